@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/playertoken"
+	"time"
+)
+
+// 鍵セットのサブコマンドの使い方
+const keysetUsage = `usage: practice-go-game-ranking keyset <command>
+
+commands:
+  rotate <file>  新しい鍵を鍵セットのファイルの先頭に追加する (ファイルがない場合は作成する)
+                 新しい鍵で署名し、古い鍵は発行済みのトークンの検証に使う (有効期限が過ぎたら削除してよい)`
+
+// プレイヤーのトークンの鍵セットのサブコマンドを実行する
+func runKeyset(args []string) {
+	if len(args) != 2 || args[0] != "rotate" {
+		fmt.Fprintln(os.Stderr, keysetUsage)
+		os.Exit(2)
+	}
+
+	kid, err := playertoken.RotateKeysetFile(args[1], time.Now())
+	if err != nil {
+		log.Fatalf("Failed to rotate keyset: %v", err)
+	}
+	fmt.Printf("added key %s to %s\n", kid, args[1])
+}
@@ -1,25 +1,41 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"crypto/rand"
 	"log"
 	"os"
 	"practice-go-game-ranking/pkg/ranking/controller"
+	"practice-go-game-ranking/pkg/ranking/domain"
 	"practice-go-game-ranking/pkg/ranking/infrastructure"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/memory"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/playertoken"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/rankindex"
 	"practice-go-game-ranking/pkg/ranking/usecase"
+	"time"
+	_ "time/tzdata"
 
-	_ "github.com/denisenkom/go-mssqldb" // SQL Server用のドライバ
-	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/mssqldialect"
 )
 
 func main() {
-	// .envファイルの読み込み
+	// .envファイルの読み込み (ファイルがない場合は環境変数をそのまま使う)
 	if err := godotenv.Load(); err != nil {
-		log.Fatalf("Error loading .env file")
+		log.Printf("No .env file loaded: %v", err)
+	}
+
+	// マイグレーションのサブコマンド
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// プレイヤーのトークンの鍵セットのサブコマンド
+	if len(os.Args) > 1 && os.Args[1] == "keyset" {
+		runKeyset(os.Args[2:])
+		return
 	}
 
 	// 環境変数からポート番号を取得
@@ -29,50 +45,304 @@ func main() {
 		port = "8080"
 	}
 
-	// データベース接続情報を環境変数から取得
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
-	dbEncrypt := os.Getenv("DB_ENCRYPT")
-
-	// データベース接続
-	dsn := "sqlserver://" + dbUser + ":" + dbPassword + "@" + dbHost + ":" + dbPort + "?database=" + dbName + "&encrypt=" + dbEncrypt
-	sqldb, err := sql.Open("sqlserver", dsn)
-	if err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err)
-	}
-	defer sqldb.Close()
-	db := bun.NewDB(sqldb, mssqldialect.New())
-
 	// Echo
 	e := echo.New()
+	e.HTTPErrorHandler = controller.HTTPErrorHandler
 
 	// バリデーター
-	validator := validator.New()
+	validator := controller.NewValidator()
+
+	// ページングのカーソルの署名鍵を環境変数から取得
+	cursorSecret := []byte(os.Getenv("CURSOR_SECRET"))
+	if len(cursorSecret) == 0 {
+		// 未設定の場合は起動ごとにランダムな鍵を使う (再起動すると発行済みのカーソルは使えなくなる)
+		log.Printf("CURSOR_SECRET is not set. Using a random key")
+		cursorSecret = make([]byte, 32)
+		if _, err := rand.Read(cursorSecret); err != nil {
+			log.Fatalf("Failed to generate cursor secret: %v", err)
+		}
+	}
+	cursorCodec := controller.NewCursorCodec(cursorSecret)
 
 	// 依存関係のセットアップ
-	userRepository := infrastructure.NewUserRepository(db)
-	userUseCase := usecase.NewUserUseCase(userRepository)
-	userController := controller.NewUserController(userUseCase, validator, db)
-	rankingRepository := infrastructure.NewRankingRepository(db)
-	rankingUseCase := usecase.NewRankingUseCase(rankingRepository)
-	rankingController := controller.NewRankingController(rankingUseCase, validator, db)
-	userRankingQueryService := infrastructure.NewUserRankingQueryService(db)
-	userRankingController := controller.NewUserRankingController(userRankingQueryService, validator)
-	userHighScoreRepository := infrastructure.NewUserHighScoreRepository(db)
-	userHighScoreUseCase := usecase.NewUserHighScoreUseCase(rankingRepository, userRepository, userHighScoreRepository)
-	userHighScoreController := controller.NewUserHighScoreController(userHighScoreUseCase, validator, db)
-
-	// エンドポイント定義とControllerのマッピング
-	e.GET("/users", userController.GetUsers)
-	e.POST("/users", userController.CreateUser)
-	e.GET("/rankings", rankingController.GetRankings)
-	e.POST("/rankings", rankingController.CreateRanking)
-	e.GET("/rankings/:ranking_id/user_high_scores", userRankingController.GetUserRanking)
-	e.PUT("/rankings/:ranking_id/user_high_scores/:user_id", userHighScoreController.StoreHighScore)
+	storage, closeStorage := newStorage(os.Getenv("STORAGE"))
+	defer closeStorage()
+	transactionManager := storage.transactionManager
+	playerTokenUseCase := usecase.NewPlayerTokenUseCase(storage.userRepository, newPlayerTokenService())
+	playerTokenController := controller.NewPlayerTokenController(playerTokenUseCase, validator)
+	userRepository := storage.userRepository
+	gameRepository := storage.gameRepository
+	rankingRepository := storage.rankingRepository
+	userHighScoreRepository := storage.userHighScoreRepository
+	userUseCase := usecase.NewUserUseCase(userRepository, rankingRepository, userHighScoreRepository, storage.userRankIndex, transactionManager)
+	userController := controller.NewUserController(userUseCase, playerTokenUseCase, validator, cursorCodec)
+	gameUseCase := usecase.NewGameUseCase(gameRepository, transactionManager)
+	gameController := controller.NewGameController(gameUseCase, validator, cursorCodec)
+	rankingUseCase := usecase.NewRankingUseCase(gameRepository, rankingRepository, storage.userRankIndex, transactionManager)
+	rankingController := controller.NewRankingController(rankingUseCase, validator, cursorCodec)
+	userRankingQueryService := storage.userRankingQueryService
+	userRankingController := controller.NewUserRankingController(userRankingQueryService, validator, cursorCodec)
+	scoreSubmissionRepository := storage.scoreSubmissionRepository
+	userHighScoreUseCase := usecase.NewUserHighScoreUseCase(rankingRepository, userRepository, userHighScoreRepository, scoreSubmissionRepository, storage.seasonRepository, userRankingQueryService, storage.userRankIndex, newSubmissionSignatureVerifier(gameRepository, storage.submissionNonceRepository), usecase.NewScorePlausibilityRules(userHighScoreRepository, scoreSubmissionRepository), transactionManager)
+	userHighScoreController := controller.NewUserHighScoreController(userHighScoreUseCase, validator)
+	scoreSubmissionUseCase := usecase.NewScoreSubmissionUseCase(rankingRepository, userRepository, scoreSubmissionRepository)
+	scoreSubmissionController := controller.NewScoreSubmissionController(scoreSubmissionUseCase, validator, cursorCodec)
+	moderationUseCase := usecase.NewModerationUseCase(rankingRepository, userRepository, userHighScoreRepository, scoreSubmissionRepository, storage.scoreReviewRepository, storage.userRankIndex, transactionManager)
+	moderationController := controller.NewModerationController(moderationUseCase, validator, cursorCodec)
+	seasonUseCase := usecase.NewSeasonUseCase(rankingRepository, storage.seasonRepository, storage.userRankIndex, transactionManager)
+	seasonController := controller.NewSeasonController(seasonUseCase, storage.seasonUserRankingQuery, validator, cursorCodec)
+
+	// APIキー (管理用のキーは最初のAPIキーの発行に使う)
+	adminAPIKey := os.Getenv("ADMIN_API_KEY")
+	if adminAPIKey == "" {
+		log.Printf("ADMIN_API_KEY is not set. Only issued API keys are accepted")
+	}
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(storage.apiKeyRepository, gameRepository, rankingRepository, adminAPIKey, transactionManager)
+	apiKeyController := controller.NewAPIKeyController(apiKeyUseCase, validator, cursorCodec)
+	auth := controller.NewAPIKeyAuth(apiKeyUseCase)
+	playerAuth := controller.NewPlayerTokenAuth(playerTokenUseCase)
+
+	// シーズンが終了したランキングを定期的に次のシーズンに進める
+	seasonCheckInterval := time.Minute
+	if value := os.Getenv("SEASON_CHECK_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Fatalf("Invalid SEASON_CHECK_INTERVAL: %q", value)
+		}
+		seasonCheckInterval = interval
+	}
+	go runSeasonScheduler(context.Background(), seasonUseCase, seasonCheckInterval)
+
+	// エンドポイント定義とControllerのマッピング (各ルートで必要なAPIキーのスコープを宣言する)
+	e.GET("/users", userController.GetUsers, auth.RequireAllGames(domain.ScopeRead))
+	e.POST("/users", userController.CreateUser, auth.Require(domain.ScopeScoresWrite))
+	e.DELETE("/users/:user_id", userController.DeleteUser, auth.RequireAllGames(domain.ScopeRankingsAdmin))
+	e.POST("/users/:user_id/restore", userController.RestoreUser, auth.RequireAllGames(domain.ScopeRankingsAdmin))
+	e.POST("/users/:user_id/player_tokens", playerTokenController.IssuePlayerToken, auth.Require(domain.ScopePlayersIssue))
+	e.GET("/games", gameController.GetGames, auth.RequireAllGames(domain.ScopeRead))
+	e.POST("/games", gameController.CreateGame, auth.RequireAllGames(domain.ScopeRankingsAdmin))
+	e.GET("/games/:game_id", gameController.GetGame, auth.Require(domain.ScopeRead))
+	e.PUT("/games/:game_id", gameController.UpdateGame, auth.Require(domain.ScopeRankingsAdmin))
+	e.DELETE("/games/:game_id", gameController.DeleteGame, auth.Require(domain.ScopeRankingsAdmin))
+	e.POST("/games/:game_id/submission_secret", gameController.RotateSubmissionSecret, auth.Require(domain.ScopeKeysAdmin))
+	e.DELETE("/games/:game_id/submission_secret", gameController.DeleteSubmissionSecret, auth.Require(domain.ScopeKeysAdmin))
+	e.GET("/games/:game_id/rankings", rankingController.GetGameRankings, auth.Require(domain.ScopeRead))
+	e.GET("/rankings", rankingController.GetRankings, auth.RequireAllGames(domain.ScopeRead))
+	e.POST("/rankings", rankingController.CreateRanking, auth.Require(domain.ScopeRankingsAdmin))
+	e.DELETE("/rankings/:ranking_id", rankingController.DeleteRanking, auth.Require(domain.ScopeRankingsAdmin))
+	e.PUT("/rankings/:ranking_id/plausibility_rules", rankingController.UpdatePlausibilityRules, auth.Require(domain.ScopeRankingsAdmin))
+	e.GET("/rankings/:ranking_id/seasons", seasonController.GetSeasons, auth.Require(domain.ScopeRead))
+	e.GET("/rankings/:ranking_id/seasons/:season/user_high_scores", seasonController.GetSeasonUserRanking, auth.Require(domain.ScopeRead))
+	e.GET("/rankings/:ranking_id/user_high_scores", userRankingController.GetUserRanking, auth.Require(domain.ScopeRead))
+	e.GET("/rankings/:ranking_id/user_high_scores/:user_id", userRankingController.GetUserStanding, auth.Require(domain.ScopeRead))
+	e.PUT("/rankings/:ranking_id/user_high_scores/:user_id", userHighScoreController.StoreHighScore, auth.Require(domain.ScopeScoresWrite), playerAuth.RequireSubject("user_id"))
+	e.DELETE("/rankings/:ranking_id/user_high_scores/:user_id", userHighScoreController.DeleteHighScore, auth.Require(domain.ScopeRankingsAdmin))
+	e.GET("/rankings/:ranking_id/user_high_scores/:user_id/around", userRankingController.GetUserRankingAround, auth.Require(domain.ScopeRead))
+	e.GET("/rankings/:ranking_id/users/:user_id/submissions", scoreSubmissionController.GetScoreSubmissions, auth.Require(domain.ScopeRead), playerAuth.RequireSubject("user_id"))
+	e.GET("/moderation/queue", moderationController.GetQueue, auth.RequireAllGames(domain.ScopeRankingsAdmin))
+	e.POST("/moderation/scores/:submission_id/approve", moderationController.ApproveScore, auth.RequireAllGames(domain.ScopeRankingsAdmin))
+	e.POST("/moderation/scores/:submission_id/reject", moderationController.RejectScore, auth.RequireAllGames(domain.ScopeRankingsAdmin))
+	e.GET("/api_keys", apiKeyController.GetAPIKeys, auth.RequireAllGames(domain.ScopeKeysAdmin))
+	e.POST("/api_keys", apiKeyController.IssueAPIKey, auth.RequireAllGames(domain.ScopeKeysAdmin))
+	e.DELETE("/api_keys/:api_key_id", apiKeyController.RevokeAPIKey, auth.RequireAllGames(domain.ScopeKeysAdmin))
 
 	// サーバを起動
 	e.Logger.Fatal(e.Start(":" + port))
 }
+
+// 起動時と一定間隔ごとに、シーズンが終了したランキングを次のシーズンに進める
+func runSeasonScheduler(ctx context.Context, seasonUseCase *usecase.SeasonUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		seasons, err := seasonUseCase.RotateSeasons(ctx, time.Now())
+		if err != nil {
+			log.Printf("Failed to rotate some seasons: %v", err)
+		}
+		for _, season := range seasons {
+			log.Printf("Season %d of ranking %d ended with %d users", season.Season, season.RankingID, season.TotalUsers)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 環境変数の鍵セットでプレイヤーのトークンの発行と検証を生成する
+func newPlayerTokenService() *playertoken.Service {
+	// トークンの有効期限 (未設定の場合は15分)
+	ttl := 15 * time.Minute
+	if value := os.Getenv("PLAYER_TOKEN_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid PLAYER_TOKEN_TTL: %q", value)
+		}
+		ttl = parsed
+	}
+
+	// 鍵セットのファイル
+	path := os.Getenv("PLAYER_TOKEN_KEYSET")
+	if path == "" {
+		// 未設定の場合は起動ごとにランダムな鍵を使う (再起動すると発行済みのトークンは使えなくなる)
+		log.Printf("PLAYER_TOKEN_KEYSET is not set. Using a random key")
+		keyset, err := playertoken.NewEphemeralKeyset()
+		if err != nil {
+			log.Fatalf("Failed to generate player token key: %v", err)
+		}
+		return playertoken.NewService(keyset, ttl)
+	}
+	keyset, err := playertoken.LoadKeyset(path)
+	if err != nil {
+		log.Fatalf("Failed to load player token keyset: %v", err)
+	}
+	return playertoken.NewService(keyset, ttl)
+}
+
+// 環境変数の許容範囲でスコアの送信の署名の検証を生成する
+func newSubmissionSignatureVerifier(gameRepository domain.GameRepositoryInterface, nonceRepository domain.SubmissionNonceRepositoryInterface) *usecase.SubmissionSignatureVerifier {
+	// タイムスタンプと現在時刻のずれの許容範囲 (未設定の場合は5分)
+	window := 5 * time.Minute
+	if value := os.Getenv("SUBMISSION_SIGNATURE_WINDOW"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid SUBMISSION_SIGNATURE_WINDOW: %q", value)
+		}
+		window = parsed
+	}
+	return usecase.NewSubmissionSignatureVerifier(gameRepository, nonceRepository, window)
+}
+
+// 永続化先の実装
+type storage struct {
+	transactionManager      usecase.TransactionManagerInterface
+	userRepository          domain.UserRepositoryInterface
+	gameRepository          domain.GameRepositoryInterface
+	rankingRepository       domain.RankingRepositoryInterface
+	userHighScoreRepository domain.UserHighScoreRepositoryInterface
+	userRankingQueryService usecase.UserRankingQueryServiceInterface
+	seasonRepository        domain.SeasonRepositoryInterface
+	seasonUserRankingQuery  usecase.SeasonUserRankingQueryServiceInterface
+
+	// スコアの送信履歴と審査
+	scoreSubmissionRepository domain.ScoreSubmissionRepositoryInterface
+	scoreReviewRepository     domain.ScoreReviewRepositoryInterface
+
+	// スコアの送信の署名の使用済みのナンス
+	submissionNonceRepository domain.SubmissionNonceRepositoryInterface
+
+	// APIキー
+	apiKeyRepository domain.APIKeyRepositoryInterface
+
+	// ランク付けのインデックス (使わない場合はnil)
+	userRankIndex usecase.UserRankIndexInterface
+}
+
+// 環境変数STORAGEに応じて永続化先を生成する (memoryの場合はインメモリ、それ以外はDB_DIALECTのデータベース)
+// 返却した関数で接続を閉じる
+func newStorage(kind string) (*storage, func()) {
+	// インメモリ (データベースなしでローカル実行する。データはプロセスの終了で消える)
+	if kind == "memory" {
+		log.Printf("Using in-memory storage")
+		store := memory.NewStore()
+		return &storage{
+			transactionManager:      memory.NewTransactionManager(store),
+			userRepository:          memory.NewUserRepository(store),
+			gameRepository:          memory.NewGameRepository(store),
+			rankingRepository:       memory.NewRankingRepository(store),
+			userHighScoreRepository: memory.NewUserHighScoreRepository(store),
+			userRankingQueryService: memory.NewUserRankingQueryService(store),
+			seasonRepository:        memory.NewSeasonRepository(store),
+			seasonUserRankingQuery:  memory.NewSeasonUserRankingQueryService(store),
+
+			scoreSubmissionRepository: memory.NewScoreSubmissionRepository(store),
+			scoreReviewRepository:     memory.NewScoreReviewRepository(store),
+			submissionNonceRepository: memory.NewSubmissionNonceRepository(store),
+			apiKeyRepository:          memory.NewAPIKeyRepository(store),
+		}, func() {}
+	}
+
+	// データベース接続
+	db := openDB()
+
+	// スキーマが古い場合は起動しない
+	migrator, err := infrastructure.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	if err := migrator.EnsureUpToDate(context.Background()); err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+
+	storage := &storage{
+		transactionManager:      infrastructure.NewTransactionManager(db),
+		userRepository:          infrastructure.NewUserRepository(db),
+		gameRepository:          infrastructure.NewGameRepository(db),
+		rankingRepository:       infrastructure.NewRankingRepository(db),
+		userHighScoreRepository: infrastructure.NewUserHighScoreRepository(db),
+		userRankingQueryService: infrastructure.NewUserRankingQueryService(db),
+		seasonRepository:        infrastructure.NewSeasonRepository(db),
+		seasonUserRankingQuery:  infrastructure.NewSeasonUserRankingQueryService(db),
+
+		scoreSubmissionRepository: infrastructure.NewScoreSubmissionRepository(db),
+		scoreReviewRepository:     infrastructure.NewScoreReviewRepository(db),
+		submissionNonceRepository: infrastructure.NewSubmissionNonceRepository(db),
+		apiKeyRepository:          infrastructure.NewAPIKeyRepository(db),
+	}
+
+	// ランク付けのインデックス (複数のプロセスで同じデータベースを使う場合は他のプロセスの更新が反映されないためoffにする)
+	if os.Getenv("RANK_INDEX") != "off" {
+		index := rankindex.NewIndex()
+		started := time.Now()
+		count, err := infrastructure.WarmRankIndex(context.Background(), db, index)
+		if err != nil {
+			log.Fatalf("Failed to warm rank index: %v", err)
+		}
+		log.Printf("Rank index warmed with %d user high scores in %v", count, time.Since(started))
+		storage.userRankingQueryService = rankindex.NewUserRankingQueryService(index, storage.rankingRepository)
+		storage.userRankIndex = index
+	}
+
+	return storage, func() { db.Close() }
+}
+
+// 環境変数の接続先のデータベースに接続する
+func openDB() *bun.DB {
+	// データベースの種類と接続先を環境変数から取得 (未設定の場合はSQL Server)
+	dialect := infrastructure.Dialect(os.Getenv("DB_DIALECT"))
+	if dialect == "" {
+		dialect = infrastructure.DialectSQLServer
+	}
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = dsnFromEnv(dialect)
+	}
+
+	// データベース接続
+	db, err := infrastructure.OpenDB(dialect, dsn)
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	return db
+}
+
+// 個別の環境変数からデータベースの接続先を組み立てる (SQLiteの場合はDB_NAMEをファイル名とする)
+func dsnFromEnv(dialect infrastructure.Dialect) string {
+	dbUser := os.Getenv("DB_USER")
+	dbPassword := os.Getenv("DB_PASSWORD")
+	dbHost := os.Getenv("DB_HOST")
+	dbPort := os.Getenv("DB_PORT")
+	dbName := os.Getenv("DB_NAME")
+	dbEncrypt := os.Getenv("DB_ENCRYPT")
+
+	switch dialect {
+	case infrastructure.DialectPostgres:
+		return "postgres://" + dbUser + ":" + dbPassword + "@" + dbHost + ":" + dbPort + "/" + dbName + "?sslmode=" + dbEncrypt
+	case infrastructure.DialectSQLite:
+		return "file:" + dbName
+	default:
+		return "sqlserver://" + dbUser + ":" + dbPassword + "@" + dbHost + ":" + dbPort + "?database=" + dbName + "&encrypt=" + dbEncrypt
+	}
+}
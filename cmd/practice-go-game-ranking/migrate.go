@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"practice-go-game-ranking/pkg/ranking/infrastructure"
+	"strconv"
+)
+
+// マイグレーションのサブコマンドの使い方
+const migrateUsage = `usage: practice-go-game-ranking migrate <command>
+
+commands:
+  up            未適用のマイグレーションをすべて適用する
+  down          最後に適用したマイグレーションを1つ取り消す
+  status        マイグレーションの適用状況を表示する
+  to <version>  指定したバージョンまで適用または取り消す (0の場合はすべて取り消す)`
+
+// マイグレーションのサブコマンドを実行する
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	// データベース接続
+	db := openDB()
+	defer db.Close()
+	migrator, err := infrastructure.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	ctx := context.Background()
+
+	// コマンドに応じて実行したマイグレーション
+	var executed []infrastructure.Migration
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		executed, err = migrator.Up(ctx)
+	case args[0] == "down" && len(args) == 1:
+		executed, err = migrator.Down(ctx)
+	case args[0] == "to" && len(args) == 2:
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			log.Fatalf("Invalid version: %q", args[1])
+		}
+		executed, err = migrator.To(ctx, version)
+	case args[0] == "status" && len(args) == 1:
+		printMigrationStatus(ctx, migrator)
+		return
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	// 途中で失敗した場合もそれまでに実行したマイグレーションを表示する
+	for _, migration := range executed {
+		fmt.Printf("migrated %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+	printMigrationStatus(ctx, migrator)
+}
+
+// マイグレーションの適用状況を表示する
+func printMigrationStatus(ctx context.Context, migrator *infrastructure.Migrator) {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		log.Fatalf("Failed to fetch migration status: %v", err)
+	}
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = "applied at " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIキーを受け取るヘッダー
+const apiKeyHeader = "X-API-Key"
+
+// 認証したAPIキーを保持するEchoのコンテキストのキー
+const apiKeyContextKey = "api_key"
+
+// APIキーの認証と認可
+type APIKeyAuth struct {
+	apiKeyUseCase *usecase.APIKeyUseCase
+}
+
+// APIキーの認証と認可を生成する
+func NewAPIKeyAuth(u *usecase.APIKeyUseCase) *APIKeyAuth {
+	return &APIKeyAuth{
+		apiKeyUseCase: u,
+	}
+}
+
+// ルートが必要とするスコープを宣言するミドルウェア
+// パスパラメタのゲームまたはランキングを対象として、ゲームを限定したキーの認可も行う
+func (auth *APIKeyAuth) Require(scope domain.Scope) echo.MiddlewareFunc {
+	return auth.middleware(scope, false)
+}
+
+// 全てのゲームに影響するルートが必要とするスコープを宣言するミドルウェア (ゲームを限定したキーは使えない)
+func (auth *APIKeyAuth) RequireAllGames(scope domain.Scope) echo.MiddlewareFunc {
+	return auth.middleware(scope, true)
+}
+
+// APIキーを認証し、スコープと対象を認可するミドルウェアを生成する
+func (auth *APIKeyAuth) middleware(scope domain.Scope, allGames bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			// ヘッダーのAPIキーを認証
+			apiKey, err := auth.apiKeyUseCase.Authenticate(ctx, c.Request().Header.Get(apiKeyHeader))
+
+			// エラーハンドリング
+			if err != nil {
+				log.Printf("[APIKeyAuth] Failed to authenticate api key: %v", err)
+				return err
+			}
+
+			// パスパラメタの対象をスコープの範囲で操作できるか認可
+			// (数値でないパラメタはコントローラーのバリデーションでエラーにする)
+			target := usecase.AuthorizationTarget{AllGames: allGames}
+			target.GameID, _ = strconv.Atoi(c.Param("game_id"))
+			target.RankingID, _ = strconv.Atoi(c.Param("ranking_id"))
+			if err := auth.apiKeyUseCase.Authorize(ctx, apiKey, scope, target); err != nil {
+				log.Printf("[APIKeyAuth] Failed to authorize api key %q: %v", apiKey.Prefix, err)
+				return err
+			}
+
+			// 認証したAPIキーをコントローラーに渡す
+			c.Set(apiKeyContextKey, apiKey)
+			return next(c)
+		}
+	}
+}
+
+// リクエストボディで指定されたゲームを認証したAPIキーで操作できることを確認する (認証していない場合は確認しない)
+func authorizeGame(c echo.Context, gameID int) error {
+	apiKey, ok := c.Get(apiKeyContextKey).(*domain.APIKey)
+	if !ok {
+		return nil
+	}
+	return apiKey.AuthorizeGame(gameID)
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// APIキーコントローラー
+type APIKeyController struct {
+	apiKeyUseCase *usecase.APIKeyUseCase
+	validator     *validator.Validate
+	cursorCodec   *CursorCodec
+}
+
+// コントローラーを生成する
+func NewAPIKeyController(u *usecase.APIKeyUseCase, v *validator.Validate, cc *CursorCodec) *APIKeyController {
+	return &APIKeyController{
+		apiKeyUseCase: u,
+		validator:     v,
+		cursorCodec:   cc,
+	}
+}
+
+// APIキー一覧を取得する
+func (apiKeyController *APIKeyController) GetAPIKeys(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetAPIKeysRequest struct {
+		Cursor string `json:"cursor" query:"cursor"`
+		Limit  int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getAPIKeysRequest := new(GetAPIKeysRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getAPIKeysRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(apiKeyController.validator, getAPIKeysRequest); err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成
+	cursor, err := apiKeyController.cursorCodec.decodeIDCursor(getAPIKeysRequest.Cursor, apiKeyCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getAPIKeysRequest.Limit)
+	if err != nil {
+		return err
+	}
+
+	// APIキー一覧を取得
+	apiKeys, err := apiKeyController.apiKeyUseCase.GetAPIKeys(c.Request().Context(), page)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyController.GetAPIKeys] Failed to fetch api keys: %v", err)
+		return err
+	}
+
+	// 前後のページへのリンクを付けてAPIキー一覧を返却する
+	response, err := newIDPageResponse(c, apiKeyController.cursorCodec, apiKeyCursorKind, apiKeys, func(apiKey usecase.APIKeyDto) int { return apiKey.ID })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// APIキーを発行する
+func (apiKeyController *APIKeyController) IssueAPIKey(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type IssueAPIKeyRequest struct {
+		Name   string   `json:"name" validate:"required,max=50"`
+		Scopes []string `json:"scopes" validate:"required,min=1"`
+		GameID *int     `json:"game_id" validate:"omitempty,min=1"`
+	}
+
+	// リクエストを受ける構造体を生成
+	issueAPIKeyRequest := new(IssueAPIKeyRequest)
+
+	// リクエストボディをマッピング
+	if err := c.Bind(issueAPIKeyRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(apiKeyController.validator, issueAPIKeyRequest); err != nil {
+		return err
+	}
+
+	// APIキーを発行
+	apiKey, err := apiKeyController.apiKeyUseCase.IssueAPIKey(c.Request().Context(), usecase.IssueAPIKeyCommand{
+		Name:   issueAPIKeyRequest.Name,
+		Scopes: issueAPIKeyRequest.Scopes,
+		GameID: issueAPIKeyRequest.GameID,
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyController.IssueAPIKey] Failed to issue api key: %v", err)
+		return err
+	}
+
+	// 発行したAPIキーを返却する (キーの文字列はこのレスポンスでのみ返す)
+	return c.JSON(http.StatusCreated, apiKey)
+}
+
+// APIキーを失効させる
+func (apiKeyController *APIKeyController) RevokeAPIKey(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type RevokeAPIKeyRequest struct {
+		APIKeyID int `json:"api_key_id" param:"api_key_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	revokeAPIKeyRequest := new(RevokeAPIKeyRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(revokeAPIKeyRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(apiKeyController.validator, revokeAPIKeyRequest); err != nil {
+		return err
+	}
+
+	// APIキーを失効
+	err := apiKeyController.apiKeyUseCase.RevokeAPIKey(c.Request().Context(), revokeAPIKeyRequest.APIKeyID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyController.RevokeAPIKey] Failed to revoke api key: %v", err)
+		return err
+	}
+
+	// 失効した場合は本文なしで返却する
+	return c.NoContent(http.StatusNoContent)
+}
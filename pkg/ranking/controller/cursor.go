@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"strings"
+	"time"
+)
+
+// カーソルの種類
+const (
+	userCursorKind        = "users"
+	gameCursorKind        = "games"
+	rankingCursorKind     = "rankings"
+	userRankingCursorKind = "user_ranking"
+	seasonCursorKind      = "seasons"
+	apiKeyCursorKind      = "api_keys"
+
+	// スコアの送信履歴
+	scoreSubmissionCursorKind = "score_submissions"
+
+	// 審査待ちの送信履歴
+	moderationQueueCursorKind = "moderation_queue"
+
+	// 終了したシーズンのユーザーランキング (ランクをIDとする)
+	seasonUserRankingCursorKind = "season_user_ranking"
+)
+
+// 不正なカーソルのエラーコード
+const invalidCursorCode = "invalid_cursor"
+
+// IDをキーとしたカーソルの中身
+type idCursorPayload struct {
+	Kind      string               `json:"k"`
+	ID        int                  `json:"id"`
+	Direction domain.PageDirection `json:"d"`
+}
+
+// ユーザーランキングのカーソルの中身
+type userRankingCursorPayload struct {
+	Kind      string               `json:"k"`
+	RankingID int                  `json:"r"`
+	OrderBy   string               `json:"o"`
+	Score     domain.Score         `json:"s"`
+	Timestamp time.Time            `json:"t"`
+	UserID    int                  `json:"u"`
+	Direction domain.PageDirection `json:"d"`
+}
+
+// 改ざんを検知できる不透明なカーソルを生成・検証する
+type CursorCodec struct {
+	secret []byte
+}
+
+// カーソルコーデックを生成する
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{
+		secret: secret,
+	}
+}
+
+// カーソルの中身を署名付きの文字列に変換する
+func (codec *CursorCodec) Encode(payload interface{}) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	encodedBody := base64.RawURLEncoding.EncodeToString(body)
+	return encodedBody + "." + base64.RawURLEncoding.EncodeToString(codec.sign(encodedBody)), nil
+}
+
+// 署名を検証してカーソルの中身を取り出す
+func (codec *CursorCodec) Decode(cursor string, payload interface{}) error {
+	// 本文と署名に分割する
+	encodedBody, encodedSignature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return domain.NewValidationError(invalidCursorCode, "カーソルの形式が不正です。")
+	}
+
+	// 署名を検証する
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, codec.sign(encodedBody)) {
+		return domain.NewValidationError(invalidCursorCode, "カーソルの署名が不正です。")
+	}
+
+	// 本文を取り出す
+	body, err := base64.RawURLEncoding.DecodeString(encodedBody)
+	if err != nil || json.Unmarshal(body, payload) != nil {
+		return domain.NewValidationError(invalidCursorCode, "カーソルの形式が不正です。")
+	}
+	return nil
+}
+
+// IDをキーとしたカーソルを取り出す (空文字の場合は先頭から)
+func (codec *CursorCodec) decodeIDCursor(cursor string, kind string) (idCursorPayload, error) {
+	var payload idCursorPayload
+	if cursor == "" {
+		return payload, nil
+	}
+	if err := codec.Decode(cursor, &payload); err != nil {
+		return payload, err
+	}
+
+	// 別の一覧のカーソルは使えない
+	if payload.Kind != kind || (payload.Direction != domain.PageNext && payload.Direction != domain.PagePrev) {
+		return payload, domain.NewValidationError(invalidCursorCode, "この一覧には使えないカーソルです。")
+	}
+	return payload, nil
+}
+
+// 文字列を署名する
+func (codec *CursorCodec) sign(body string) []byte {
+	mac := hmac.New(sha256.New, codec.secret)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
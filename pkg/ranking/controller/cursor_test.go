@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// カーソルの署名と検証
+func TestCursorCodec(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+
+	// 生成したカーソルは取り出せる
+	cursor, err := codec.Encode(idCursorPayload{Kind: userCursorKind, ID: 42, Direction: domain.PageNext})
+	require.NoError(t, err)
+	payload, err := codec.decodeIDCursor(cursor, userCursorKind)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, payload.ID)
+	assert.Equal(t, domain.PageNext, payload.Direction)
+
+	// 別の一覧のカーソルは使えない
+	_, err = codec.decodeIDCursor(cursor, rankingCursorKind)
+	assert.ErrorIs(t, err, domain.ErrValidation, "Expected error for cursor of another list")
+
+	// 別の鍵で署名したカーソルは使えない
+	_, err = NewCursorCodec([]byte("other")).decodeIDCursor(cursor, userCursorKind)
+	assert.ErrorIs(t, err, domain.ErrValidation, "Expected error for cursor signed with another key")
+
+	// 改ざんしたカーソルは使えない
+	_, err = codec.decodeIDCursor("x"+cursor, userCursorKind)
+	assert.ErrorIs(t, err, domain.ErrValidation, "Expected error for tampered cursor")
+}
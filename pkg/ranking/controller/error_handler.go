@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// コントローラーから返されたエラーをRFC 7807形式のレスポンスに変換する (EchoのHTTPErrorHandler)
+func HTTPErrorHandler(err error, c echo.Context) {
+	// 既にレスポンスを返している場合は何もしない
+	if c.Response().Committed {
+		return
+	}
+
+	// エラーをエラーレスポンスに変換する
+	problem := toProblem(err)
+	problem.Instance = c.Request().URL.Path
+
+	// エラーレスポンスを返却する
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+		err = c.JSON(problem.Status, problem)
+	}
+	if err != nil {
+		log.Printf("[HTTPErrorHandler] Failed to send error response: %v", err)
+	}
+}
+
+// エラーをエラーレスポンスに変換する
+func toProblem(err error) *Problem {
+	// ドメインエラーは種類に応じたステータスコードに変換する
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return NewProblem(domainErrorStatus(domainErr), domainErr.Code, domainErr.Message)
+	}
+
+	// リクエストパラメタのバリデーションエラーはフィールドごとのエラーを含める
+	var validationErr *RequestValidationError
+	if errors.As(err, &validationErr) {
+		problem := NewProblem(http.StatusUnprocessableEntity, "validation_failed", validationErr.Error())
+		problem.Errors = validationErr.Errors
+		return problem
+	}
+
+	// Echoのエラー (ルーティングやリクエストボディのマッピングなど) はそのステータスコードを使う
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return NewProblem(httpErr.Code, statusCode(httpErr.Code), fmt.Sprint(httpErr.Message))
+	}
+
+	// それ以外は内部エラーとして詳細を返さない
+	return NewProblem(http.StatusInternalServerError, "internal_error", "サーバー内部でエラーが発生しました。")
+}
+
+// ドメインエラーの種類をステータスコードに変換する
+func domainErrorStatus(err *domain.Error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ステータスコードから機械可読なエラーコードを生成する (例: 404 -> not_found)
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
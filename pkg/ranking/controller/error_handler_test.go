@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// エラーの種類に応じたステータスコードとエラーコードへの変換
+func TestToProblem(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{domain.NewNotFoundError("ranking_not_found", "not found"), http.StatusNotFound, "ranking_not_found"},
+		{domain.NewConflictError("ranking_name_conflict", "conflict"), http.StatusConflict, "ranking_name_conflict"},
+		{fmt.Errorf("wrapped: %w", domain.NewValidationError("invalid_user_name", "invalid")), http.StatusUnprocessableEntity, "invalid_user_name"},
+		{domain.NewUnauthorizedError("invalid_api_key", "unauthorized"), http.StatusUnauthorized, "invalid_api_key"},
+		{domain.NewForbiddenError("insufficient_scope", "forbidden"), http.StatusForbidden, "insufficient_scope"},
+		{&RequestValidationError{}, http.StatusUnprocessableEntity, "validation_failed"},
+		{echo.ErrNotFound, http.StatusNotFound, "not_found"},
+		{errors.New("boom"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		problem := toProblem(tt.err)
+		assert.Equal(t, tt.status, problem.Status, "Unexpected status for %v", tt.err)
+		assert.Equal(t, tt.code, problem.Code, "Unexpected code for %v", tt.err)
+	}
+}
+
+// バリデーションエラーはフィールドごとのエラーを含むproblem+jsonで返る
+func TestHTTPErrorHandlerValidationProblem(t *testing.T) {
+	type request struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/users", nil), rec)
+
+	HTTPErrorHandler(validateRequest(NewValidator(), &request{}), c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "/users", problem.Instance)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "name", problem.Errors[0].Field)
+	assert.Equal(t, "required", problem.Errors[0].Code)
+}
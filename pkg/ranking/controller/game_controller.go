@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// ゲームコントローラー
+type GameController struct {
+	gameUseCase *usecase.GameUseCase
+	validator   *validator.Validate
+	cursorCodec *CursorCodec
+}
+
+// コントローラーを生成する
+func NewGameController(u *usecase.GameUseCase, v *validator.Validate, cc *CursorCodec) *GameController {
+	return &GameController{
+		gameUseCase: u,
+		validator:   v,
+		cursorCodec: cc,
+	}
+}
+
+// ゲーム一覧を取得する
+func (gameController *GameController) GetGames(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetGamesRequest struct {
+		Cursor string `json:"cursor" query:"cursor"`
+		Limit  int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getGamesRequest := new(GetGamesRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getGamesRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(gameController.validator, getGamesRequest); err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成
+	cursor, err := gameController.cursorCodec.decodeIDCursor(getGamesRequest.Cursor, gameCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getGamesRequest.Limit)
+	if err != nil {
+		return err
+	}
+
+	// ゲーム一覧を取得
+	games, err := gameController.gameUseCase.GetGames(c.Request().Context(), page)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameController.GetGames] Failed to fetch games: %v", err)
+		return err
+	}
+
+	// 前後のページへのリンクを付けてゲーム一覧を返却する
+	response, err := newIDPageResponse(c, gameController.cursorCodec, gameCursorKind, games, func(game usecase.GameDto) int { return game.ID })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// ゲームを取得する
+func (gameController *GameController) GetGame(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetGameRequest struct {
+		GameID int `json:"game_id" param:"game_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getGameRequest := new(GetGameRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getGameRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(gameController.validator, getGameRequest); err != nil {
+		return err
+	}
+
+	// ゲームを取得
+	game, err := gameController.gameUseCase.GetGame(c.Request().Context(), getGameRequest.GameID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameController.GetGame] Failed to fetch game: %v", err)
+		return err
+	}
+
+	// ゲームを返却する
+	return c.JSON(http.StatusOK, game)
+}
+
+// ゲームを新規登録する
+func (gameController *GameController) CreateGame(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type CreateGameRequest struct {
+		Name string `json:"name" validate:"required,max=50"`
+	}
+
+	// リクエストを受ける構造体を生成
+	createGameRequest := new(CreateGameRequest)
+
+	// リクエストボディをマッピング
+	if err := c.Bind(createGameRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(gameController.validator, createGameRequest); err != nil {
+		return err
+	}
+
+	// ゲームを新規登録
+	game, err := gameController.gameUseCase.CreateGame(c.Request().Context(), createGameRequest.Name)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameController.CreateGame] Failed to create game: %v", err)
+		return err
+	}
+
+	// 登録したゲームを返却する
+	return c.JSON(http.StatusCreated, game)
+}
+
+// ゲーム名を変更する
+func (gameController *GameController) UpdateGame(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type UpdateGameRequest struct {
+		GameID int    `json:"game_id" param:"game_id" validate:"required"`
+		Name   string `json:"name" validate:"required,max=50"`
+	}
+
+	// リクエストを受ける構造体を生成
+	updateGameRequest := new(UpdateGameRequest)
+
+	// リクエストパラメタとボディをマッピング
+	if err := c.Bind(updateGameRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(gameController.validator, updateGameRequest); err != nil {
+		return err
+	}
+
+	// ゲーム名を変更
+	game, err := gameController.gameUseCase.UpdateGame(c.Request().Context(), updateGameRequest.GameID, updateGameRequest.Name)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameController.UpdateGame] Failed to update game: %v", err)
+		return err
+	}
+
+	// 変更したゲームを返却する
+	return c.JSON(http.StatusOK, game)
+}
+
+// ゲームを削除する
+func (gameController *GameController) DeleteGame(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type DeleteGameRequest struct {
+		GameID int `json:"game_id" param:"game_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	deleteGameRequest := new(DeleteGameRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(deleteGameRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(gameController.validator, deleteGameRequest); err != nil {
+		return err
+	}
+
+	// ゲームを削除
+	err := gameController.gameUseCase.DeleteGame(c.Request().Context(), deleteGameRequest.GameID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameController.DeleteGame] Failed to delete game: %v", err)
+		return err
+	}
+
+	// 削除した場合は本文なしで返却する
+	return c.NoContent(http.StatusNoContent)
+}
+
+// スコアの送信の署名に使う秘密鍵を生成し直す
+func (gameController *GameController) RotateSubmissionSecret(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type RotateSubmissionSecretRequest struct {
+		GameID int `json:"game_id" param:"game_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	rotateSubmissionSecretRequest := new(RotateSubmissionSecretRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(rotateSubmissionSecretRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(gameController.validator, rotateSubmissionSecretRequest); err != nil {
+		return err
+	}
+
+	// 秘密鍵を生成し直す
+	secret, err := gameController.gameUseCase.RotateSubmissionSecret(c.Request().Context(), rotateSubmissionSecretRequest.GameID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameController.RotateSubmissionSecret] Failed to rotate submission secret: %v", err)
+		return err
+	}
+
+	// 生成した秘密鍵を返却する
+	return c.JSON(http.StatusCreated, secret)
+}
+
+// スコアの送信の署名に使う秘密鍵を削除する
+func (gameController *GameController) DeleteSubmissionSecret(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type DeleteSubmissionSecretRequest struct {
+		GameID int `json:"game_id" param:"game_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	deleteSubmissionSecretRequest := new(DeleteSubmissionSecretRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(deleteSubmissionSecretRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(gameController.validator, deleteSubmissionSecretRequest); err != nil {
+		return err
+	}
+
+	// 秘密鍵を削除
+	err := gameController.gameUseCase.DeleteSubmissionSecret(c.Request().Context(), deleteSubmissionSecretRequest.GameID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameController.DeleteSubmissionSecret] Failed to delete submission secret: %v", err)
+		return err
+	}
+
+	// 削除した場合は本文なしで返却する
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// モデレーションコントローラー
+type ModerationController struct {
+	moderationUseCase *usecase.ModerationUseCase
+	validator         *validator.Validate
+	cursorCodec       *CursorCodec
+}
+
+// コントローラーを生成する
+func NewModerationController(u *usecase.ModerationUseCase, v *validator.Validate, cc *CursorCodec) *ModerationController {
+	return &ModerationController{
+		moderationUseCase: u,
+		validator:         v,
+		cursorCodec:       cc,
+	}
+}
+
+// 審査待ちの送信履歴を取得する
+func (moderationController *ModerationController) GetQueue(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetQueueRequest struct {
+		Cursor string `json:"cursor" query:"cursor"`
+		Limit  int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getQueueRequest := new(GetQueueRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getQueueRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(moderationController.validator, getQueueRequest); err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成
+	cursor, err := moderationController.cursorCodec.decodeIDCursor(getQueueRequest.Cursor, moderationQueueCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getQueueRequest.Limit)
+	if err != nil {
+		return err
+	}
+
+	// 審査待ちの送信履歴を取得
+	submissions, err := moderationController.moderationUseCase.GetQueue(c.Request().Context(), page)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ModerationController.GetQueue] Failed to fetch moderation queue: %v", err)
+		return err
+	}
+
+	// 前後のページへのリンクを付けて審査待ちの送信履歴を返却する
+	response, err := newIDPageResponse(c, moderationController.cursorCodec, moderationQueueCursorKind, submissions, func(submission usecase.ScoreSubmissionDto) int { return submission.ID })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// 審査待ちの送信を承認する
+func (moderationController *ModerationController) ApproveScore(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type ApproveScoreRequest struct {
+		SubmissionID int    `json:"submission_id" param:"submission_id" validate:"required"`
+		Reason       string `json:"reason" validate:"max=500"`
+	}
+
+	// リクエストを受ける構造体を生成
+	approveScoreRequest := new(ApproveScoreRequest)
+
+	// リクエストパラメタとリクエストボディをマッピング
+	if err := c.Bind(approveScoreRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(moderationController.validator, approveScoreRequest); err != nil {
+		return err
+	}
+
+	// 審査したAPIキー
+	reviewer, err := reviewerAPIKey(c)
+	if err != nil {
+		return err
+	}
+
+	// 送信を承認
+	review, err := moderationController.moderationUseCase.ApproveScore(c.Request().Context(), usecase.ReviewScoreCommand{
+		SubmissionID: approveScoreRequest.SubmissionID,
+		Reviewer:     reviewer,
+		Reason:       approveScoreRequest.Reason,
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ModerationController.ApproveScore] Failed to approve score: %v", err)
+		return err
+	}
+
+	// 記録した審査を返却する
+	return c.JSON(http.StatusOK, review)
+}
+
+// 審査待ちの送信を却下する
+func (moderationController *ModerationController) RejectScore(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type RejectScoreRequest struct {
+		SubmissionID int    `json:"submission_id" param:"submission_id" validate:"required"`
+		Reason       string `json:"reason" validate:"max=500"`
+		ShadowBan    bool   `json:"shadow_ban"`
+	}
+
+	// リクエストを受ける構造体を生成
+	rejectScoreRequest := new(RejectScoreRequest)
+
+	// リクエストパラメタとリクエストボディをマッピング
+	if err := c.Bind(rejectScoreRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(moderationController.validator, rejectScoreRequest); err != nil {
+		return err
+	}
+
+	// 審査したAPIキー
+	reviewer, err := reviewerAPIKey(c)
+	if err != nil {
+		return err
+	}
+
+	// 送信を却下
+	review, err := moderationController.moderationUseCase.RejectScore(c.Request().Context(), usecase.ReviewScoreCommand{
+		SubmissionID: rejectScoreRequest.SubmissionID,
+		Reviewer:     reviewer,
+		Reason:       rejectScoreRequest.Reason,
+		ShadowBan:    rejectScoreRequest.ShadowBan,
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ModerationController.RejectScore] Failed to reject score: %v", err)
+		return err
+	}
+
+	// 記録した審査を返却する
+	return c.JSON(http.StatusOK, review)
+}
+
+// 認証したAPIキーを審査した者として取得する
+func reviewerAPIKey(c echo.Context) (*domain.APIKey, error) {
+	apiKey, ok := c.Get(apiKeyContextKey).(*domain.APIKey)
+	if !ok {
+		return nil, domain.NewUnauthorizedError("api_key_required", "APIキーが指定されていません。")
+	}
+	return apiKey, nil
+}
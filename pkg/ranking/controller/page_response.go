@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 前後のページへのリンク
+type PageLinks struct {
+	Next *string `json:"next"`
+	Prev *string `json:"prev"`
+}
+
+// ページングされた一覧レスポンス
+type PageResponse[T any] struct {
+	Items []T       `json:"items"`
+	Links PageLinks `json:"links"`
+}
+
+// リクエストのURLのカーソルを差し替えたリンクを生成する
+func pageLink(c echo.Context, cursor string) *string {
+	u := *c.Request().URL
+	query := u.Query()
+	query.Set("cursor", cursor)
+	u.RawQuery = query.Encode()
+	link := u.RequestURI()
+	return &link
+}
+
+// IDをキーとしたページングの一覧レスポンスを生成する
+func newIDPageResponse[T any](c echo.Context, codec *CursorCodec, kind string, page *usecase.PageDto[T], id func(T) int) (*PageResponse[T], error) {
+	links, err := newIDPageLinks(c, codec, kind, page.Items, page.HasNext, page.HasPrev, id)
+	if err != nil {
+		return nil, err
+	}
+	return &PageResponse[T]{Items: page.Items, Links: links}, nil
+}
+
+// IDをキーとした前後のページへのリンクを生成する
+func newIDPageLinks[T any](c echo.Context, codec *CursorCodec, kind string, items []T, hasNext bool, hasPrev bool, id func(T) int) (PageLinks, error) {
+	var links PageLinks
+
+	// 要素がない場合は基準にできないためリンクを生成しない
+	if len(items) == 0 {
+		return links, nil
+	}
+
+	// 後ろのページは最後の要素を基準にする
+	if hasNext {
+		cursor, err := codec.Encode(idCursorPayload{Kind: kind, ID: id(items[len(items)-1]), Direction: domain.PageNext})
+		if err != nil {
+			return links, err
+		}
+		links.Next = pageLink(c, cursor)
+	}
+
+	// 前のページは最初の要素を基準にする
+	if hasPrev {
+		cursor, err := codec.Encode(idCursorPayload{Kind: kind, ID: id(items[0]), Direction: domain.PagePrev})
+		if err != nil {
+			return links, err
+		}
+		links.Prev = pageLink(c, cursor)
+	}
+
+	return links, nil
+}
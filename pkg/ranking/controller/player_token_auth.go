@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"log"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// プレイヤーのトークンの認可
+type PlayerTokenAuth struct {
+	playerTokenUseCase *usecase.PlayerTokenUseCase
+}
+
+// プレイヤーのトークンの認可を生成する
+func NewPlayerTokenAuth(u *usecase.PlayerTokenUseCase) *PlayerTokenAuth {
+	return &PlayerTokenAuth{
+		playerTokenUseCase: u,
+	}
+}
+
+// AuthorizationヘッダーのBearerトークンの主体がパスパラメタのユーザーであることを求めるミドルウェア
+// (数値でないパラメタは主体と一致しないため拒否する)
+func (auth *PlayerTokenAuth) RequireSubject(param string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, _ := strconv.Atoi(c.Param(param))
+			if err := auth.playerTokenUseCase.AuthorizePlayer(c.Request().Context(), bearerToken(c), userID); err != nil {
+				log.Printf("[PlayerTokenAuth] Failed to authorize player token: %v", err)
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// AuthorizationヘッダーのBearerトークンを取り出す (指定されていない場合は空文字)
+func bearerToken(c echo.Context) string {
+	scheme, token, ok := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// プレイヤーのトークンコントローラー
+type PlayerTokenController struct {
+	playerTokenUseCase *usecase.PlayerTokenUseCase
+	validator          *validator.Validate
+}
+
+// コントローラーを生成する
+func NewPlayerTokenController(u *usecase.PlayerTokenUseCase, v *validator.Validate) *PlayerTokenController {
+	return &PlayerTokenController{
+		playerTokenUseCase: u,
+		validator:          v,
+	}
+}
+
+// ユーザーのトークンを発行する (ゲームサーバーがプレイヤーのログイン時に呼び出す)
+func (playerTokenController *PlayerTokenController) IssuePlayerToken(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type IssuePlayerTokenRequest struct {
+		UserID int `json:"user_id" param:"user_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	issuePlayerTokenRequest := new(IssuePlayerTokenRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(issuePlayerTokenRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(playerTokenController.validator, issuePlayerTokenRequest); err != nil {
+		return err
+	}
+
+	// トークンを発行
+	playerToken, err := playerTokenController.playerTokenUseCase.IssuePlayerToken(c.Request().Context(), issuePlayerTokenRequest.UserID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[PlayerTokenController.IssuePlayerToken] Failed to issue player token: %v", err)
+		return err
+	}
+
+	// 発行したトークンを返却する
+	return c.JSON(http.StatusCreated, playerToken)
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RFC 7807 エラーレスポンスのContent-Type
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// RFC 7807 エラーレスポンス
+type Problem struct {
+	// エラーの種類を識別するURI
+	Type string `json:"type"`
+
+	// エラーの種類の概要
+	Title string `json:"title"`
+
+	// HTTPステータスコード
+	Status int `json:"status"`
+
+	// このリクエストに固有のエラー詳細
+	Detail string `json:"detail,omitempty"`
+
+	// エラーが発生したリクエストのパス
+	Instance string `json:"instance,omitempty"`
+
+	// 機械可読なエラーコード (拡張メンバー)
+	Code string `json:"code"`
+
+	// フィールドごとのエラー (拡張メンバー)
+	Errors []ProblemFieldError `json:"errors,omitempty"`
+}
+
+// フィールドごとのエラー
+type ProblemFieldError struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// エラーレスポンスを生成する
+func NewProblem(status int, code string, detail string) *Problem {
+	return &Problem{
+		Type:   problemType(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// エラーコードからエラーの種類を識別するURIを生成する
+func problemType(code string) string {
+	return fmt.Sprintf("urn:practice-go-game-ranking:problem:%s", code)
+}
@@ -1,52 +1,143 @@
 package controller
 
 import (
-	"fmt"
+	"encoding/json"
 	"log"
 	"net/http"
 	"practice-go-game-ranking/pkg/ranking/usecase"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
-	"github.com/uptrace/bun"
 )
 
 // ランキングコントローラー
 type RankingController struct {
 	rankingUseCase *usecase.RankingUseCase
 	validator      *validator.Validate
-	db             *bun.DB
+	cursorCodec    *CursorCodec
 }
 
 // コントローラーを生成する
-func NewRankingController(u *usecase.RankingUseCase, v *validator.Validate, d *bun.DB) *RankingController {
+func NewRankingController(u *usecase.RankingUseCase, v *validator.Validate, cc *CursorCodec) *RankingController {
 	return &RankingController{
 		rankingUseCase: u,
 		validator:      v,
-		db:             d,
+		cursorCodec:    cc,
 	}
 }
 
 // ランキング一覧を取得する
 func (rankingController *RankingController) GetRankings(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetRankingsRequest struct {
+		Cursor string `json:"cursor" query:"cursor"`
+		Limit  int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getRankingsRequest := new(GetRankingsRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getRankingsRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(rankingController.validator, getRankingsRequest); err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成
+	cursor, err := rankingController.cursorCodec.decodeIDCursor(getRankingsRequest.Cursor, rankingCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getRankingsRequest.Limit)
+	if err != nil {
+		return err
+	}
+
 	// ランキング一覧を取得
-	rankings, err := rankingController.rankingUseCase.GetRankings(c.Request().Context())
+	rankings, err := rankingController.rankingUseCase.GetRankings(c.Request().Context(), page)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[RankingController.GetRankings] Failed to fetch rankings: %v", err)
+		return err
+	}
+
+	// 前後のページへのリンクを付けてランキング一覧を返却する
+	response, err := newIDPageResponse(c, rankingController.cursorCodec, rankingCursorKind, rankings, func(ranking usecase.RankingDto) int { return ranking.ID })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// ゲームに属するランキング一覧を取得する
+func (rankingController *RankingController) GetGameRankings(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetGameRankingsRequest struct {
+		GameID int    `json:"game_id" param:"game_id" validate:"required"`
+		Cursor string `json:"cursor" query:"cursor"`
+		Limit  int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getGameRankingsRequest := new(GetGameRankingsRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getGameRankingsRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(rankingController.validator, getGameRankingsRequest); err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成
+	cursor, err := rankingController.cursorCodec.decodeIDCursor(getGameRankingsRequest.Cursor, rankingCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getGameRankingsRequest.Limit)
+	if err != nil {
+		return err
+	}
+
+	// ゲームに属するランキング一覧を取得
+	rankings, err := rankingController.rankingUseCase.GetRankingsByGame(c.Request().Context(), getGameRankingsRequest.GameID, page)
 
 	// エラーハンドリング
 	if err != nil {
-		log.Printf("[UserController.GetUsers] Failed to fetch users: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ランキング一覧の取得に失敗しました。"})
+		log.Printf("[RankingController.GetGameRankings] Failed to fetch rankings: %v", err)
+		return err
 	}
 
-	// ランキング一覧を返却する
-	return c.JSON(http.StatusOK, rankings)
+	// 前後のページへのリンクを付けてランキング一覧を返却する
+	response, err := newIDPageResponse(c, rankingController.cursorCodec, rankingCursorKind, rankings, func(ranking usecase.RankingDto) int { return ranking.ID })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
 }
 
 // ランキングを新規登録する
 func (rankingController *RankingController) CreateRanking(c echo.Context) error {
 	// リクエストを受ける構造体を定義
+	// スコアの制約はスコアの型に応じて変換するため、浮動小数点数を経由せず数値の表記のまま受け取る (未指定の場合は空文字)
 	type CreateRankingRequest struct {
-		Name string `json:"name" validate:"required,max=50"`
+		GameID     int         `json:"game_id" validate:"required"`
+		Name       string      `json:"name" validate:"required,max=50"`
+		SortOrder  string      `json:"sort_order" validate:"omitempty,oneof=desc asc"`
+		ScoreType  string      `json:"score_type" validate:"omitempty,oneof=integer decimal duration"`
+		ScoreScale int         `json:"score_scale" validate:"min=0,max=6"`
+		MinScore   json.Number `json:"min_score"`
+		MaxScore   json.Number `json:"max_score"`
+		ScoreStep  json.Number `json:"score_step"`
+		Recurrence string      `json:"recurrence" validate:"omitempty,oneof=daily weekly monthly"`
+		Timezone   string      `json:"timezone" validate:"max=64"`
 	}
 
 	// リクエストを受ける構造体を生成
@@ -54,42 +145,116 @@ func (rankingController *RankingController) CreateRanking(c echo.Context) error
 
 	// リクエストボディをマッピング
 	if err := c.Bind(createRankingRequest); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "リクエストボディが不正です。"})
+		return newBindError(err)
 	}
 
 	// リクエストパラメタのバリデーション
-	if err := rankingController.validator.Struct(createRankingRequest); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		var messages []string
-		for _, vErr := range validationErrors {
-			messages = append(messages, fmt.Sprintf("フィールド '%s' の値が不正です: %s", vErr.Field(), vErr.Tag()))
-		}
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"error":   "バリデーションエラー",
-			"message": messages,
-		})
-	}
-
-	// トランザクションを管理する
-	tx, err := rankingController.db.BeginTx(c.Request().Context(), nil)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "トランザクション開始に失敗しました"})
+	if err := validateRequest(rankingController.validator, createRankingRequest); err != nil {
+		return err
+	}
+
+	// 登録先のゲームをAPIキーで操作できるか確認
+	if err := authorizeGame(c, createRankingRequest.GameID); err != nil {
+		log.Printf("[RankingController.CreateRanking] Failed to authorize game: %v", err)
+		return err
 	}
 
 	// ランキングを新規登録
-	ranking, err := rankingController.rankingUseCase.CreateRanking(c.Request().Context(), createRankingRequest.Name)
+	ranking, err := rankingController.rankingUseCase.CreateRanking(c.Request().Context(), usecase.CreateRankingCommand{
+		GameID:     createRankingRequest.GameID,
+		Name:       createRankingRequest.Name,
+		SortOrder:  createRankingRequest.SortOrder,
+		ScoreType:  createRankingRequest.ScoreType,
+		ScoreScale: createRankingRequest.ScoreScale,
+		MinScore:   createRankingRequest.MinScore.String(),
+		MaxScore:   createRankingRequest.MaxScore.String(),
+		ScoreStep:  createRankingRequest.ScoreStep.String(),
+		Recurrence: createRankingRequest.Recurrence,
+		Timezone:   createRankingRequest.Timezone,
+	})
 
 	// エラーハンドリング
 	if err != nil {
-		tx.Rollback()
 		log.Printf("[RankingController.CreateRanking] Failed to create ranking: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ランキング登録に失敗しました。"})
-	}
-
-	if err := tx.Commit(); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "コミットに失敗しました"})
+		return err
 	}
 
 	// 登録したランキングを返却する
 	return c.JSON(http.StatusCreated, ranking)
 }
+
+// ランキングを削除する
+func (rankingController *RankingController) DeleteRanking(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type DeleteRankingRequest struct {
+		RankingID int `json:"ranking_id" param:"ranking_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	deleteRankingRequest := new(DeleteRankingRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(deleteRankingRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(rankingController.validator, deleteRankingRequest); err != nil {
+		return err
+	}
+
+	// ランキングを削除
+	err := rankingController.rankingUseCase.DeleteRanking(c.Request().Context(), deleteRankingRequest.RankingID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[RankingController.DeleteRanking] Failed to delete ranking: %v", err)
+		return err
+	}
+
+	// 削除した場合は本文なしで返却する
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ランキングのスコアの妥当性のルールを更新する
+func (rankingController *RankingController) UpdatePlausibilityRules(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	// スコアの上限はスコアの型に応じて変換するため、浮動小数点数を経由せず数値の表記のまま受け取る (未指定のルールは無効にする)
+	type UpdatePlausibilityRulesRequest struct {
+		RankingID               int         `json:"ranking_id" param:"ranking_id" validate:"required"`
+		MaxImprovementRatio     *float64    `json:"max_improvement_ratio"`
+		MinSubmissionIntervalMs *int64      `json:"min_submission_interval_ms"`
+		ScoreCap                json.Number `json:"score_cap"`
+		MaxZScore               *float64    `json:"max_z_score"`
+	}
+
+	// リクエストを受ける構造体を生成
+	updatePlausibilityRulesRequest := new(UpdatePlausibilityRulesRequest)
+
+	// リクエストパラメタとリクエストボディをマッピング
+	if err := c.Bind(updatePlausibilityRulesRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(rankingController.validator, updatePlausibilityRulesRequest); err != nil {
+		return err
+	}
+
+	// スコアの妥当性のルールを更新
+	ranking, err := rankingController.rankingUseCase.UpdatePlausibilityRules(c.Request().Context(), updatePlausibilityRulesRequest.RankingID, usecase.UpdatePlausibilityRulesCommand{
+		MaxImprovementRatio:     updatePlausibilityRulesRequest.MaxImprovementRatio,
+		MinSubmissionIntervalMs: updatePlausibilityRulesRequest.MinSubmissionIntervalMs,
+		ScoreCap:                updatePlausibilityRulesRequest.ScoreCap.String(),
+		MaxZScore:               updatePlausibilityRulesRequest.MaxZScore,
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[RankingController.UpdatePlausibilityRules] Failed to update plausibility rules: %v", err)
+		return err
+	}
+
+	// 更新したランキングを返却する
+	return c.JSON(http.StatusOK, ranking)
+}
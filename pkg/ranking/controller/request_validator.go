@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// リクエストパラメタのバリデーションエラー
+type RequestValidationError struct {
+	Errors []ProblemFieldError
+}
+
+// エラーメッセージを返す
+func (e *RequestValidationError) Error() string {
+	return "リクエストパラメタの値が不正です。"
+}
+
+// バリデーターを生成する (エラーのフィールド名にはjsonタグの名前を使う)
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, key := range []string{"json", "param", "query"} {
+			name := strings.SplitN(field.Tag.Get(key), ",", 2)[0]
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	return v
+}
+
+// リクエストパラメタのバリデーションを行う
+func validateRequest(v *validator.Validate, request interface{}) error {
+	err := v.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	// バリデーションエラー以外はそのまま返す
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	// フィールドごとのエラーに変換する
+	fieldErrors := make([]ProblemFieldError, 0, len(validationErrors))
+	for _, vErr := range validationErrors {
+		fieldErrors = append(fieldErrors, ProblemFieldError{
+			Field:  vErr.Field(),
+			Code:   vErr.Tag(),
+			Detail: fmt.Sprintf("フィールド '%s' の値が不正です: %s", vErr.Field(), vErr.Tag()),
+		})
+	}
+	return &RequestValidationError{Errors: fieldErrors}
+}
+
+// リクエストボディのマッピングエラーを生成する
+func newBindError(err error) error {
+	return echo.NewHTTPError(http.StatusBadRequest, "リクエストボディが不正です。").SetInternal(err)
+}
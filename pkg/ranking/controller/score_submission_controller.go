@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// スコアの送信履歴コントローラー
+type ScoreSubmissionController struct {
+	scoreSubmissionUseCase *usecase.ScoreSubmissionUseCase
+	validator              *validator.Validate
+	cursorCodec            *CursorCodec
+}
+
+// コントローラーを生成する
+func NewScoreSubmissionController(u *usecase.ScoreSubmissionUseCase, v *validator.Validate, cc *CursorCodec) *ScoreSubmissionController {
+	return &ScoreSubmissionController{
+		scoreSubmissionUseCase: u,
+		validator:              v,
+		cursorCodec:            cc,
+	}
+}
+
+// ランキングとユーザーを指定してスコアの送信履歴を取得する
+func (scoreSubmissionController *ScoreSubmissionController) GetScoreSubmissions(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	// 期間はRFC 3339形式の日時で受け取る (fromを含み、toを含まない)
+	type GetScoreSubmissionsRequest struct {
+		RankingID int    `json:"ranking_id" param:"ranking_id" validate:"required"`
+		UserID    int    `json:"user_id" param:"user_id" validate:"required"`
+		From      string `json:"from" query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+		To        string `json:"to" query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+		Cursor    string `json:"cursor" query:"cursor"`
+		Limit     int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getScoreSubmissionsRequest := new(GetScoreSubmissionsRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getScoreSubmissionsRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(scoreSubmissionController.validator, getScoreSubmissionsRequest); err != nil {
+		return err
+	}
+
+	// 絞り込む期間を生成
+	timeRange, err := domain.NewTimeRange(parseOptionalTime(getScoreSubmissionsRequest.From), parseOptionalTime(getScoreSubmissionsRequest.To))
+	if err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成
+	cursor, err := scoreSubmissionController.cursorCodec.decodeIDCursor(getScoreSubmissionsRequest.Cursor, scoreSubmissionCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getScoreSubmissionsRequest.Limit)
+	if err != nil {
+		return err
+	}
+
+	// 送信履歴を取得
+	submissions, err := scoreSubmissionController.scoreSubmissionUseCase.GetScoreSubmissions(c.Request().Context(), getScoreSubmissionsRequest.RankingID, getScoreSubmissionsRequest.UserID, timeRange, page)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ScoreSubmissionController.GetScoreSubmissions] Failed to fetch score submissions: %v", err)
+		return err
+	}
+
+	// 前後のページへのリンクを付けて送信履歴を返却する (リンクには期間の指定も引き継ぐ)
+	response, err := newIDPageResponse(c, scoreSubmissionController.cursorCodec, scoreSubmissionCursorKind, submissions, func(submission usecase.ScoreSubmissionDto) int { return submission.ID })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// バリデーション済みのRFC 3339形式の日時を変換する (空文字の場合はnil)
+func parseOptionalTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
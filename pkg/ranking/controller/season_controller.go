@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// シーズンコントローラー
+type SeasonController struct {
+	seasonUseCase                 *usecase.SeasonUseCase
+	seasonUserRankingQueryService usecase.SeasonUserRankingQueryServiceInterface
+	validator                     *validator.Validate
+	cursorCodec                   *CursorCodec
+}
+
+// 終了したシーズンのユーザーランキングレスポンス
+type SeasonUserRankingResponse struct {
+	*usecase.SeasonUserRankingDto
+	Links PageLinks `json:"links"`
+}
+
+// コントローラーを生成する
+func NewSeasonController(u *usecase.SeasonUseCase, q usecase.SeasonUserRankingQueryServiceInterface, v *validator.Validate, cc *CursorCodec) *SeasonController {
+	return &SeasonController{
+		seasonUseCase:                 u,
+		seasonUserRankingQueryService: q,
+		validator:                     v,
+		cursorCodec:                   cc,
+	}
+}
+
+// ランキングの終了したシーズン一覧を取得する
+func (seasonController *SeasonController) GetSeasons(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetSeasonsRequest struct {
+		RankingID int    `json:"ranking_id" param:"ranking_id" validate:"required"`
+		Cursor    string `json:"cursor" query:"cursor"`
+		Limit     int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getSeasonsRequest := new(GetSeasonsRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getSeasonsRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(seasonController.validator, getSeasonsRequest); err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成 (シーズンの番号をキーとする)
+	cursor, err := seasonController.cursorCodec.decodeIDCursor(getSeasonsRequest.Cursor, seasonCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getSeasonsRequest.Limit)
+	if err != nil {
+		return err
+	}
+
+	// 終了したシーズン一覧を取得
+	seasons, err := seasonController.seasonUseCase.GetSeasons(c.Request().Context(), getSeasonsRequest.RankingID, page)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[SeasonController.GetSeasons] Failed to fetch seasons: %v", err)
+		return err
+	}
+
+	// 前後のページへのリンクを付けてシーズン一覧を返却する
+	response, err := newIDPageResponse(c, seasonController.cursorCodec, seasonCursorKind, seasons, func(season usecase.SeasonDto) int { return season.Season })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// 終了したシーズンのユーザーランキングを取得する
+func (seasonController *SeasonController) GetSeasonUserRanking(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetSeasonUserRankingRequest struct {
+		RankingID int    `json:"ranking_id" param:"ranking_id" validate:"required"`
+		Season    int    `json:"season" param:"season" validate:"required,min=1"`
+		Cursor    string `json:"cursor" query:"cursor"`
+		Limit     int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getSeasonUserRankingRequest := new(GetSeasonUserRankingRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getSeasonUserRankingRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(seasonController.validator, getSeasonUserRankingRequest); err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成 (アーカイブ時のランクをキーとする)
+	cursor, err := seasonController.cursorCodec.decodeIDCursor(getSeasonUserRankingRequest.Cursor, seasonUserRankingCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getSeasonUserRankingRequest.Limit)
+	if err != nil {
+		return err
+	}
+
+	// 終了したシーズンのユーザーランキングを取得
+	seasonUserRanking, err := seasonController.seasonUserRankingQueryService.FetchSeasonUserRanking(c.Request().Context(), getSeasonUserRankingRequest.RankingID, getSeasonUserRankingRequest.Season, page)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[SeasonController.GetSeasonUserRanking] Failed to fetch season user ranking: %v", err)
+		return err
+	}
+
+	// 前後のページへのリンクを付けてランキングを返却する
+	links, err := newIDPageLinks(c, seasonController.cursorCodec, seasonUserRankingCursorKind, seasonUserRanking.UserRanks, seasonUserRanking.HasNext, seasonUserRanking.HasPrev, func(userRank usecase.UserRankDto) int { return userRank.Rank })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &SeasonUserRankingResponse{SeasonUserRankingDto: seasonUserRanking, Links: links})
+}
@@ -1,45 +1,84 @@
 package controller
 
 import (
-	"fmt"
 	"log"
 	"net/http"
 	"practice-go-game-ranking/pkg/ranking/usecase"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
-	"github.com/uptrace/bun"
 )
 
 // ユーザーコントローラー
 type UserController struct {
-	userUseCase *usecase.UserUseCase
-	validator   *validator.Validate
-	db          *bun.DB
+	userUseCase        *usecase.UserUseCase
+	playerTokenUseCase *usecase.PlayerTokenUseCase
+	validator          *validator.Validate
+	cursorCodec        *CursorCodec
+}
+
+// 登録したユーザーのレスポンス (プレイヤーのトークンを含む)
+type CreatedUserResponse struct {
+	*usecase.UserDto
+	PlayerToken *usecase.PlayerTokenDto `json:"player_token"`
 }
 
 // コントローラーを生成する
-func NewUserController(u *usecase.UserUseCase, v *validator.Validate, d *bun.DB) *UserController {
+func NewUserController(u *usecase.UserUseCase, pt *usecase.PlayerTokenUseCase, v *validator.Validate, cc *CursorCodec) *UserController {
 	return &UserController{
-		userUseCase: u,
-		validator:   v,
-		db:          d,
+		userUseCase:        u,
+		playerTokenUseCase: pt,
+		validator:          v,
+		cursorCodec:        cc,
 	}
 }
 
 // ユーザー一覧を取得する
 func (u *UserController) GetUsers(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetUsersRequest struct {
+		Cursor string `json:"cursor" query:"cursor"`
+		Limit  int    `json:"limit" query:"limit"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getUsersRequest := new(GetUsersRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getUsersRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(u.validator, getUsersRequest); err != nil {
+		return err
+	}
+
+	// カーソルからページ指定を生成
+	cursor, err := u.cursorCodec.decodeIDCursor(getUsersRequest.Cursor, userCursorKind)
+	if err != nil {
+		return err
+	}
+	page, err := usecase.NewIDPageRequest(cursor.ID, cursor.Direction, getUsersRequest.Limit)
+	if err != nil {
+		return err
+	}
+
 	// ユーザー一覧を取得
-	users, err := u.userUseCase.GetUsers(c.Request().Context())
+	users, err := u.userUseCase.GetUsers(c.Request().Context(), page)
 
 	// エラーハンドリング
 	if err != nil {
 		log.Printf("[UserController.GetUsers] Failed to fetch users: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ユーザー一覧の取得に失敗しました。"})
+		return err
 	}
 
-	// ユーザー一覧を返却する
-	return c.JSON(http.StatusOK, users)
+	// 前後のページへのリンクを付けてユーザー一覧を返却する
+	response, err := newIDPageResponse(c, u.cursorCodec, userCursorKind, users, func(user usecase.UserDto) int { return user.ID })
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
 }
 
 // ユーザーを新規登録する
@@ -54,26 +93,12 @@ func (u *UserController) CreateUser(c echo.Context) error {
 
 	// リクエストボディをマッピング
 	if err := c.Bind(createUserRequest); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "リクエストボディが不正です。"})
+		return newBindError(err)
 	}
 
 	// リクエストパラメタのバリデーション
-	if err := u.validator.Struct(createUserRequest); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		var messages []string
-		for _, vErr := range validationErrors {
-			messages = append(messages, fmt.Sprintf("フィールド '%s' の値が不正です: %s", vErr.Field(), vErr.Tag()))
-		}
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"error":   "バリデーションエラー",
-			"message": messages,
-		})
-	}
-
-	// トランザクションを管理する
-	tx, err := u.db.BeginTx(c.Request().Context(), nil)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "トランザクション開始に失敗しました"})
+	if err := validateRequest(u.validator, createUserRequest); err != nil {
+		return err
 	}
 
 	// ユーザーを新規登録
@@ -81,15 +106,85 @@ func (u *UserController) CreateUser(c echo.Context) error {
 
 	// エラーハンドリング
 	if err != nil {
-		tx.Rollback()
 		log.Printf("[UserController.CreateUser] Failed to create user: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ユーザー登録に失敗しました。"})
+		return err
+	}
+
+	// 登録したユーザーのトークンを発行
+	playerToken, err := u.playerTokenUseCase.IssuePlayerToken(c.Request().Context(), user.ID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserController.CreateUser] Failed to issue player token: %v", err)
+		return err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "コミットに失敗しました"})
+	// 登録したユーザーをトークンとともに返却する
+	return c.JSON(http.StatusCreated, &CreatedUserResponse{UserDto: user, PlayerToken: playerToken})
+}
+
+// ユーザーを論理削除する
+func (u *UserController) DeleteUser(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type DeleteUserRequest struct {
+		UserID int `json:"user_id" param:"user_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	deleteUserRequest := new(DeleteUserRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(deleteUserRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(u.validator, deleteUserRequest); err != nil {
+		return err
+	}
+
+	// ユーザーを論理削除
+	err := u.userUseCase.DeleteUser(c.Request().Context(), deleteUserRequest.UserID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserController.DeleteUser] Failed to delete user: %v", err)
+		return err
+	}
+
+	// 削除した場合は本文なしで返却する
+	return c.NoContent(http.StatusNoContent)
+}
+
+// 論理削除したユーザーを復元する
+func (u *UserController) RestoreUser(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type RestoreUserRequest struct {
+		UserID int `json:"user_id" param:"user_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	restoreUserRequest := new(RestoreUserRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(restoreUserRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(u.validator, restoreUserRequest); err != nil {
+		return err
+	}
+
+	// ユーザーを復元
+	user, err := u.userUseCase.RestoreUser(c.Request().Context(), restoreUserRequest.UserID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserController.RestoreUser] Failed to restore user: %v", err)
+		return err
 	}
 
-	// 登録したユーザーを返却する
-	return c.JSON(http.StatusCreated, user)
+	// 復元したユーザーを返却する
+	return c.JSON(http.StatusOK, user)
 }
@@ -1,39 +1,48 @@
 package controller
 
 import (
-	"fmt"
+	"encoding/json"
 	"log"
 	"net/http"
+	"practice-go-game-ranking/pkg/ranking/domain"
 	"practice-go-game-ranking/pkg/ranking/usecase"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
-	"github.com/uptrace/bun"
+)
+
+// クライアントのバージョンを受け取るヘッダー
+const clientVersionHeader = "X-Client-Version"
+
+// スコアの送信の署名を受け取るヘッダー
+const (
+	submissionSignatureHeader = "X-Submission-Signature"
+	submissionNonceHeader     = "X-Submission-Nonce"
+	submissionTimestampHeader = "X-Submission-Timestamp"
 )
 
 // ユーザーハイスコアコントローラー
 type UserHighScoreController struct {
 	userHighScoreUseCase *usecase.UserHighScoreUseCase
 	validator            *validator.Validate
-	db                   *bun.DB
 }
 
 // コントローラーを生成する
-func NewUserHighScoreController(u *usecase.UserHighScoreUseCase, v *validator.Validate, d *bun.DB) *UserHighScoreController {
+func NewUserHighScoreController(u *usecase.UserHighScoreUseCase, v *validator.Validate) *UserHighScoreController {
 	return &UserHighScoreController{
 		userHighScoreUseCase: u,
 		validator:            v,
-		db:                   d,
 	}
 }
 
 // ハイスコアを登録する
 func (userHighScoreController *UserHighScoreController) StoreHighScore(c echo.Context) error {
 	// リクエストを受ける構造体を定義
+	// スコアはランキングのスコアの型に応じて変換するため、浮動小数点数を経由せず数値の表記のまま受け取る
 	type CreateUserHighScoreRequest struct {
-		RankingID int `json:"ranking_id" param:"ranking_id" validate:"required`
-		UserID    int `json:"user_id" param:"user_id" validate:"required`
-		Score     int `json:"score" validate:"required`
+		RankingID int         `json:"ranking_id" param:"ranking_id" validate:"required"`
+		UserID    int         `json:"user_id" param:"user_id" validate:"required"`
+		Score     json.Number `json:"score" validate:"required"`
 	}
 
 	// リクエストを受ける構造体を生成
@@ -41,42 +50,88 @@ func (userHighScoreController *UserHighScoreController) StoreHighScore(c echo.Co
 
 	// リクエストボディをマッピング
 	if err := c.Bind(createUserHighScoreRequest); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "リクエストボディが不正です。"})
+		return newBindError(err)
 	}
 
 	// リクエストパラメタのバリデーション
-	if err := userHighScoreController.validator.Struct(createUserHighScoreRequest); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		var messages []string
-		for _, vErr := range validationErrors {
-			messages = append(messages, fmt.Sprintf("フィールド '%s' の値が不正です: %s", vErr.Field(), vErr.Tag()))
-		}
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"error":   "バリデーションエラー",
-			"message": messages,
-		})
+	if err := validateRequest(userHighScoreController.validator, createUserHighScoreRequest); err != nil {
+		return err
 	}
 
-	// トランザクションを管理する
-	tx, err := userHighScoreController.db.BeginTx(c.Request().Context(), nil)
+	// 送信の署名をヘッダーから受け取る
+	signature, err := submissionSignature(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "トランザクション開始に失敗しました"})
+		return err
 	}
 
-	// ハイスコアを登録
-	err = userHighScoreController.userHighScoreUseCase.UpdateUserHighScore(c.Request().Context(), createUserHighScoreRequest.RankingID, createUserHighScoreRequest.UserID, createUserHighScoreRequest.Score)
+	// ハイスコアを登録 (送信履歴にはリクエスト元のクライアントの情報も記録する)
+	result, err := userHighScoreController.userHighScoreUseCase.UpdateUserHighScore(c.Request().Context(), usecase.UpdateUserHighScoreCommand{
+		RankingID: createUserHighScoreRequest.RankingID,
+		UserID:    createUserHighScoreRequest.UserID,
+		Score:     createUserHighScoreRequest.Score.String(),
+		Client:    clientMetadata(c),
+		Signature: signature,
+	})
 
 	// エラーハンドリング
 	if err != nil {
-		tx.Rollback()
-		log.Printf("[UserController.CreateUser] Failed to create user: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ハイスコア更新に失敗しました。"})
+		log.Printf("[UserHighScoreController.StoreHighScore] Failed to store high score: %v", err)
+		return err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "コミットに失敗しました"})
+	// 更新結果を返却する
+	return c.JSON(http.StatusOK, result)
+}
+
+// ハイスコアを削除する
+func (userHighScoreController *UserHighScoreController) DeleteHighScore(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type DeleteUserHighScoreRequest struct {
+		RankingID int `json:"ranking_id" param:"ranking_id" validate:"required"`
+		UserID    int `json:"user_id" param:"user_id" validate:"required"`
 	}
 
-	// 更新結果を返却する
-	return c.JSON(http.StatusOK, nil)
+	// リクエストを受ける構造体を生成
+	deleteUserHighScoreRequest := new(DeleteUserHighScoreRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(deleteUserHighScoreRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(userHighScoreController.validator, deleteUserHighScoreRequest); err != nil {
+		return err
+	}
+
+	// ハイスコアを削除
+	err := userHighScoreController.userHighScoreUseCase.DeleteUserHighScore(c.Request().Context(), deleteUserHighScoreRequest.RankingID, deleteUserHighScoreRequest.UserID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserHighScoreController.DeleteHighScore] Failed to delete high score: %v", err)
+		return err
+	}
+
+	// 削除した場合は本文なしで返却する
+	return c.NoContent(http.StatusNoContent)
+}
+
+// リクエスト元のクライアントの情報を取得する (クライアントのバージョンはX-Client-Versionヘッダーで受け取る)
+func clientMetadata(c echo.Context) domain.ClientMetadata {
+	return domain.ClientMetadata{
+		IPAddress:     c.RealIP(),
+		UserAgent:     c.Request().UserAgent(),
+		ClientVersion: c.Request().Header.Get(clientVersionHeader),
+	}
+}
+
+// スコアの送信の署名をヘッダーから取得する (署名のヘッダーがない場合はnil)
+func submissionSignature(c echo.Context) (*domain.SubmissionSignature, error) {
+	header := c.Request().Header
+	signature := header.Get(submissionSignatureHeader)
+	if signature == "" {
+		return nil, nil
+	}
+	return domain.NewSubmissionSignature(header.Get(submissionNonceHeader), header.Get(submissionTimestampHeader), signature)
 }
@@ -1,9 +1,9 @@
 package controller
 
 import (
-	"fmt"
 	"log"
 	"net/http"
+	"practice-go-game-ranking/pkg/ranking/domain"
 	"practice-go-game-ranking/pkg/ranking/usecase"
 
 	"github.com/go-playground/validator/v10"
@@ -14,13 +14,21 @@ import (
 type UserRankingController struct {
 	userRankingQueryService usecase.UserRankingQueryServiceInterface
 	validator               *validator.Validate
+	cursorCodec             *CursorCodec
+}
+
+// ユーザーランキングレスポンス
+type UserRankingResponse struct {
+	*usecase.UserRankingDto
+	Links PageLinks `json:"links"`
 }
 
 // コントローラーを生成する
-func NewUserRankingController(q usecase.UserRankingQueryServiceInterface, v *validator.Validate) *UserRankingController {
+func NewUserRankingController(q usecase.UserRankingQueryServiceInterface, v *validator.Validate, cc *CursorCodec) *UserRankingController {
 	return &UserRankingController{
 		userRankingQueryService: q,
 		validator:               v,
+		cursorCodec:             cc,
 	}
 }
 
@@ -28,9 +36,10 @@ func NewUserRankingController(q usecase.UserRankingQueryServiceInterface, v *val
 func (userRankingController *UserRankingController) GetUserRanking(c echo.Context) error {
 	// リクエストを受ける構造体を定義
 	type GetUserRankingRequest struct {
-		RankingID int    `json:"ranking_id" param:"ranking_id" validate:"required`
-		OrderBy   string `json:"order_by" query:"order_by" validate:"required`
-		Limit     int    `json:"limit" query:"limit" validate:"required`
+		RankingID int    `json:"ranking_id" param:"ranking_id" validate:"required"`
+		OrderBy   string `json:"order_by" query:"order_by" validate:"omitempty,oneof=rank_asc rank_desc"`
+		Limit     int    `json:"limit" query:"limit"`
+		Cursor    string `json:"cursor" query:"cursor"`
 	}
 
 	// リクエストを受ける構造体を生成
@@ -38,26 +47,24 @@ func (userRankingController *UserRankingController) GetUserRanking(c echo.Contex
 
 	// リクエストボディをマッピング
 	if err := c.Bind(getUserRankingRequest); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "リクエストボディが不正です。"})
+		return newBindError(err)
 	}
 
 	// リクエストパラメタのバリデーション
-	if err := userRankingController.validator.Struct(getUserRankingRequest); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		var messages []string
-		for _, vErr := range validationErrors {
-			messages = append(messages, fmt.Sprintf("フィールド '%s' の値が不正です: %s", vErr.Field(), vErr.Tag()))
-		}
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"error":   "バリデーションエラー",
-			"message": messages,
-		})
+	if err := validateRequest(userRankingController.validator, getUserRankingRequest); err != nil {
+		return err
+	}
+
+	// カーソルを取り出す
+	cursor, err := userRankingController.decodeCursor(getUserRankingRequest.Cursor, getUserRankingRequest.RankingID, getUserRankingRequest.OrderBy)
+	if err != nil {
+		return err
 	}
 
 	// クエリ構造体を生成
-	query := usecase.UserRankingQuery{
-		RankingID: getUserRankingRequest.RankingID,
-		OrderBy:   getUserRankingRequest.OrderBy,
+	query, err := usecase.NewUserRankingQuery(getUserRankingRequest.RankingID, getUserRankingRequest.OrderBy, getUserRankingRequest.Limit, cursor)
+	if err != nil {
+		return err
 	}
 
 	// ユーザーランキングを取得
@@ -65,10 +72,151 @@ func (userRankingController *UserRankingController) GetUserRanking(c echo.Contex
 
 	// エラーハンドリング
 	if err != nil {
-		log.Printf("[UserRankingController.CreateUser] Failed to fetch user ranking: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ユーザーランキングの取得に失敗しました"})
+		log.Printf("[UserRankingController.GetUserRanking] Failed to fetch user ranking: %v", err)
+		return err
+	}
+
+	// 前後のページへのリンクを付けてランキングを返却する
+	response := &UserRankingResponse{UserRankingDto: userRanking}
+	if len(userRanking.UserRanks) > 0 {
+		// 後ろのページは最後の要素を基準にする
+		if userRanking.HasNext {
+			last := userRanking.UserRanks[len(userRanking.UserRanks)-1]
+			if response.Links.Next, err = userRankingController.cursorLink(c, query, last, domain.PageNext); err != nil {
+				return err
+			}
+		}
+
+		// 前のページは最初の要素を基準にする
+		if userRanking.HasPrev {
+			first := userRanking.UserRanks[0]
+			if response.Links.Prev, err = userRankingController.cursorLink(c, query, first, domain.PagePrev); err != nil {
+				return err
+			}
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// ランキングにおけるユーザーの現在のランクを取得する
+func (userRankingController *UserRankingController) GetUserStanding(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetUserStandingRequest struct {
+		RankingID int `json:"ranking_id" param:"ranking_id" validate:"required"`
+		UserID    int `json:"user_id" param:"user_id" validate:"required"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getUserStandingRequest := new(GetUserStandingRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getUserStandingRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(userRankingController.validator, getUserStandingRequest); err != nil {
+		return err
+	}
+
+	// ユーザーの順位を取得
+	userStanding, err := userRankingController.userRankingQueryService.FetchUserStanding(c.Request().Context(), getUserStandingRequest.RankingID, getUserStandingRequest.UserID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserRankingController.GetUserStanding] Failed to fetch user standing: %v", err)
+		return err
 	}
 
-	// ランキングを返却する
-	return c.JSON(http.StatusOK, userRanking)
+	// ユーザーの順位を返却する
+	return c.JSON(http.StatusOK, userStanding)
+}
+
+// ユーザーの前後のユーザーランキングを取得する
+func (userRankingController *UserRankingController) GetUserRankingAround(c echo.Context) error {
+	// リクエストを受ける構造体を定義
+	type GetUserRankingAroundRequest struct {
+		RankingID int  `json:"ranking_id" param:"ranking_id" validate:"required"`
+		UserID    int  `json:"user_id" param:"user_id" validate:"required"`
+		Before    *int `json:"before" query:"before"`
+		After     *int `json:"after" query:"after"`
+	}
+
+	// リクエストを受ける構造体を生成
+	getUserRankingAroundRequest := new(GetUserRankingAroundRequest)
+
+	// リクエストパラメタをマッピング
+	if err := c.Bind(getUserRankingAroundRequest); err != nil {
+		return newBindError(err)
+	}
+
+	// リクエストパラメタのバリデーション
+	if err := validateRequest(userRankingController.validator, getUserRankingAroundRequest); err != nil {
+		return err
+	}
+
+	// クエリ構造体を生成
+	query, err := usecase.NewUserRankingAroundQuery(getUserRankingAroundRequest.RankingID, getUserRankingAroundRequest.UserID, getUserRankingAroundRequest.Before, getUserRankingAroundRequest.After)
+	if err != nil {
+		return err
+	}
+
+	// ユーザーの前後のユーザーランキングを取得
+	userRankingAround, err := userRankingController.userRankingQueryService.FetchUserRankingAround(c.Request().Context(), query)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserRankingController.GetUserRankingAround] Failed to fetch user ranking around: %v", err)
+		return err
+	}
+
+	// ユーザーの前後のユーザーランキングを返却する
+	return c.JSON(http.StatusOK, userRankingAround)
+}
+
+// ユーザーランキングのカーソルを取り出す (空文字の場合はnil)
+func (userRankingController *UserRankingController) decodeCursor(cursor string, rankingID int, orderBy string) (*usecase.UserRankingCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	// 署名を検証してカーソルの中身を取り出す
+	var payload userRankingCursorPayload
+	if err := userRankingController.cursorCodec.Decode(cursor, &payload); err != nil {
+		return nil, err
+	}
+
+	// 別のランキングや並び順のカーソルは使えない
+	order, err := usecase.NewUserRankingOrder(orderBy)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Kind != userRankingCursorKind || payload.RankingID != rankingID || payload.OrderBy != string(order) ||
+		(payload.Direction != domain.PageNext && payload.Direction != domain.PagePrev) {
+		return nil, domain.NewValidationError(invalidCursorCode, "このランキングには使えないカーソルです。")
+	}
+
+	return &usecase.UserRankingCursor{
+		Score:     payload.Score,
+		Timestamp: payload.Timestamp,
+		UserID:    payload.UserID,
+		Direction: payload.Direction,
+	}, nil
+}
+
+// ユーザーランクを基準にしたページへのリンクを生成する
+func (userRankingController *UserRankingController) cursorLink(c echo.Context, query usecase.UserRankingQuery, userRank usecase.UserRankDto, direction domain.PageDirection) (*string, error) {
+	cursor, err := userRankingController.cursorCodec.Encode(userRankingCursorPayload{
+		Kind:      userRankingCursorKind,
+		RankingID: query.RankingID,
+		OrderBy:   string(query.OrderBy),
+		Score:     userRank.Score.Value,
+		Timestamp: userRank.AchievedAt,
+		UserID:    userRank.UserID,
+		Direction: direction,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pageLink(c, cursor), nil
 }
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// APIキー (エンティティ)
+// キーの文字列は発行時にのみ返し、保存するのはハッシュ値と識別用の先頭部分のみとする
+type APIKey struct {
+	ID     int
+	Name   APIKeyName
+	Prefix string
+	Hash   string
+	Scopes []Scope
+
+	// 操作できるゲーム (nilの場合は全てのゲーム)
+	GameID *int
+
+	CreatedAt time.Time
+
+	// 失効した日時 (失効していない場合はnil)
+	RevokedAt *time.Time
+}
+
+// 発行するAPIキーを生成する
+func NewAPIKey(name APIKeyName, scopes []Scope, gameID *int, prefix string, hash string) (*APIKey, error) {
+	// APIキーの管理はゲームを限定したキーには許可しない
+	if gameID != nil && slices.Contains(scopes, ScopeKeysAdmin) {
+		return nil, NewValidationError("invalid_scope", fmt.Sprintf("ゲームを限定したAPIキーには %q スコープを付与できません。", ScopeKeysAdmin))
+	}
+
+	return &APIKey{
+		Name:   name,
+		Prefix: prefix,
+		Hash:   hash,
+		Scopes: scopes,
+		GameID: gameID,
+	}, nil
+}
+
+// 失効しているかを判定する
+func (apiKey *APIKey) IsRevoked() bool {
+	return apiKey.RevokedAt != nil
+}
+
+// スコープを持っていることを確認する
+func (apiKey *APIKey) AuthorizeScope(scope Scope) error {
+	if !slices.Contains(apiKey.Scopes, scope) {
+		return NewForbiddenError("insufficient_scope", fmt.Sprintf("APIキーに %q スコープがありません。", scope))
+	}
+	return nil
+}
+
+// ゲームを操作できることを確認する
+func (apiKey *APIKey) AuthorizeGame(gameID int) error {
+	if apiKey.GameID != nil && *apiKey.GameID != gameID {
+		return NewForbiddenError("game_forbidden", fmt.Sprintf("APIキーではこのゲームを操作できません。ゲームID: %d", gameID))
+	}
+	return nil
+}
+
+// 全てのゲームに影響する操作ができることを確認する
+func (apiKey *APIKey) AuthorizeAllGames() error {
+	if apiKey.GameID != nil {
+		return NewForbiddenError("game_forbidden", "ゲームを限定したAPIキーでは全てのゲームに影響する操作はできません。")
+	}
+	return nil
+}
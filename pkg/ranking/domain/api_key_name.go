@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// APIキー名 (値オブジェクト)
+type APIKeyName struct {
+	Value string
+}
+
+// APIキー名を生成する
+func NewAPIKeyName(name string) (APIKeyName, error) {
+	// APIキー名の前後の空白を取り除く
+	trimmedName := strings.TrimSpace(name)
+
+	// ブランク文字、空白文字のみは許容しない
+	if trimmedName == "" {
+		return APIKeyName{}, NewValidationError("invalid_api_key_name", fmt.Sprintf("APIキー名は空にできません。入力された名前: %q", name))
+	}
+
+	// 50文字を超えたAPIキー名を許容しない
+	if utf8.RuneCountInString(trimmedName) > 50 {
+		return APIKeyName{}, NewValidationError("invalid_api_key_name", fmt.Sprintf("APIキー名は50文字以内である必要があります。入力された名前: %q", name))
+	}
+
+	// APIキー名を返却する
+	return APIKeyName{Value: trimmedName}, nil
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// APIキーリポジトリ (インターフェース)
+type APIKeyRepositoryInterface interface {
+	// キーのハッシュ値でAPIキーを取得する (存在しない場合はnil)
+	FindByHash(ctx context.Context, hash string) (*APIKey, error)
+
+	// APIキー一覧をIDの昇順でページングして取得する (失効したキーも含む)
+	FindAll(ctx context.Context, page IDPageRequest) (*Page[APIKey], error)
+
+	// APIキーを登録する
+	Create(ctx context.Context, apiKey *APIKey) (*APIKey, error)
+
+	// APIキーを失効させる (存在しない場合と失効済みの場合はErrNotFound)
+	Revoke(ctx context.Context, id int) error
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコープとゲームの認可
+func TestAPIKeyAuthorize(t *testing.T) {
+	name, _ := NewAPIKeyName("client")
+	gameID := 1
+	apiKey, err := NewAPIKey(name, []Scope{ScopeRead, ScopeScoresWrite}, &gameID, "grk_prefix", "hash")
+	require.NoError(t, err)
+
+	assert.NoError(t, apiKey.AuthorizeScope(ScopeScoresWrite))
+	assert.ErrorIs(t, apiKey.AuthorizeScope(ScopeRankingsAdmin), ErrForbidden)
+	assert.NoError(t, apiKey.AuthorizeGame(1))
+	assert.ErrorIs(t, apiKey.AuthorizeGame(2), ErrForbidden)
+	assert.ErrorIs(t, apiKey.AuthorizeAllGames(), ErrForbidden)
+
+	// ゲームを限定したキーにはAPIキーの管理を許可しない
+	_, err = NewAPIKey(name, []Scope{ScopeKeysAdmin}, &gameID, "grk_prefix", "hash")
+	assert.ErrorIs(t, err, ErrValidation)
+}
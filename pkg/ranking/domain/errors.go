@@ -0,0 +1,68 @@
+package domain
+
+import "errors"
+
+// ドメインエラーの種類 (errors.Isで判定する)
+var (
+	// 対象が存在しない
+	ErrNotFound = errors.New("not found")
+
+	// 一意制約などと競合する
+	ErrConflict = errors.New("conflict")
+
+	// 入力値が不正
+	ErrValidation = errors.New("validation failed")
+
+	// 認証されていない
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// 操作する権限がない
+	ErrForbidden = errors.New("forbidden")
+)
+
+// ドメインエラー
+type Error struct {
+	// エラーの種類 (ErrNotFound, ErrConflict, ErrValidation, ErrUnauthorized, ErrForbidden)
+	Kind error
+
+	// 機械可読なエラーコード
+	Code string
+
+	// エラーメッセージ
+	Message string
+}
+
+// エラーメッセージを返す
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// エラーの種類を返す
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// 対象が存在しないエラーを生成する
+func NewNotFoundError(code string, message string) error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+// 競合エラーを生成する
+func NewConflictError(code string, message string) error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// 入力値が不正なエラーを生成する
+func NewValidationError(code string, message string) error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message}
+}
+
+// 認証されていないエラーを生成する
+func NewUnauthorizedError(code string, message string) error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+// 権限がないエラーを生成する
+func NewForbiddenError(code string, message string) error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
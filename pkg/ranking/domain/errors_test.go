@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// エラーの種類とコードの判定
+func TestDomainErrorKind(t *testing.T) {
+	// ラップされていても種類を判定できる
+	err := fmt.Errorf("wrapped: %w", NewNotFoundError("ranking_not_found", "ランキングが存在しません。"))
+	assert.ErrorIs(t, err, ErrNotFound, "Expected not found error")
+	assert.NotErrorIs(t, err, ErrConflict, "Expected not to be conflict error")
+
+	// コードを取り出せる
+	var domainErr *Error
+	assert.True(t, errors.As(err, &domainErr), "Expected domain error")
+	assert.Equal(t, "ranking_not_found", domainErr.Code)
+
+	// 値オブジェクトの生成失敗はバリデーションエラー
+	_, err = NewUserName(" ")
+	assert.ErrorIs(t, err, ErrValidation, "Expected validation error for blank user name")
+	_, err = NewRankingName(" ")
+	assert.ErrorIs(t, err, ErrValidation, "Expected validation error for blank ranking name")
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ゲーム (エンティティ)
+type Game struct {
+	ID        int
+	Name      GameName
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// スコアの送信の署名に使う秘密鍵 (nilの場合は署名を求めない)
+	SubmissionSecret *string
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ゲーム名 (値オブジェクト)
+type GameName struct {
+	Value string
+}
+
+// ゲーム名を生成する
+func NewGameName(name string) (GameName, error) {
+	// ゲーム名の前後の空白を取り除く
+	trimmedName := strings.TrimSpace(name)
+
+	// ブランク文字、空白文字のみは許容しない
+	if trimmedName == "" {
+		return GameName{}, NewValidationError("invalid_game_name", fmt.Sprintf("ゲーム名は空にできません。入力された名前: %q", name))
+	}
+
+	// 50文字を超えたゲーム名を許容しない
+	if utf8.RuneCountInString(trimmedName) > 50 {
+		return GameName{}, NewValidationError("invalid_game_name", fmt.Sprintf("ゲーム名は50文字以内である必要があります。入力された名前: %q", name))
+	}
+
+	// ゲーム名を返却する
+	return GameName{Value: trimmedName}, nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 文字数の境界値テスト
+func TestGameNameWordCount(t *testing.T) {
+	// 日本語50文字はOK
+	_, err := NewGameName("あいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあいうえお")
+	assert.NoError(t, err, "Expected no error for 50 characters (Japanese)")
+
+	// 日本語51文字はNG
+	_, err = NewGameName("あいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあいうえおあ")
+	assert.Error(t, err, "Expected error for 51 characters (Japanese)")
+}
+
+// 空白文字の取り扱い
+func TestGameNameWhiteTrim(t *testing.T) {
+	// 空白文字だけ
+	_, err := NewGameName(" ")
+	assert.Error(t, err, "Expected error for only blank char")
+
+	// 前後の空白はトリミングされる
+	gameName, err := NewGameName(" a ")
+	assert.NoError(t, err, "Expected no error for trimmed game name")
+	assert.Equal(t, "a", gameName.Value, "Expected trimmed game name to be 'a'")
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "context"
+
+// ゲームリポジトリ (インターフェース)
+type GameRepositoryInterface interface {
+	// ゲームをIDをキーとして取得する (存在しない場合はErrNotFound)
+	FindByID(ctx context.Context, id int) (*Game, error)
+
+	// ゲームを名前をキーとして取得する (存在しない場合はnilを返す)
+	FindByName(ctx context.Context, name GameName) (*Game, error)
+
+	// ゲーム一覧をIDの昇順でページングして取得する
+	FindAll(ctx context.Context, page IDPageRequest) (*Page[Game], error)
+
+	// ゲームを登録する (名前が重複する場合はErrConflict)
+	Create(ctx context.Context, name GameName) (*Game, error)
+
+	// ゲーム名を変更する (存在しない場合はErrNotFound、名前が重複する場合はErrConflict)
+	Update(ctx context.Context, id int, name GameName) (*Game, error)
+
+	// スコアの送信の署名に使う秘密鍵を設定する (nilの場合は署名を求めなくする。存在しない場合はErrNotFound)
+	UpdateSubmissionSecret(ctx context.Context, id int, secret *string) (*Game, error)
+
+	// ゲームを削除する (ゲームに属するランキングも削除される。存在しない場合はErrNotFound)
+	Delete(ctx context.Context, id int) error
+}
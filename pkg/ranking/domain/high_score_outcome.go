@@ -0,0 +1,33 @@
+package domain
+
+// ハイスコア登録結果 (値オブジェクト)
+type HighScoreOutcome string
+
+const (
+	// 初めてハイスコアが登録された
+	HighScoreCreated HighScoreOutcome = "created"
+
+	// ハイスコアが更新された
+	HighScoreImproved HighScoreOutcome = "improved"
+
+	// 既存のハイスコアを上回らないため更新されなかった
+	HighScoreUnchanged HighScoreOutcome = "unchanged"
+
+	// 疑わしいスコアのため審査待ちにした (ハイスコアは変わらない)
+	HighScorePendingReview HighScoreOutcome = "pending_review"
+)
+
+// 既存のハイスコアと新しいスコア、ランキングの並び順から登録結果を判定する
+func DecideHighScoreOutcome(current *UserHighScore, score Score, sortOrder SortOrder) HighScoreOutcome {
+	// ハイスコアが未登録であれば新規登録
+	if current == nil {
+		return HighScoreCreated
+	}
+
+	// ハイスコアを上回った場合のみ更新
+	if current.IsBeatenBy(score, sortOrder) {
+		return HighScoreImproved
+	}
+
+	return HighScoreUnchanged
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ハイスコア登録結果の判定
+func TestDecideHighScoreOutcome(t *testing.T) {
+	// 未登録なら新規登録
+	assert.Equal(t, HighScoreCreated, DecideHighScoreOutcome(nil, 10, SortOrderDesc), "Expected created when no high score exists")
+
+	current := NewUserHighScore(1, 1, 100)
+
+	// 高いスコアなら更新
+	assert.Equal(t, HighScoreImproved, DecideHighScoreOutcome(current, 101, SortOrderDesc), "Expected improved for higher score")
+
+	// 同点は登録日時が古い方を優先するため更新しない
+	assert.Equal(t, HighScoreUnchanged, DecideHighScoreOutcome(current, 100, SortOrderDesc), "Expected unchanged for tie score")
+
+	// 低いスコアなら更新しない
+	assert.Equal(t, HighScoreUnchanged, DecideHighScoreOutcome(current, 99, SortOrderDesc), "Expected unchanged for lower score")
+}
+
+// 小さいほど上位のランキングでのハイスコア登録結果の判定
+func TestDecideHighScoreOutcomeAscending(t *testing.T) {
+	current := NewUserHighScore(1, 1, 100)
+
+	// 低いスコアなら更新
+	assert.Equal(t, HighScoreImproved, DecideHighScoreOutcome(current, 99, SortOrderAsc), "Expected improved for lower score")
+
+	// 同点や高いスコアなら更新しない
+	assert.Equal(t, HighScoreUnchanged, DecideHighScoreOutcome(current, 100, SortOrderAsc), "Expected unchanged for tie score")
+	assert.Equal(t, HighScoreUnchanged, DecideHighScoreOutcome(current, 101, SortOrderAsc), "Expected unchanged for higher score")
+}
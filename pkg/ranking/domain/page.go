@@ -0,0 +1,33 @@
+package domain
+
+// ページングの方向
+type PageDirection string
+
+const (
+	// 基準より後ろのページ
+	PageNext PageDirection = "next"
+
+	// 基準より前のページ
+	PagePrev PageDirection = "prev"
+)
+
+// IDをキーとしたページ指定 (キーセットページネーション)
+type IDPageRequest struct {
+	// 基準のID (0の場合は先頭から取得する)
+	CursorID int
+
+	// 基準からの方向
+	Direction PageDirection
+
+	// 取得件数
+	Limit int
+}
+
+// ページングの結果
+type Page[T any] struct {
+	// 取得した要素 (常に昇順)
+	Items []T
+
+	// 取得した方向にさらに続きがあるか
+	HasMore bool
+}
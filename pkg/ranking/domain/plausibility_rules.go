@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// スコアの妥当性のルールの名前 (審査待ちにした理由として送信履歴に記録する)
+const (
+	PlausibilityRuleImprovementRatio   = "max_improvement_ratio"
+	PlausibilityRuleSubmissionInterval = "min_submission_interval"
+	PlausibilityRuleScoreCap           = "score_cap"
+	PlausibilityRuleZScore             = "max_z_score"
+)
+
+// スコアの妥当性のルールの設定 (値オブジェクト)
+// ランキングごとに疑わしいスコアの閾値を表す。満たさないスコアは拒否せずに審査待ちにする。未指定のルールはnil
+type PlausibilityRules struct {
+	// 前回のハイスコアからの改善の倍率の上限 (1より大きい。スコアが小さいほど上位の場合は前回のハイスコア÷スコア)
+	MaxImprovementRatio *float64
+
+	// 同じユーザーの前回の送信からの最短間隔
+	MinSubmissionInterval *time.Duration
+
+	// 審査なしで受け付けるスコアの上限 (スコアが小さいほど上位の場合は下限)
+	ScoreCap *Score
+
+	// 現在のハイスコアの分布に対する標準得点の上限 (0より大きい)
+	MaxZScore *float64
+}
+
+// スコアの妥当性のルールの設定を生成する (上限はスコアの型に応じた数値の文字列で受け取り、空文字の場合は上限なし)
+func NewPlausibilityRules(scoreType ScoreType, maxImprovementRatio *float64, minSubmissionInterval *time.Duration, scoreCap string, maxZScore *float64) (PlausibilityRules, error) {
+	// 改善の倍率の上限 (1より大きい)
+	if maxImprovementRatio != nil && !(*maxImprovementRatio > 1) {
+		return PlausibilityRules{}, NewValidationError("invalid_plausibility_rules", fmt.Sprintf("改善の倍率の上限は1より大きい必要があります。入力された倍率: %v", *maxImprovementRatio))
+	}
+
+	// 送信の最短間隔 (0より大きい)
+	if minSubmissionInterval != nil && *minSubmissionInterval <= 0 {
+		return PlausibilityRules{}, NewValidationError("invalid_plausibility_rules", fmt.Sprintf("送信の最短間隔は0より大きい必要があります。入力された間隔: %s", *minSubmissionInterval))
+	}
+
+	// 標準得点の上限 (0より大きい)
+	if maxZScore != nil && !(*maxZScore > 0) {
+		return PlausibilityRules{}, NewValidationError("invalid_plausibility_rules", fmt.Sprintf("標準得点の上限は0より大きい必要があります。入力された上限: %v", *maxZScore))
+	}
+
+	// スコアの上限
+	rules := PlausibilityRules{
+		MaxImprovementRatio:   maxImprovementRatio,
+		MinSubmissionInterval: minSubmissionInterval,
+		MaxZScore:             maxZScore,
+	}
+	if scoreCap != "" {
+		score, err := scoreType.Parse(scoreCap)
+		if err != nil {
+			// スコアの変換エラーをルールのエラーとして返す
+			var domainError *Error
+			if errors.As(err, &domainError) {
+				return PlausibilityRules{}, NewValidationError("invalid_plausibility_rules", "スコアの上限が不正です。"+domainError.Message)
+			}
+			return PlausibilityRules{}, err
+		}
+		rules.ScoreCap = &score
+	}
+
+	return rules, nil
+}
+
+// 前回のハイスコアからの改善が倍率の上限を超えるかを判定する (上限なし、前回のハイスコアなし、0以下のスコアは判定しない)
+func (rules PlausibilityRules) ExceedsImprovementRatio(previous *Score, score Score, sortOrder SortOrder) bool {
+	if rules.MaxImprovementRatio == nil || previous == nil || *previous <= 0 || score <= 0 {
+		return false
+	}
+	ratio := float64(score) / float64(*previous)
+	if sortOrder == SortOrderAsc {
+		ratio = float64(*previous) / float64(score)
+	}
+	return ratio > *rules.MaxImprovementRatio
+}
+
+// 前回の送信からの間隔が最短間隔より短いかを判定する (最短間隔なし、前回の送信なしは判定しない)
+func (rules PlausibilityRules) IsTooSoon(lastSubmittedAt *time.Time, now time.Time) bool {
+	if rules.MinSubmissionInterval == nil || lastSubmittedAt == nil {
+		return false
+	}
+	return now.Sub(*lastSubmittedAt) < *rules.MinSubmissionInterval
+}
+
+// スコアが上限を超えて上位かを判定する (上限なしは判定しない)
+func (rules PlausibilityRules) ExceedsScoreCap(score Score, sortOrder SortOrder) bool {
+	return rules.ScoreCap != nil && sortOrder.Beats(score, *rules.ScoreCap)
+}
+
+// スコアの標準得点が上位側に上限を超えるかを判定する (上限なし、分布を判定できない場合は判定しない)
+func (rules PlausibilityRules) ExceedsZScore(stats ScoreStats, score Score, sortOrder SortOrder) bool {
+	if rules.MaxZScore == nil {
+		return false
+	}
+	z, ok := stats.ZScore(score)
+	if !ok {
+		return false
+	}
+	if sortOrder == SortOrderAsc {
+		z = -z
+	}
+	return z > *rules.MaxZScore
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコアの妥当性のルールの設定の生成
+func TestNewPlausibilityRules(t *testing.T) {
+	ratio := 2.0
+	interval := 10 * time.Second
+	z := 3.0
+	rules, err := NewPlausibilityRules(ScoreType{Kind: ScoreKindDecimal, Scale: 2}, &ratio, &interval, "99.5", &z)
+	require.NoError(t, err)
+	assert.Equal(t, Score(9950), *rules.ScoreCap)
+
+	// 未指定の場合はルールなし
+	rules, err = NewPlausibilityRules(ScoreType{Kind: ScoreKindInteger}, nil, nil, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, PlausibilityRules{}, rules)
+
+	// 範囲外の閾値とスコアの型に合わない上限はNG
+	one := 1.0
+	zero := 0.0
+	negative := -time.Second
+	for _, build := range []func() (PlausibilityRules, error){
+		func() (PlausibilityRules, error) {
+			return NewPlausibilityRules(ScoreType{Kind: ScoreKindInteger}, &one, nil, "", nil)
+		},
+		func() (PlausibilityRules, error) {
+			return NewPlausibilityRules(ScoreType{Kind: ScoreKindInteger}, nil, &negative, "", nil)
+		},
+		func() (PlausibilityRules, error) {
+			return NewPlausibilityRules(ScoreType{Kind: ScoreKindInteger}, nil, nil, "1.5", nil)
+		},
+		func() (PlausibilityRules, error) {
+			return NewPlausibilityRules(ScoreType{Kind: ScoreKindInteger}, nil, nil, "", &zero)
+		},
+	} {
+		_, err := build()
+		var domainError *Error
+		require.ErrorAs(t, err, &domainError)
+		assert.Equal(t, "invalid_plausibility_rules", domainError.Code)
+	}
+}
+
+// 各ルールは並び順に応じて上位側への外れ値のみを疑わしいと判定する
+func TestPlausibilityRulesChecks(t *testing.T) {
+	ratio := 2.0
+	interval := 10 * time.Second
+	scoreCap := Score(1000)
+	z := 2.0
+	rules := PlausibilityRules{MaxImprovementRatio: &ratio, MinSubmissionInterval: &interval, ScoreCap: &scoreCap, MaxZScore: &z}
+	previous := Score(100)
+
+	// 改善の倍率
+	assert.False(t, rules.ExceedsImprovementRatio(&previous, 200, SortOrderDesc))
+	assert.True(t, rules.ExceedsImprovementRatio(&previous, 201, SortOrderDesc))
+	assert.False(t, rules.ExceedsImprovementRatio(&previous, 50, SortOrderAsc))
+	assert.True(t, rules.ExceedsImprovementRatio(&previous, 49, SortOrderAsc))
+	assert.False(t, rules.ExceedsImprovementRatio(nil, 10000, SortOrderDesc))
+
+	// 送信の間隔
+	now := time.Unix(1735560000, 0)
+	last := now.Add(-9 * time.Second)
+	assert.True(t, rules.IsTooSoon(&last, now))
+	last = now.Add(-10 * time.Second)
+	assert.False(t, rules.IsTooSoon(&last, now))
+	assert.False(t, rules.IsTooSoon(nil, now))
+
+	// スコアの上限
+	assert.False(t, rules.ExceedsScoreCap(1000, SortOrderDesc))
+	assert.True(t, rules.ExceedsScoreCap(1001, SortOrderDesc))
+	assert.True(t, rules.ExceedsScoreCap(999, SortOrderAsc))
+
+	// 標準得点 (平均100、標準偏差10)
+	stats := ScoreStats{Count: MinScoreStatsCount, Mean: 100, StdDev: 10}
+	assert.False(t, rules.ExceedsZScore(stats, 120, SortOrderDesc))
+	assert.True(t, rules.ExceedsZScore(stats, 121, SortOrderDesc))
+	assert.False(t, rules.ExceedsZScore(stats, 79, SortOrderDesc))
+	assert.True(t, rules.ExceedsZScore(stats, 79, SortOrderAsc))
+
+	// 件数が足りない場合は判定しない
+	stats.Count = MinScoreStatsCount - 1
+	assert.False(t, rules.ExceedsZScore(stats, 1000, SortOrderDesc))
+
+	// ルールなしは判定しない
+	assert.False(t, PlausibilityRules{}.ExceedsScoreCap(1000000, SortOrderDesc))
+}
+
+// ハイスコアの追加、置き換え、取り除きで逐次更新した分布
+func TestScoreMoments(t *testing.T) {
+	// 2, 4, 4, 4, 5, 5, 7, 9 の平均は5、母標準偏差は2
+	var moments ScoreMoments
+	for _, score := range []Score{2, 4, 4, 4, 5, 5, 7, 1} {
+		moments = moments.Add(score)
+	}
+	moments = moments.Replace(1, 9)
+	stats := moments.Stats()
+	assert.Equal(t, 8, stats.Count)
+	assert.InDelta(t, 5.0, stats.Mean, 1e-9)
+	assert.InDelta(t, 2.0, stats.StdDev, 1e-9)
+
+	// 取り除くと残りの分布になる (4, 4, 4, 5, 5, 7 の平均は29/6)
+	stats = moments.Remove(2).Remove(9).Stats()
+	assert.Equal(t, 6, stats.Count)
+	assert.InDelta(t, 29.0/6, stats.Mean, 1e-9)
+
+	// 大きなスコアでも桁落ちしない
+	var large ScoreMoments
+	for _, score := range []Score{1e9 + 2, 1e9 + 4, 1e9 + 4, 1e9 + 4, 1e9 + 5, 1e9 + 5, 1e9 + 7, 1e9 + 9} {
+		large = large.Add(score)
+	}
+	assert.InDelta(t, 2.0, large.Stats().StdDev, 1e-6)
+
+	// 全て取り除くと空
+	assert.Equal(t, ScoreStats{}, ScoreMoments{}.Add(3).Remove(3).Stats())
+}
@@ -4,8 +4,50 @@ import "time"
 
 // ランキング (エンティティ)
 type Ranking struct {
-	ID        int
-	Name      RankingName
+	ID         int
+	GameID     int
+	Name       RankingName
+	SortOrder  SortOrder
+	ScoreType  ScoreType
+	ScoreRules ScoreRules
+	Recurrence Recurrence
+
+	// スコアの妥当性のルール (満たさないスコアは審査待ちにする)
+	PlausibilityRules PlausibilityRules
+
+	// 進行中のシーズン (周期なしの場合はnil)
+	Season *SeasonPeriod
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// 進行中のシーズンが終了しているかを判定する
+func (ranking *Ranking) SeasonIsOver(now time.Time) bool {
+	return ranking.Season != nil && !now.Before(ranking.Season.EndsAt)
+}
+
+// 進行中のシーズンの番号を返す (周期なしの場合は0)
+func (ranking *Ranking) SeasonNumber() int {
+	if ranking.Season == nil {
+		return 0
+	}
+	return ranking.Season.Number
+}
+
+// 現在時刻を含む期間を次のシーズンとして返す (期間内に実行されなかった周期は飛ばす)
+func (ranking *Ranking) NextSeason(now time.Time) SeasonPeriod {
+	return ranking.Recurrence.SeasonAt(ranking.Season.Number+1, now)
+}
+
+// 数値の文字列をランキングのスコアの型に応じてスコアに変換し、スコアの制約を満たすかを検証する
+func (ranking *Ranking) ParseScore(text string) (Score, error) {
+	score, err := ranking.ScoreType.Parse(text)
+	if err != nil {
+		return 0, err
+	}
+	if err := ranking.ScoreRules.Check(score, ranking.ScoreType); err != nil {
+		return 0, err
+	}
+	return score, nil
+}
@@ -18,12 +18,12 @@ func NewRankingName(name string) (RankingName, error) {
 
 	// ブランク文字、空白文字のみは許容しない
 	if trimmedName == "" {
-		return RankingName{}, fmt.Errorf("ランキング名は空にできません。入力された名前: %q", name)
+		return RankingName{}, NewValidationError("invalid_ranking_name", fmt.Sprintf("ランキング名は空にできません。入力された名前: %q", name))
 	}
 
 	// 50文字を超えたランキング名を許容しない
 	if utf8.RuneCountInString(trimmedName) > 50 {
-		return RankingName{}, fmt.Errorf("ランキング名は50文字以内である必要があります。入力された名前: %q", name)
+		return RankingName{}, NewValidationError("invalid_ranking_name", fmt.Sprintf("ランキング名は50文字以内である必要があります。入力された名前: %q", name))
 	}
 
 	// ランキング名を返却する
@@ -1,18 +1,37 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ランキングリポジトリ (インターフェース)
 type RankingRepositoryInterface interface {
-	// ランキングをIDをキーとして取得する
+	// ランキングをIDをキーとして取得する (存在しない場合はErrNotFound)
 	FindByID(ctx context.Context, id int) (*Ranking, error)
 
-	// ランキングを名前をキーとして取得する
-	FindByName(ctx context.Context, name RankingName) (*Ranking, error)
+	// ランキングを行ロックして取得する (存在しない場合はErrNotFound)
+	// トランザクション内で呼び、コミットまでシーズンを進める処理と直列にする
+	FindByIDForUpdate(ctx context.Context, id int) (*Ranking, error)
 
-	// ランキング一覧を取得する
-	FindAll(ctx context.Context) ([]Ranking, error)
+	// ゲーム内のランキングを名前をキーとして取得する (存在しない場合はnilを返す)
+	FindByName(ctx context.Context, gameID int, name RankingName) (*Ranking, error)
 
-	// ランキングを登録する
-	Create(ctx context.Context, name RankingName) (*Ranking, error)
+	// ランキング一覧をIDの昇順でページングして取得する
+	FindAll(ctx context.Context, page IDPageRequest) (*Page[Ranking], error)
+
+	// ゲームに属するランキング一覧をIDの昇順でページングして取得する
+	FindAllByGameID(ctx context.Context, gameID int, page IDPageRequest) (*Page[Ranking], error)
+
+	// 進行中のシーズンが終了したランキング一覧を取得する
+	FindAllSeasonEnded(ctx context.Context, now time.Time) ([]Ranking, error)
+
+	// ランキングを登録する (IDと登録日時は採番した値を返す。ゲーム内で名前が重複する場合はErrConflict)
+	Create(ctx context.Context, ranking *Ranking) (*Ranking, error)
+
+	// スコアの妥当性のルールを変更する (存在しない場合はErrNotFound)
+	UpdatePlausibilityRules(ctx context.Context, id int, rules PlausibilityRules) (*Ranking, error)
+
+	// ランキングを削除する (ハイスコア、シーズン、送信履歴も削除される。存在しない場合はErrNotFound)
+	Delete(ctx context.Context, id int) error
 }
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// シーズンの周期
+type RecurrencePeriod string
+
+const (
+	// 周期なし (シーズンを区切らない)
+	RecurrenceNone RecurrencePeriod = ""
+
+	// 毎日0時に区切る
+	RecurrenceDaily RecurrencePeriod = "daily"
+
+	// 毎週月曜日の0時に区切る
+	RecurrenceWeekly RecurrencePeriod = "weekly"
+
+	// 毎月1日の0時に区切る
+	RecurrenceMonthly RecurrencePeriod = "monthly"
+)
+
+// シーズンの繰り返し (値オブジェクト)
+// 周期の区切りはタイムゾーンの暦で決める (夏時間でも現地の0時で区切る)
+type Recurrence struct {
+	Period RecurrencePeriod
+
+	// 区切りの基準のタイムゾーン (周期なしの場合はnil)
+	Location *time.Location
+}
+
+// シーズンの繰り返しを生成する (周期が未指定の場合は周期なし、タイムゾーンが未指定の場合はUTC)
+func NewRecurrence(period string, timezone string) (Recurrence, error) {
+	switch RecurrencePeriod(period) {
+	case RecurrenceNone:
+		// 周期なしにタイムゾーンは指定できない
+		if timezone != "" {
+			return Recurrence{}, NewValidationError("invalid_recurrence", fmt.Sprintf("タイムゾーンは周期を指定したランキングにのみ指定できます。入力されたタイムゾーン: %q", timezone))
+		}
+		return Recurrence{}, nil
+	case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+	default:
+		return Recurrence{}, NewValidationError("invalid_recurrence", fmt.Sprintf("周期はdaily、weekly、monthlyのいずれかである必要があります。入力された周期: %q", period))
+	}
+
+	// タイムゾーン (IANAのタイムゾーン名)
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Recurrence{}, NewValidationError("invalid_recurrence", fmt.Sprintf("タイムゾーンが存在しません。入力されたタイムゾーン: %q", timezone))
+	}
+	return Recurrence{Period: RecurrencePeriod(period), Location: location}, nil
+}
+
+// 周期があるかを判定する
+func (recurrence Recurrence) IsRecurring() bool {
+	return recurrence.Period != RecurrenceNone
+}
+
+// タイムゾーン名を返す (周期なしの場合は空文字)
+func (recurrence Recurrence) Timezone() string {
+	if recurrence.Location == nil {
+		return ""
+	}
+	return recurrence.Location.String()
+}
+
+// 時刻を含む期間の開始時刻を返す
+func (recurrence Recurrence) PeriodStart(t time.Time) time.Time {
+	local := t.In(recurrence.Location)
+	year, month, day := local.Date()
+	switch recurrence.Period {
+	case RecurrenceWeekly:
+		// 月曜日を週の始まりとする
+		day -= (int(local.Weekday()) + 6) % 7
+	case RecurrenceMonthly:
+		day = 1
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, recurrence.Location).UTC()
+}
+
+// 期間の開始時刻から次の期間の開始時刻を返す
+func (recurrence Recurrence) NextPeriodStart(start time.Time) time.Time {
+	local := start.In(recurrence.Location)
+	switch recurrence.Period {
+	case RecurrenceWeekly:
+		local = local.AddDate(0, 0, 7)
+	case RecurrenceMonthly:
+		local = local.AddDate(0, 1, 0)
+	default:
+		local = local.AddDate(0, 0, 1)
+	}
+	return recurrence.PeriodStart(local)
+}
+
+// 時刻を含む期間を指定した番号のシーズンとして返す
+func (recurrence Recurrence) SeasonAt(number int, t time.Time) SeasonPeriod {
+	start := recurrence.PeriodStart(t)
+	return SeasonPeriod{
+		Number:    number,
+		StartedAt: start,
+		EndsAt:    recurrence.NextPeriodStart(start),
+	}
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// シーズンの繰り返しの生成
+func TestNewRecurrence(t *testing.T) {
+	// 未指定は周期なし
+	recurrence, err := NewRecurrence("", "")
+	require.NoError(t, err)
+	assert.False(t, recurrence.IsRecurring())
+	assert.Equal(t, "", recurrence.Timezone())
+
+	// タイムゾーンの未指定はUTC
+	recurrence, err = NewRecurrence("weekly", "")
+	require.NoError(t, err)
+	assert.True(t, recurrence.IsRecurring())
+	assert.Equal(t, "UTC", recurrence.Timezone())
+
+	// 不正な周期、存在しないタイムゾーン、周期なしのタイムゾーンは指定できない
+	for _, c := range [][2]string{{"yearly", ""}, {"daily", "Mars/Olympus"}, {"", "Asia/Tokyo"}} {
+		_, err := NewRecurrence(c[0], c[1])
+		assert.ErrorIs(t, err, ErrValidation, c)
+	}
+}
+
+// 期間の区切りはタイムゾーンの暦で決める
+func TestRecurrenceSeasonAt(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	// 2025-01-15 (水) 23:30 JST
+	now := time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		period RecurrencePeriod
+		start  time.Time
+		end    time.Time
+	}{
+		{RecurrenceDaily, time.Date(2025, 1, 15, 0, 0, 0, 0, tokyo), time.Date(2025, 1, 16, 0, 0, 0, 0, tokyo)},
+		{RecurrenceWeekly, time.Date(2025, 1, 13, 0, 0, 0, 0, tokyo), time.Date(2025, 1, 20, 0, 0, 0, 0, tokyo)},
+		{RecurrenceMonthly, time.Date(2025, 1, 1, 0, 0, 0, 0, tokyo), time.Date(2025, 2, 1, 0, 0, 0, 0, tokyo)},
+	}
+	for _, c := range cases {
+		season := Recurrence{Period: c.period, Location: tokyo}.SeasonAt(3, now)
+		assert.Equal(t, 3, season.Number)
+		assert.True(t, c.start.Equal(season.StartedAt), c.period)
+		assert.True(t, c.end.Equal(season.EndsAt), c.period)
+	}
+
+	// 日曜日は前の月曜日から始まる週に含まれる
+	sunday := time.Date(2025, 1, 19, 12, 0, 0, 0, time.UTC)
+	season := Recurrence{Period: RecurrenceWeekly, Location: time.UTC}.SeasonAt(1, sunday)
+	assert.Equal(t, time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), season.StartedAt)
+
+	// 夏時間の切り替わる日も現地の0時で区切る
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	season = Recurrence{Period: RecurrenceDaily, Location: newYork}.SeasonAt(1, time.Date(2025, 3, 9, 12, 0, 0, 0, newYork))
+	assert.Equal(t, 23*time.Hour, season.EndsAt.Sub(season.StartedAt))
+}
+
+// 終了したシーズンの次のシーズンは現在時刻を含む期間になる
+func TestRankingNextSeason(t *testing.T) {
+	recurrence := Recurrence{Period: RecurrenceDaily, Location: time.UTC}
+	started := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := recurrence.SeasonAt(1, started)
+	ranking := &Ranking{Recurrence: recurrence, Season: &current}
+
+	assert.False(t, ranking.SeasonIsOver(started.Add(time.Hour)))
+	assert.True(t, ranking.SeasonIsOver(current.EndsAt))
+
+	// 実行されなかった周期は飛ばす
+	next := ranking.NextSeason(time.Date(2025, 1, 5, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, 2, next.Number)
+	assert.Equal(t, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), next.StartedAt)
+
+	// 周期なしのランキングはシーズンが終了しない
+	assert.False(t, (&Ranking{}).SeasonIsOver(time.Now()))
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// 操作のスコープ (値オブジェクト)
+type Scope string
+
+const (
+	// ゲーム、ランキング、ハイスコアなどの参照
+	ScopeRead Scope = "read"
+
+	// ユーザーの登録とハイスコアの登録
+	ScopeScoresWrite Scope = "scores:write"
+
+	// 既存のユーザーのプレイヤーのトークンの発行 (ゲームクライアントには渡さず、ゲームサーバーのキーのみに付与する)
+	ScopePlayersIssue Scope = "players:issue"
+
+	// ゲームとランキングの管理、ハイスコアとユーザーの削除などのモデレーション
+	ScopeRankingsAdmin Scope = "rankings:admin"
+
+	// APIキーの発行と失効 (全てのゲームを対象とするキーのみ持てる)
+	ScopeKeysAdmin Scope = "keys:admin"
+)
+
+// 全てのスコープ
+var allScopes = []Scope{ScopeRead, ScopeScoresWrite, ScopePlayersIssue, ScopeRankingsAdmin, ScopeKeysAdmin}
+
+// 全てのスコープを返す
+func AllScopes() []Scope {
+	return slices.Clone(allScopes)
+}
+
+// スコープの一覧を生成する (重複は取り除き、指定された順に並べる)
+func NewScopes(values []string) ([]Scope, error) {
+	// 1つ以上のスコープが必要
+	if len(values) == 0 {
+		return nil, NewValidationError("invalid_scope", "スコープを1つ以上指定する必要があります。")
+	}
+
+	scopes := make([]Scope, 0, len(values))
+	for _, value := range values {
+		// 定義されていないスコープは許容しない
+		scope := Scope(value)
+		if !slices.Contains(allScopes, scope) {
+			return nil, NewValidationError("invalid_scope", fmt.Sprintf("スコープは %v のいずれかである必要があります。入力されたスコープ: %q", allScopes, value))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
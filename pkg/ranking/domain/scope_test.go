@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコープの一覧の生成
+func TestNewScopes(t *testing.T) {
+	// 重複は取り除かれ、指定された順に並ぶ
+	scopes, err := NewScopes([]string{"scores:write", "read", "scores:write"})
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeScoresWrite, ScopeRead}, scopes)
+
+	// 空と定義されていないスコープはNG
+	_, err = NewScopes(nil)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewScopes([]string{"read", "write"})
+	assert.ErrorIs(t, err, ErrValidation)
+}
@@ -0,0 +1,5 @@
+package domain
+
+// スコア (値オブジェクト)
+// 小数のスコアはランキングのスコアの型の桁数だけ10倍した整数で保持し、浮動小数点数の誤差なく比較する
+type Score int64
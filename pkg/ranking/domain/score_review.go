@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// 審査の理由の最大文字数
+	MaxReviewReasonLength = 500
+)
+
+// スコアの審査 (エンティティ)
+// 審査待ちの送信に対する承認または却下の判断を記録する
+type ScoreReview struct {
+	ID           int
+	SubmissionID int
+	Decision     ReviewDecision
+
+	// 審査したAPIキーのIDと名前 (管理用のキーの場合はIDがnil)
+	ReviewerAPIKeyID *int
+	Reviewer         string
+
+	// 審査の理由 (未指定の場合は空文字)
+	Reason string
+
+	// 却下と同時にユーザーをシャドウバンしたか
+	ShadowBanned bool
+
+	// 審査待ちにした日時 (送信日時) と審査した日時
+	FlaggedAt  time.Time
+	ReviewedAt time.Time
+}
+
+// 審査の判断 (値オブジェクト)
+type ReviewDecision string
+
+const (
+	// 承認してハイスコアに反映する
+	ReviewApproved ReviewDecision = "approved"
+
+	// 却下してハイスコアに反映しない
+	ReviewRejected ReviewDecision = "rejected"
+)
+
+// 審査の判断に対応する送信の状態を返す
+func (decision ReviewDecision) SubmissionStatus() SubmissionStatus {
+	if decision == ReviewApproved {
+		return SubmissionApproved
+	}
+	return SubmissionRejected
+}
+
+// 審査待ちの送信に対する審査を生成する (審査待ちでない送信は審査できない)
+func NewScoreReview(submission *ScoreSubmission, decision ReviewDecision, reviewer *APIKey, reason string, shadowBan bool) (*ScoreReview, error) {
+	// 審査済みの送信や審査待ちにしていない送信
+	if submission.Status != SubmissionPendingReview {
+		return nil, NewConflictError("submission_not_pending_review", fmt.Sprintf("送信は審査待ちではありません。送信履歴ID: %d, 状態: %s", submission.ID, submission.Status))
+	}
+
+	// 理由の文字数
+	if utf8.RuneCountInString(reason) > MaxReviewReasonLength {
+		return nil, NewValidationError("invalid_review_reason", fmt.Sprintf("審査の理由は%d文字以下である必要があります。", MaxReviewReasonLength))
+	}
+
+	// シャドウバンは却下する場合のみ
+	if shadowBan && decision != ReviewRejected {
+		return nil, NewValidationError("invalid_shadow_ban", "シャドウバンは送信を却下する場合のみ指定できます。")
+	}
+
+	// 管理用のキーは保存されていないためIDを記録しない
+	var reviewerAPIKeyID *int
+	if reviewer.ID != 0 {
+		apiKeyID := reviewer.ID
+		reviewerAPIKeyID = &apiKeyID
+	}
+
+	return &ScoreReview{
+		SubmissionID:     submission.ID,
+		Decision:         decision,
+		ReviewerAPIKeyID: reviewerAPIKeyID,
+		Reviewer:         reviewer.Name.Value,
+		Reason:           reason,
+		ShadowBanned:     shadowBan,
+		FlaggedAt:        submission.SubmittedAt,
+	}, nil
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// スコアの審査リポジトリ (インターフェース)
+type ScoreReviewRepositoryInterface interface {
+	// 審査を記録する (審査日時は保存時に記録する)
+	Create(ctx context.Context, review *ScoreReview) (*ScoreReview, error)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 審査待ちの送信のみ審査でき、審査したキーと送信日時を記録する
+func TestNewScoreReview(t *testing.T) {
+	name, _ := NewAPIKeyName("moderator")
+	reviewer := &APIKey{ID: 3, Name: name}
+	submittedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	submission := &ScoreSubmission{ID: 7, Score: 100, SubmittedAt: submittedAt}
+	submission.HoldForReview([]string{PlausibilityRuleScoreCap})
+
+	review, err := NewScoreReview(submission, ReviewRejected, reviewer, "改造クライアント", true)
+	require.NoError(t, err)
+	assert.Equal(t, 7, review.SubmissionID)
+	assert.Equal(t, 3, *review.ReviewerAPIKeyID)
+	assert.Equal(t, "moderator", review.Reviewer)
+	assert.True(t, review.ShadowBanned)
+	assert.Equal(t, submittedAt, review.FlaggedAt)
+	assert.Equal(t, SubmissionRejected, review.Decision.SubmissionStatus())
+
+	// 管理用のキーはIDを記録しない
+	admin, _ := NewAPIKeyName("admin")
+	review, err = NewScoreReview(submission, ReviewApproved, &APIKey{Name: admin}, "", false)
+	require.NoError(t, err)
+	assert.Nil(t, review.ReviewerAPIKeyID)
+	assert.Equal(t, SubmissionApproved, review.Decision.SubmissionStatus())
+
+	// 承認と同時のシャドウバンや長すぎる理由は不正
+	_, err = NewScoreReview(submission, ReviewApproved, reviewer, "", true)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewScoreReview(submission, ReviewRejected, reviewer, strings.Repeat("a", MaxReviewReasonLength+1), false)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	// 審査待ちでない送信は審査できない
+	_, err = NewScoreReview(&ScoreSubmission{ID: 8, Status: SubmissionAccepted}, ReviewApproved, reviewer, "", false)
+	assert.ErrorIs(t, err, ErrConflict)
+}
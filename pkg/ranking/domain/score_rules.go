@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// スコアの制約 (値オブジェクト)
+// ランキングごとに登録できるスコアの最小値、最大値と刻み幅を表す。未指定の制約はnil
+type ScoreRules struct {
+	// 最小スコア (この値を含む)
+	Min *Score
+
+	// 最大スコア (この値を含む)
+	Max *Score
+
+	// 刻み幅 (最小スコアが指定されている場合は最小スコアから、それ以外は0からの倍数のみ許容する)
+	Step *Score
+}
+
+// スコアの制約を生成する (各制約はスコアの型に応じた数値の文字列で受け取り、空文字の場合は制約なし)
+func NewScoreRules(scoreType ScoreType, min string, max string, step string) (ScoreRules, error) {
+	var rules ScoreRules
+	var err error
+
+	// 最小スコア
+	if rules.Min, err = parseScoreRule(scoreType, "最小スコア", min); err != nil {
+		return ScoreRules{}, err
+	}
+
+	// 最大スコア (最小スコア以上)
+	if rules.Max, err = parseScoreRule(scoreType, "最大スコア", max); err != nil {
+		return ScoreRules{}, err
+	}
+	if rules.Min != nil && rules.Max != nil && *rules.Min > *rules.Max {
+		return ScoreRules{}, NewValidationError("invalid_score_rules", fmt.Sprintf("最大スコアは最小スコア以上である必要があります。入力された最小スコア: %s, 最大スコア: %s", min, max))
+	}
+
+	// 刻み幅 (0より大きい)
+	if rules.Step, err = parseScoreRule(scoreType, "刻み幅", step); err != nil {
+		return ScoreRules{}, err
+	}
+	if rules.Step != nil && *rules.Step <= 0 {
+		return ScoreRules{}, NewValidationError("invalid_score_rules", fmt.Sprintf("刻み幅は0より大きい必要があります。入力された刻み幅: %s", step))
+	}
+
+	return rules, nil
+}
+
+// スコアが制約を満たすかを検証する (満たさない場合はどの制約に違反したかをエラーコードで返す)
+func (rules ScoreRules) Check(score Score, scoreType ScoreType) error {
+	// 最小スコア
+	if rules.Min != nil && score < *rules.Min {
+		return NewValidationError("score_below_min", fmt.Sprintf("スコアは%s以上である必要があります。入力されたスコア: %s", scoreType.Format(*rules.Min), scoreType.Format(score)))
+	}
+
+	// 最大スコア
+	if rules.Max != nil && score > *rules.Max {
+		return NewValidationError("score_above_max", fmt.Sprintf("スコアは%s以下である必要があります。入力されたスコア: %s", scoreType.Format(*rules.Max), scoreType.Format(score)))
+	}
+
+	// 刻み幅 (差を取るとオーバーフローするため剰余どうしを比べる)
+	if rules.Step != nil {
+		var base Score
+		if rules.Min != nil {
+			base = *rules.Min
+		}
+		if remainder(score, *rules.Step) != remainder(base, *rules.Step) {
+			return NewValidationError("score_step_mismatch", fmt.Sprintf("スコアは%sから%s刻みである必要があります。入力されたスコア: %s", scoreType.Format(base), scoreType.Format(*rules.Step), scoreType.Format(score)))
+		}
+	}
+
+	return nil
+}
+
+// 0以上の剰余を返す
+func remainder(score Score, step Score) Score {
+	r := score % step
+	if r < 0 {
+		r += step
+	}
+	return r
+}
+
+// 制約の値をスコアの型に応じて変換する (空文字の場合はnil)
+func parseScoreRule(scoreType ScoreType, label string, text string) (*Score, error) {
+	if text == "" {
+		return nil, nil
+	}
+	score, err := scoreType.Parse(text)
+	if err != nil {
+		// スコアの変換エラーを制約のエラーとして返す
+		var domainError *Error
+		if errors.As(err, &domainError) {
+			return nil, NewValidationError("invalid_score_rules", label+"が不正です。"+domainError.Message)
+		}
+		return nil, err
+	}
+	return &score, nil
+}
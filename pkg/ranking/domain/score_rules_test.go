@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコアの制約の生成
+func TestNewScoreRules(t *testing.T) {
+	decimal := ScoreType{Kind: ScoreKindDecimal, Scale: 2}
+
+	// 未指定の制約はnil
+	rules, err := NewScoreRules(decimal, "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, ScoreRules{}, rules)
+
+	rules, err = NewScoreRules(decimal, "0", "100", "0.25")
+	require.NoError(t, err)
+	assert.Equal(t, Score(0), *rules.Min)
+	assert.Equal(t, Score(10000), *rules.Max)
+	assert.Equal(t, Score(25), *rules.Step)
+
+	// 桁数を超える値、最小スコアより小さい最大スコア、0以下の刻み幅は指定できない
+	for _, c := range [][3]string{
+		{"0.001", "", ""},
+		{"10", "5", ""},
+		{"", "", "0"},
+		{"", "", "-1"},
+	} {
+		_, err := NewScoreRules(decimal, c[0], c[1], c[2])
+		assert.ErrorIs(t, err, ErrValidation, c)
+		assert.Equal(t, "invalid_score_rules", err.(*Error).Code, c)
+	}
+}
+
+// スコアの制約の検証
+func TestScoreRulesCheck(t *testing.T) {
+	integer := ScoreType{Kind: ScoreKindInteger}
+	rules, err := NewScoreRules(integer, "5", "100", "10")
+	require.NoError(t, err)
+
+	// 最小スコアから刻み幅の倍数のみ許容する
+	for _, score := range []Score{5, 15, 95} {
+		assert.NoError(t, rules.Check(score, integer), score)
+	}
+
+	// 違反した制約をエラーコードで返す
+	for _, c := range []struct {
+		score Score
+		code  string
+	}{
+		{-5, "score_below_min"},
+		{105, "score_above_max"},
+		{10, "score_step_mismatch"},
+	} {
+		err := rules.Check(c.score, integer)
+		assert.ErrorIs(t, err, ErrValidation, c.score)
+		assert.Equal(t, c.code, err.(*Error).Code, c.score)
+	}
+
+	// 最小スコアがない場合は0からの倍数で、負の値や端の値でもオーバーフローしない
+	rules, err = NewScoreRules(integer, "", "", "3")
+	require.NoError(t, err)
+	assert.NoError(t, rules.Check(-6, integer))
+	assert.Error(t, rules.Check(-7, integer))
+	rules, err = NewScoreRules(integer, "-9223372036854775807", "", "9223372036854775807")
+	require.NoError(t, err)
+	assert.NoError(t, rules.Check(0, integer))
+	assert.NoError(t, rules.Check(math.MaxInt64, integer))
+	assert.Error(t, rules.Check(1, integer))
+}
+
+// ランキングのスコアの変換は型と制約の両方を検証する
+func TestRankingParseScore(t *testing.T) {
+	scoreType := ScoreType{Kind: ScoreKindDecimal, Scale: 1}
+	rules, err := NewScoreRules(scoreType, "0", "10", "")
+	require.NoError(t, err)
+	ranking := &Ranking{ScoreType: scoreType, ScoreRules: rules}
+
+	score, err := ranking.ParseScore("9.5")
+	require.NoError(t, err)
+	assert.Equal(t, Score(95), score)
+
+	_, err = ranking.ParseScore("9.55")
+	assert.Equal(t, "invalid_score", err.(*Error).Code)
+	_, err = ranking.ParseScore("10.1")
+	assert.Equal(t, "score_above_max", err.(*Error).Code)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "math"
+
+// 標準得点を判定するのに必要なハイスコアの件数
+const MinScoreStatsCount = 10
+
+// ランキングのハイスコアの分布 (値オブジェクト)
+type ScoreStats struct {
+	// ハイスコアの件数
+	Count int
+
+	// 平均
+	Mean float64
+
+	// 標準偏差 (母標準偏差)
+	StdDev float64
+}
+
+// ランキングのハイスコアの分布の集計値 (値オブジェクト)
+// Welfordのアルゴリズムで件数、平均、平均からの偏差の二乗和を逐次更新する (二乗の合計から求める方法と違い、大きなスコアでも桁落ちしない)
+type ScoreMoments struct {
+	Count int
+	Mean  float64
+
+	// 平均からの偏差の二乗和
+	M2 float64
+}
+
+// ハイスコアを加えた集計値を返す
+func (moments ScoreMoments) Add(score Score) ScoreMoments {
+	x := float64(score)
+	count := moments.Count + 1
+	mean := moments.Mean + (x-moments.Mean)/float64(count)
+	return ScoreMoments{Count: count, Mean: mean, M2: moments.M2 + (x-moments.Mean)*(x-mean)}
+}
+
+// ハイスコアを取り除いた集計値を返す
+func (moments ScoreMoments) Remove(score Score) ScoreMoments {
+	if moments.Count <= 1 {
+		return ScoreMoments{}
+	}
+	x := float64(score)
+	count := moments.Count - 1
+	mean := (moments.Mean*float64(moments.Count) - x) / float64(count)
+	return ScoreMoments{Count: count, Mean: mean, M2: moments.M2 - (x-moments.Mean)*(x-mean)}
+}
+
+// ハイスコアを置き換えた集計値を返す (件数は変わらない)
+func (moments ScoreMoments) Replace(previous Score, score Score) ScoreMoments {
+	if moments.Count == 0 {
+		return moments.Add(score)
+	}
+	delta := float64(score) - float64(previous)
+	mean := moments.Mean + delta/float64(moments.Count)
+	return ScoreMoments{Count: moments.Count, Mean: mean, M2: moments.M2 + delta*(float64(score)-mean+float64(previous)-moments.Mean)}
+}
+
+// 集計値から分布を返す
+func (moments ScoreMoments) Stats() ScoreStats {
+	if moments.Count == 0 {
+		return ScoreStats{}
+	}
+	variance := moments.M2 / float64(moments.Count)
+
+	// 丸め誤差で負になった場合は0とする
+	return ScoreStats{Count: moments.Count, Mean: moments.Mean, StdDev: math.Sqrt(math.Max(variance, 0))}
+}
+
+// スコアの標準得点を返す (件数が足りないか、ばらつきがない場合は判定できないためfalse)
+func (stats ScoreStats) ZScore(score Score) (float64, bool) {
+	if stats.Count < MinScoreStatsCount || stats.StdDev == 0 {
+		return 0, false
+	}
+	return (float64(score) - stats.Mean) / stats.StdDev, true
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// クライアント情報の各項目の最大文字数 (超えた分は切り詰めて保存する)
+	MaxClientMetadataLength = 256
+)
+
+// スコアの送信履歴 (エンティティ)
+// ハイスコアを更新したかどうかに関わらず、送信されたスコアをすべて記録する
+type ScoreSubmission struct {
+	ID          int
+	RankingID   int
+	UserID      int
+	Score       Score
+	Client      ClientMetadata
+	SubmittedAt time.Time
+
+	// 受け付けた状態 (審査待ちの場合はハイスコアに反映しない)
+	Status SubmissionStatus
+
+	// 審査待ちにした理由のルールの名前 (受け付けた場合は空)
+	FlaggedRules []string
+}
+
+// スコアの送信の状態 (値オブジェクト)
+type SubmissionStatus string
+
+const (
+	// 受け付けてハイスコアに反映した
+	SubmissionAccepted SubmissionStatus = "accepted"
+
+	// 疑わしいスコアのため審査待ち
+	SubmissionPendingReview SubmissionStatus = "pending_review"
+
+	// 審査で承認してハイスコアに反映した
+	SubmissionApproved SubmissionStatus = "approved"
+
+	// 審査で却下した
+	SubmissionRejected SubmissionStatus = "rejected"
+
+	// シャドウバンしたユーザーの送信のため反映しない (送信したユーザーには審査待ちと同じ結果を返す)
+	SubmissionShadowBanned SubmissionStatus = "shadow_banned"
+)
+
+// スコアを送信したクライアントの情報 (不明な項目は空文字)
+type ClientMetadata struct {
+	IPAddress     string
+	UserAgent     string
+	ClientVersion string
+}
+
+// スコアの送信履歴を生成する
+func NewScoreSubmission(rankingID int, userID int, score Score, client ClientMetadata) *ScoreSubmission {
+	return &ScoreSubmission{
+		RankingID: rankingID,
+		UserID:    userID,
+		Score:     score,
+		Client: ClientMetadata{
+			IPAddress:     truncateClientMetadata(client.IPAddress),
+			UserAgent:     truncateClientMetadata(client.UserAgent),
+			ClientVersion: truncateClientMetadata(client.ClientVersion),
+		},
+		Status: SubmissionAccepted,
+	}
+}
+
+// 疑わしいスコアとして審査待ちにする
+func (submission *ScoreSubmission) HoldForReview(flaggedRules []string) {
+	submission.Status = SubmissionPendingReview
+	submission.FlaggedRules = flaggedRules
+}
+
+// シャドウバンしたユーザーの送信として反映しない
+func (submission *ScoreSubmission) ShadowBan() {
+	submission.Status = SubmissionShadowBanned
+}
+
+// ハイスコアに反映せずに保留したかを判定する (審査待ちとシャドウバン)
+func (submission *ScoreSubmission) IsWithheld() bool {
+	return submission.Status == SubmissionPendingReview || submission.Status == SubmissionShadowBanned
+}
+
+// クライアント情報を最大文字数で切り詰める (送信自体はクライアント情報で拒否しない)
+func truncateClientMetadata(value string) string {
+	if utf8.RuneCountInString(value) <= MaxClientMetadataLength {
+		return value
+	}
+	return string([]rune(value)[:MaxClientMetadataLength])
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "context"
+
+// スコアの送信履歴リポジトリ (インターフェース)
+type ScoreSubmissionRepositoryInterface interface {
+	// 送信履歴を追加する (送信日時は保存時に記録する)
+	Create(ctx context.Context, submission *ScoreSubmission) (*ScoreSubmission, error)
+
+	// 送信履歴を取得する (存在しない場合はErrNotFound)
+	FindByID(ctx context.Context, id int) (*ScoreSubmission, error)
+
+	// ランキングとユーザーを指定して、最後の送信履歴を取得する (未送信の場合はnilを返す)
+	FindLatest(ctx context.Context, rankingID int, userID int) (*ScoreSubmission, error)
+
+	// ランキングとユーザーを指定して、期間内の送信履歴を送信順に取得する
+	FindAllByRankingIDAndUserID(ctx context.Context, rankingID int, userID int, timeRange TimeRange, page IDPageRequest) (*Page[ScoreSubmission], error)
+
+	// 審査待ちの送信履歴を送信順に取得する (論理削除したユーザーを除く)
+	FindAllPendingReview(ctx context.Context, page IDPageRequest) (*Page[ScoreSubmission], error)
+
+	// 審査待ちの送信履歴を審査の結果の状態に更新する
+	// 同時に審査された場合に一方のみ成功するよう、審査待ちでない場合は更新せずにErrConflictを返す
+	Resolve(ctx context.Context, id int, status SubmissionStatus) error
+}
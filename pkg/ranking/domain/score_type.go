@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// スコアの種類
+type ScoreKind string
+
+const (
+	// 整数 (既定)
+	ScoreKindInteger ScoreKind = "integer"
+
+	// 固定小数点数 (小数点以下の桁数はランキングごとに指定する)
+	ScoreKindDecimal ScoreKind = "decimal"
+
+	// 所要時間 (ミリ秒)
+	ScoreKindDuration ScoreKind = "duration"
+)
+
+// 固定小数点数の小数点以下の最大の桁数
+const maxScoreScale = 6
+
+// スコアの型 (値オブジェクト)
+type ScoreType struct {
+	Kind ScoreKind
+
+	// 小数点以下の桁数 (固定小数点数以外は0)
+	Scale int
+}
+
+// スコアの型を生成する (種類が未指定の場合は整数)
+func NewScoreType(kind string, scale int) (ScoreType, error) {
+	switch ScoreKind(kind) {
+	case "", ScoreKindInteger, ScoreKindDuration:
+		// 整数と所要時間は小数点以下の桁数を持たない
+		if scale != 0 {
+			return ScoreType{}, NewValidationError("invalid_score_type", fmt.Sprintf("小数点以下の桁数は固定小数点数のスコアにのみ指定できます。入力された桁数: %d", scale))
+		}
+		if kind == "" {
+			return ScoreType{Kind: ScoreKindInteger}, nil
+		}
+		return ScoreType{Kind: ScoreKind(kind)}, nil
+	case ScoreKindDecimal:
+		// 固定小数点数は1桁以上、最大の桁数以下
+		if scale < 1 || scale > maxScoreScale {
+			return ScoreType{}, NewValidationError("invalid_score_type", fmt.Sprintf("小数点以下の桁数は1から%dである必要があります。入力された桁数: %d", maxScoreScale, scale))
+		}
+		return ScoreType{Kind: ScoreKindDecimal, Scale: scale}, nil
+	}
+
+	// それ以外の種類は許容しない
+	return ScoreType{}, NewValidationError("invalid_score_type", fmt.Sprintf("スコアの種類はinteger、decimal、durationのいずれかである必要があります。入力された種類: %q", kind))
+}
+
+// 数値の文字列をスコアの型に応じてスコアに変換する
+// 浮動小数点数を経由せず10進数のまま変換するため丸め誤差が発生しない
+func (scoreType ScoreType) Parse(text string) (Score, error) {
+	// 符号を取り除く
+	digits, negative := strings.CutPrefix(text, "-")
+
+	// 整数部と小数部に分ける (指数表記は許容しない)
+	integerPart, fractionPart, hasPoint := strings.Cut(digits, ".")
+	if !isDigits(integerPart) || (hasPoint && !isDigits(fractionPart)) {
+		return 0, invalidScoreError(text, "数値である必要があります")
+	}
+
+	// 桁数を超える小数部は0のみ許容する (丸めない)
+	if len(fractionPart) > scoreType.Scale {
+		if strings.Trim(fractionPart[scoreType.Scale:], "0") != "" {
+			return 0, invalidScoreError(text, fmt.Sprintf("小数点以下は%d桁以内である必要があります", scoreType.Scale))
+		}
+		fractionPart = fractionPart[:scoreType.Scale]
+	}
+	fractionPart += strings.Repeat("0", scoreType.Scale-len(fractionPart))
+
+	// 桁数だけ10倍した整数に変換する
+	value, err := strconv.ParseInt(integerPart+fractionPart, 10, 64)
+	if err != nil {
+		return 0, invalidScoreError(text, "扱える範囲を超えています")
+	}
+	if negative {
+		// 所要時間は負の値を許容しない
+		if scoreType.Kind == ScoreKindDuration && value != 0 {
+			return 0, invalidScoreError(text, "所要時間は0以上である必要があります")
+		}
+		value = -value
+	}
+	return Score(value), nil
+}
+
+// スコアをスコアの型に応じた数値の文字列に変換する (固定小数点数は小数点以下を桁数分だけ表示する)
+func (scoreType ScoreType) Format(score Score) string {
+	if scoreType.Scale == 0 {
+		return strconv.FormatInt(int64(score), 10)
+	}
+
+	// 絶対値を桁数+1桁以上に0埋めして小数点を挿入する
+	sign := ""
+	abs := uint64(score)
+	if score < 0 {
+		sign = "-"
+		abs = -abs
+	}
+	digits := strconv.FormatUint(abs, 10)
+	if len(digits) <= scoreType.Scale {
+		digits = strings.Repeat("0", scoreType.Scale-len(digits)+1) + digits
+	}
+	point := len(digits) - scoreType.Scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// 1文字以上の数字のみで構成されているかを判定する
+func isDigits(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// スコアが不正なエラーを生成する
+func invalidScoreError(text string, reason string) error {
+	return NewValidationError("invalid_score", fmt.Sprintf("スコアは%s。入力されたスコア: %q", reason, text))
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコアの型の生成
+func TestNewScoreType(t *testing.T) {
+	// 未指定は整数
+	scoreType, err := NewScoreType("", 0)
+	require.NoError(t, err)
+	assert.Equal(t, ScoreType{Kind: ScoreKindInteger}, scoreType)
+
+	scoreType, err = NewScoreType("decimal", 3)
+	require.NoError(t, err)
+	assert.Equal(t, ScoreType{Kind: ScoreKindDecimal, Scale: 3}, scoreType)
+
+	// 固定小数点数以外に桁数は指定できず、固定小数点数は桁数が必要
+	_, err = NewScoreType("duration", 3)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewScoreType("decimal", 0)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewScoreType("decimal", 7)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewScoreType("float", 0)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+// スコアの型に応じた変換
+func TestScoreTypeParse(t *testing.T) {
+	integer := ScoreType{Kind: ScoreKindInteger}
+	decimal := ScoreType{Kind: ScoreKindDecimal, Scale: 3}
+	duration := ScoreType{Kind: ScoreKindDuration}
+
+	cases := []struct {
+		scoreType ScoreType
+		text      string
+		expected  Score
+	}{
+		{integer, "100", 100},
+		{integer, "-5", -5},
+		{integer, "100.00", 100},
+		{decimal, "12.5", 12500},
+		{decimal, "0.1", 100},
+		{decimal, "-0.001", -1},
+		{decimal, "7", 7000},
+		{decimal, "1.2300", 1230},
+		{duration, "83456", 83456},
+		{integer, "9223372036854775807", math.MaxInt64},
+	}
+	for _, c := range cases {
+		score, err := c.scoreType.Parse(c.text)
+		require.NoError(t, err, c.text)
+		assert.Equal(t, c.expected, score, c.text)
+	}
+
+	// 丸めが必要な値、数値でない値、範囲外の値は変換できない
+	for _, c := range []struct {
+		scoreType ScoreType
+		text      string
+	}{
+		{integer, "1.5"},
+		{decimal, "0.0001"},
+		{decimal, "1e3"},
+		{decimal, ".5"},
+		{decimal, "1."},
+		{integer, ""},
+		{integer, "abc"},
+		{duration, "-1"},
+		{integer, "9223372036854775808"},
+	} {
+		_, err := c.scoreType.Parse(c.text)
+		assert.ErrorIs(t, err, ErrValidation, c.text)
+	}
+}
+
+// スコアの型に応じた表示
+func TestScoreTypeFormat(t *testing.T) {
+	decimal := ScoreType{Kind: ScoreKindDecimal, Scale: 3}
+	assert.Equal(t, "12.500", decimal.Format(12500))
+	assert.Equal(t, "0.001", decimal.Format(1))
+	assert.Equal(t, "-0.001", decimal.Format(-1))
+	assert.Equal(t, "0.000", decimal.Format(0))
+	assert.Equal(t, "-9223372036854775.808", decimal.Format(math.MinInt64))
+	assert.Equal(t, "100", ScoreType{Kind: ScoreKindInteger}.Format(100))
+	assert.Equal(t, "83456", ScoreType{Kind: ScoreKindDuration}.Format(83456))
+
+	// 変換して表示すると桁数分の表記に揃う
+	score, err := decimal.Parse("0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "0.100", decimal.Format(score))
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// 進行中のシーズンの期間 (値オブジェクト)
+type SeasonPeriod struct {
+	// シーズンの番号 (1から始まる)
+	Number int
+
+	// 開始時刻 (この時刻を含む)
+	StartedAt time.Time
+
+	// 終了時刻 (この時刻を含まない。次のシーズンの開始時刻)
+	EndsAt time.Time
+}
+
+// 終了したシーズン (エンティティ)
+// 終了時点のユーザーハイスコアの順位をアーカイブとして保持する
+type Season struct {
+	RankingID  int
+	Number     int
+	StartedAt  time.Time
+	EndedAt    time.Time
+	TotalUsers int
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// シーズンリポジトリ (インターフェース)
+type SeasonRepositoryInterface interface {
+	// ランキングの終了したシーズンを番号をキーとして取得する (存在しない場合はErrNotFound)
+	FindByNumber(ctx context.Context, rankingID int, number int) (*Season, error)
+
+	// ランキングの終了したシーズン一覧を番号の昇順でページングして取得する (ページのカーソルはシーズンの番号)
+	FindAllByRankingID(ctx context.Context, rankingID int, page IDPageRequest) (*Page[Season], error)
+
+	// 進行中のシーズンを終了して次のシーズンを開始する
+	// 現在のユーザーハイスコアを順位付きでアーカイブし、ランキングのユーザーハイスコアを空にする処理をアトミックに行う
+	// 他の処理が先にシーズンを進めていた場合はErrConflict
+	Archive(ctx context.Context, ranking *Ranking, next SeasonPeriod) (*Season, error)
+}
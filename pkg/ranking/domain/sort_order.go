@@ -0,0 +1,35 @@
+package domain
+
+import "fmt"
+
+// ランキングの並び順 (値オブジェクト)
+type SortOrder string
+
+const (
+	// スコアが大きいほど上位 (既定)
+	SortOrderDesc SortOrder = "desc"
+
+	// スコアが小さいほど上位 (タイムアタックやゴルフなど)
+	SortOrderAsc SortOrder = "asc"
+)
+
+// 並び順を生成する (未指定の場合はスコアが大きいほど上位)
+func NewSortOrder(value string) (SortOrder, error) {
+	switch SortOrder(value) {
+	case "":
+		return SortOrderDesc, nil
+	case SortOrderDesc, SortOrderAsc:
+		return SortOrder(value), nil
+	}
+
+	// それ以外の並び順は許容しない
+	return "", NewValidationError("invalid_sort_order", fmt.Sprintf("並び順はdescかascである必要があります。入力された並び順: %q", value))
+}
+
+// スコアがもう一方のスコアより上位かを判定する (同点の場合はfalse)
+func (sortOrder SortOrder) Beats(score Score, other Score) bool {
+	if sortOrder == SortOrderAsc {
+		return score < other
+	}
+	return score > other
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 並び順の生成
+func TestNewSortOrder(t *testing.T) {
+	// 未指定はスコアが大きいほど上位
+	sortOrder, err := NewSortOrder("")
+	require.NoError(t, err)
+	assert.Equal(t, SortOrderDesc, sortOrder)
+
+	sortOrder, err = NewSortOrder("asc")
+	require.NoError(t, err)
+	assert.Equal(t, SortOrderAsc, sortOrder)
+
+	// それ以外はバリデーションエラー
+	_, err = NewSortOrder("ASC")
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+// 並び順に応じたスコアの比較
+func TestSortOrderBeats(t *testing.T) {
+	assert.True(t, SortOrderDesc.Beats(101, 100), "Expected higher score to win in desc order")
+	assert.False(t, SortOrderDesc.Beats(99, 100), "Expected lower score to lose in desc order")
+	assert.True(t, SortOrderAsc.Beats(99, 100), "Expected lower score to win in asc order")
+	assert.False(t, SortOrderAsc.Beats(101, 100), "Expected higher score to lose in asc order")
+
+	// 同点はどちらの並び順でも上位としない
+	assert.False(t, SortOrderDesc.Beats(100, 100))
+	assert.False(t, SortOrderAsc.Beats(100, 100))
+}
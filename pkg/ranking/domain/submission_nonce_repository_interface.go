@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// スコアの送信の署名の使用済みのナンスのリポジトリ (インターフェース)
+type SubmissionNonceRepositoryInterface interface {
+	// ゲームのナンスを期限まで使用済みにする (期限内に使用済みの場合はErrConflict)
+	// 送信と同一トランザクション内で記録し、送信がロールバックした場合はナンスも未使用に戻る
+	Use(ctx context.Context, gameID int, nonce string, expiresAt time.Time) error
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ナンスの書式 (英数字、ハイフン、アンダースコアの1〜64文字)
+var submissionNoncePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// スコアの送信の署名 (値オブジェクト)
+// ゲームのクライアントがゲームの秘密鍵で (ランキングID、ユーザーID、スコア、ナンス、タイムスタンプ) にHMAC-SHA256で署名する
+type SubmissionSignature struct {
+	// 送信ごとに一意な文字列 (同じナンスの再送を拒否する)
+	Nonce string
+
+	// 署名した日時 (UNIX時間の秒)
+	Timestamp int64
+
+	// 署名 (16進数の文字列)
+	Signature string
+}
+
+// スコアの送信の署名を生成する
+func NewSubmissionSignature(nonce string, timestamp string, signature string) (*SubmissionSignature, error) {
+	// ナンスの書式を確認する
+	if !submissionNoncePattern.MatchString(nonce) {
+		return nil, NewValidationError("invalid_submission_nonce", "ナンスは英数字、ハイフン、アンダースコアの1〜64文字で指定してください。")
+	}
+
+	// タイムスタンプはUNIX時間の秒で受け取る
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, NewValidationError("invalid_submission_timestamp", fmt.Sprintf("タイムスタンプはUNIX時間の秒で指定してください。入力された値: %q", timestamp))
+	}
+
+	return &SubmissionSignature{
+		Nonce:     nonce,
+		Timestamp: unix,
+		Signature: signature,
+	}, nil
+}
+
+// 署名の対象の文字列を返す (各項目を改行で区切る。スコアは送信された数値の表記のまま)
+func SubmissionSigningInput(rankingID int, userID int, score string, nonce string, timestamp int64) string {
+	return fmt.Sprintf("%d\n%d\n%s\n%s\n%d", rankingID, userID, score, nonce, timestamp)
+}
+
+// 秘密鍵で署名する
+func SignSubmission(secret string, rankingID int, userID int, score string, nonce string, timestamp int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(SubmissionSigningInput(rankingID, userID, score, nonce, timestamp)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// 署名を検証する
+// 改ざんを時計のずれと区別するため、先に署名を検証してからタイムスタンプが現在時刻の前後window以内であることを確認する
+func (s *SubmissionSignature) Verify(secret string, rankingID int, userID int, score string, now time.Time, window time.Duration) error {
+	// 署名が一致しない場合は改ざんとみなす
+	expected, _ := hex.DecodeString(SignSubmission(secret, rankingID, userID, score, s.Nonce, s.Timestamp))
+	actual, err := hex.DecodeString(s.Signature)
+	if err != nil || !hmac.Equal(expected, actual) {
+		return NewUnauthorizedError("invalid_submission_signature", "スコアの送信の署名が一致しません。")
+	}
+
+	// タイムスタンプが古すぎるか未来すぎる場合は時計のずれとみなす
+	skew := now.Sub(s.SignedAt())
+	if skew < -window || skew > window {
+		return NewUnauthorizedError("submission_timestamp_skewed", fmt.Sprintf("スコアの送信のタイムスタンプが現在時刻から %s 以上ずれています。タイムスタンプ: %d", window, s.Timestamp))
+	}
+	return nil
+}
+
+// 署名した日時を返す
+func (s *SubmissionSignature) SignedAt() time.Time {
+	return time.Unix(s.Timestamp, 0)
+}
+
+// 署名を受け付ける期限を返す (これより後は同じナンスを再送してもタイムスタンプで拒否される)
+func (s *SubmissionSignature) ExpiresAt(window time.Duration) time.Time {
+	return s.SignedAt().Add(window)
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 署名のエラーコードを確認する
+func assertSignatureErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var domainError *Error
+	require.ErrorAs(t, err, &domainError)
+	assert.Equal(t, code, domainError.Code)
+}
+
+// スコアの送信の署名の生成
+func TestNewSubmissionSignature(t *testing.T) {
+	signature, err := NewSubmissionSignature("nonce-1", "1735560000", "abcd")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1735560000), signature.Timestamp)
+
+	// ナンスの書式とタイムスタンプの書式はNG
+	_, err = NewSubmissionSignature("", "1735560000", "abcd")
+	assertSignatureErrorCode(t, err, "invalid_submission_nonce")
+	_, err = NewSubmissionSignature("nonce 1", "1735560000", "abcd")
+	assertSignatureErrorCode(t, err, "invalid_submission_nonce")
+	_, err = NewSubmissionSignature("nonce-1", "2024-12-30T12:00:00Z", "abcd")
+	assertSignatureErrorCode(t, err, "invalid_submission_timestamp")
+}
+
+// 署名の検証は改ざんと時計のずれを区別する
+func TestSubmissionSignatureVerify(t *testing.T) {
+	const secret = "secret"
+	now := time.Unix(1735560000, 0)
+	window := 5 * time.Minute
+	sign := func(score string, timestamp time.Time) *SubmissionSignature {
+		signature, err := NewSubmissionSignature("nonce-1", strconv.FormatInt(timestamp.Unix(), 10), SignSubmission(secret, 1, 2, score, "nonce-1", timestamp.Unix()))
+		require.NoError(t, err)
+		return signature
+	}
+
+	// 正しい署名
+	assert.NoError(t, sign("100", now).Verify(secret, 1, 2, "100", now, window))
+	assert.NoError(t, sign("100", now.Add(-window)).Verify(secret, 1, 2, "100", now, window))
+
+	// スコア、ユーザー、秘密鍵が異なる場合は改ざん
+	assertSignatureErrorCode(t, sign("100", now).Verify(secret, 1, 2, "1000", now, window), "invalid_submission_signature")
+	assertSignatureErrorCode(t, sign("100", now).Verify(secret, 1, 3, "100", now, window), "invalid_submission_signature")
+	assertSignatureErrorCode(t, sign("100", now).Verify("other", 1, 2, "100", now, window), "invalid_submission_signature")
+
+	// タイムスタンプを書き換えた場合も改ざん
+	tampered := sign("100", now.Add(-time.Hour))
+	tampered.Timestamp = now.Unix()
+	assertSignatureErrorCode(t, tampered.Verify(secret, 1, 2, "100", now, window), "invalid_submission_signature")
+
+	// 署名が正しくタイムスタンプが古すぎるか未来すぎる場合は時計のずれ
+	err := sign("100", now.Add(-window-time.Second)).Verify(secret, 1, 2, "100", now, window)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assertSignatureErrorCode(t, err, "submission_timestamp_skewed")
+	assertSignatureErrorCode(t, sign("100", now.Add(window+time.Second)).Verify(secret, 1, 2, "100", now, window), "submission_timestamp_skewed")
+}
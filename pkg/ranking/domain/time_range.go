@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// 期間の絞り込み (開始日時を含み、終了日時を含まない。nilの場合は制限なし)
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// 期間を生成する
+func NewTimeRange(from *time.Time, to *time.Time) (TimeRange, error) {
+	// 開始日時は終了日時より前である必要がある
+	if from != nil && to != nil && !from.Before(*to) {
+		return TimeRange{}, NewValidationError("invalid_time_range", fmt.Sprintf("開始日時は終了日時より前を指定してください。開始日時: %s, 終了日時: %s", from.Format(time.RFC3339), to.Format(time.RFC3339)))
+	}
+	return TimeRange{From: from, To: to}, nil
+}
+
+// 日時が期間に含まれるかを判定する
+func (timeRange TimeRange) Contains(t time.Time) bool {
+	if timeRange.From != nil && t.Before(*timeRange.From) {
+		return false
+	}
+	if timeRange.To != nil && !t.Before(*timeRange.To) {
+		return false
+	}
+	return true
+}
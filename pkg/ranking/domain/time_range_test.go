@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 期間の生成
+func TestNewTimeRange(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	// 開始日時は終了日時より前である必要がある
+	_, err := NewTimeRange(&to, &from)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewTimeRange(&from, &from)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	// 片方だけの指定や指定なしは制限なしとして扱う
+	_, err = NewTimeRange(&from, nil)
+	assert.NoError(t, err)
+	_, err = NewTimeRange(nil, nil)
+	assert.NoError(t, err)
+}
+
+// 期間に含まれるかの判定
+func TestTimeRangeContains(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	timeRange, err := NewTimeRange(&from, &to)
+	require.NoError(t, err)
+
+	// 開始日時を含み、終了日時を含まない
+	assert.True(t, timeRange.Contains(from))
+	assert.True(t, timeRange.Contains(to.Add(-time.Nanosecond)))
+	assert.False(t, timeRange.Contains(to))
+	assert.False(t, timeRange.Contains(from.Add(-time.Nanosecond)))
+	assert.True(t, TimeRange{}.Contains(from))
+}
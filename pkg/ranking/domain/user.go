@@ -8,4 +8,20 @@ type User struct {
 	Name      UserName
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// 論理削除した日時 (削除されていない場合はnil)
+	DeletedAt *time.Time
+
+	// シャドウバンした日時 (シャドウバンしていない場合はnil)
+	ShadowBannedAt *time.Time
+}
+
+// 論理削除されているかを判定する
+func (user *User) IsDeleted() bool {
+	return user.DeletedAt != nil
+}
+
+// シャドウバンされているかを判定する (以降の送信はハイスコアに反映しない)
+func (user *User) IsShadowBanned() bool {
+	return user.ShadowBannedAt != nil
 }
@@ -1,17 +1,26 @@
 package domain
 
+import "time"
+
 // ユーザーハイスコア (エンティティ)
 type UserHighScore struct {
 	RankingID int
 	UserID    int
-	Score     int
+	Score     Score
+	Timestamp time.Time
 }
 
 // ユーザーハイスコアを生成する
-func NewUserHighScore(rankingID int, userID int, score int) *UserHighScore {
+func NewUserHighScore(rankingID int, userID int, score Score) *UserHighScore {
 	return &UserHighScore{
 		RankingID: rankingID,
 		UserID:    userID,
 		Score:     score,
 	}
 }
+
+// 新しいスコアがランキングの並び順でハイスコアを更新するかを判定する
+// 同点の場合は登録日時が古い方を優先するため更新しない
+func (userHighScore *UserHighScore) IsBeatenBy(score Score, sortOrder SortOrder) bool {
+	return sortOrder.Beats(score, userHighScore.Score)
+}
@@ -1,12 +1,28 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ユーザーハイスコアリポジトリ (インターフェース)
 type UserHighScoreRepositoryInterface interface {
-	// ユーザーハイスコアを取得する
+	// ユーザーハイスコアを取得する (未登録の場合はnilを返す)
 	Find(ctx context.Context, rankingID int, userID int) (*UserHighScore, error)
 
 	// ユーザーハイスコアを保存する
-	Store(ctx context.Context, rankingID int, userID int, score int) error
+	// 未登録なら登録し、ランキングの並び順で既存のハイスコアを上回る場合のみ更新する処理をアトミックに行う
+	Upsert(ctx context.Context, rankingID int, userID int, score Score, sortOrder SortOrder) (*UserHighScoreUpsertResult, error)
+
+	// 登録日時を指定してユーザーハイスコアを保存する (審査で承認したスコアを送信日時の順で同点のユーザーと比べるために使う)
+	UpsertAt(ctx context.Context, rankingID int, userID int, score Score, sortOrder SortOrder, timestamp time.Time) (*UserHighScoreUpsertResult, error)
+
+	// ユーザーの全てのランキングのハイスコアを取得する
+	FindAllByUserID(ctx context.Context, userID int) ([]UserHighScore, error)
+
+	// ランキングのハイスコアの分布を取得する (論理削除したユーザーを除く)
+	FetchScoreStats(ctx context.Context, rankingID int) (ScoreStats, error)
+
+	// ユーザーハイスコアを削除する (未登録の場合はErrNotFound)
+	Delete(ctx context.Context, rankingID int, userID int) error
 }
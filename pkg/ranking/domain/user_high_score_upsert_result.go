@@ -0,0 +1,13 @@
+package domain
+
+// ユーザーハイスコア保存結果
+type UserHighScoreUpsertResult struct {
+	// 登録結果
+	Outcome HighScoreOutcome
+
+	// 保存前のハイスコア (未登録の場合はnil)
+	PreviousScore *Score
+
+	// 保存後のハイスコア
+	HighScore UserHighScore
+}
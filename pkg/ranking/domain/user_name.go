@@ -18,12 +18,12 @@ func NewUserName(name string) (UserName, error) {
 
 	// ブランク文字、空白文字のみは許容しない
 	if trimmedName == "" {
-		return UserName{}, fmt.Errorf("ユーザー名は空にできません。入力された名前: %q", name)
+		return UserName{}, NewValidationError("invalid_user_name", fmt.Sprintf("ユーザー名は空にできません。入力された名前: %q", name))
 	}
 
 	// 30文字を超えたユーザー名を許容しない
 	if utf8.RuneCountInString(trimmedName) > 30 {
-		return UserName{}, fmt.Errorf("ユーザー名は30文字以内である必要があります。入力された名前: %q", name)
+		return UserName{}, NewValidationError("invalid_user_name", fmt.Sprintf("ユーザー名は30文字以内である必要があります。入力された名前: %q", name))
 	}
 
 	// ユーザー名を返却する
@@ -4,12 +4,21 @@ import "context"
 
 // ユーザーリポジトリ (インターフェース)
 type UserRepositoryInterface interface {
-	// ユーザーを取得する
+	// ユーザーを取得する (存在しない場合と削除済みの場合はErrNotFound)
 	FindByID(ctx context.Context, id int) (*User, error)
 
-	// ユーザー一覧を取得する
-	FindAll(ctx context.Context) ([]User, error)
+	// 削除済みでないユーザー一覧をIDの昇順でページングして取得する
+	FindAll(ctx context.Context, page IDPageRequest) (*Page[User], error)
 
 	// ユーザーを登録する
 	Create(ctx context.Context, name UserName) (*User, error)
+
+	// ユーザーを論理削除する (ハイスコアは残したまま全てのランキングから除外される。存在しない場合と削除済みの場合はErrNotFound)
+	Delete(ctx context.Context, id int) error
+
+	// 論理削除したユーザーを元に戻す (削除済みでない場合はそのまま返す。存在しない場合はErrNotFound)
+	Restore(ctx context.Context, id int) (*User, error)
+
+	// ユーザーをシャドウバンする (シャドウバン済みの場合は日時を変えない。存在しない場合と削除済みの場合はErrNotFound)
+	ShadowBan(ctx context.Context, id int) error
 }
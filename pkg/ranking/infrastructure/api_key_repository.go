@@ -0,0 +1,183 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// APIキー (スコープはカンマ区切りで保存する)
+type APIKey struct {
+	ID        int        `bun:"id,pk,autoincrement"`
+	Name      string     `bun:"name"`
+	KeyPrefix string     `bun:"key_prefix"`
+	KeyHash   string     `bun:"key_hash"`
+	Scopes    string     `bun:"scopes"`
+	GameID    *int       `bun:"game_id"`
+	CreatedAt time.Time  `bun:"created_at,nullzero,default:CURRENT_TIMESTAMP"`
+	RevokedAt *time.Time `bun:"revoked_at"`
+}
+
+// APIキーリポジトリ
+type APIKeyRepository struct {
+	db bun.IDB
+}
+
+// リポジトリを生成する
+func NewAPIKeyRepository(bun bun.IDB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: bun,
+	}
+}
+
+// キーのハッシュ値でAPIキーを取得する
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	// APIキー
+	apiKey := new(APIKey)
+
+	// クエリ実行
+	err := dbFromContext(ctx, r.db).NewSelect().Model(apiKey).Where("key_hash = ?", hash).Scan(ctx)
+
+	// 存在しない場合はnilを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのAPIキーを返す
+	return toDomainAPIKey(apiKey)
+}
+
+// APIキー一覧を取得する
+func (r *APIKeyRepository) FindAll(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.APIKey], error) {
+	// APIキースライス
+	var apiKeys []APIKey
+
+	// クエリ実行
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&apiKeys)
+	err := applyIDPage(q, "id", page).Scan(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメイン層のAPIキー構造体にマッピング
+	domainAPIKeys := make([]domain.APIKey, 0, len(apiKeys))
+	for i := range apiKeys {
+		// APIキー
+		apiKey, err := toDomainAPIKey(&apiKeys[i])
+
+		// エラーハンドリング
+		if err != nil {
+			return nil, err
+		}
+
+		// domainAPIKeysに詰める
+		domainAPIKeys = append(domainAPIKeys, *apiKey)
+	}
+
+	// ドメインのAPIキー一覧をページとして返す
+	return toPage(domainAPIKeys, page.Limit, page.Direction), nil
+}
+
+// APIキーを登録する
+func (r *APIKeyRepository) Create(ctx context.Context, apiKey *domain.APIKey) (*domain.APIKey, error) {
+	// APIキー構造体を生成
+	scopes := make([]string, 0, len(apiKey.Scopes))
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	newAPIKey := &APIKey{
+		Name:      apiKey.Name.Value,
+		KeyPrefix: apiKey.Prefix,
+		KeyHash:   apiKey.Hash,
+		Scopes:    strings.Join(scopes, ","),
+		GameID:    apiKey.GameID,
+	}
+
+	// APIキー登録クエリを実行
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(newAPIKey).Exec(ctx)
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// 挿入後に ID を基に再取得
+	err = dbFromContext(ctx, r.db).NewSelect().Model(newAPIKey).Where("id = ?", newAPIKey.ID).Scan(ctx)
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのAPIキーを返す
+	return toDomainAPIKey(newAPIKey)
+}
+
+// APIキーを失効させる
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int) error {
+	// 失効した日時を記録するクエリを実行
+	result, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*APIKey)(nil)).
+		Set("revoked_at = ?", time.Now().UTC()).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Exec(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return err
+	}
+
+	// 対象が存在しない場合と失効済みの場合はNotFoundエラーを返す
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.NewNotFoundError("api_key_not_found", fmt.Sprintf("APIキーが存在しないか失効しています。APIキーID: %d", id))
+	}
+
+	return nil
+}
+
+// ドメインのAPIキーにマッピングする
+func toDomainAPIKey(apiKey *APIKey) (*domain.APIKey, error) {
+	// APIキー名
+	apiKeyName, err := domain.NewAPIKeyName(apiKey.Name)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// スコープ
+	scopes, err := domain.NewScopes(strings.Split(apiKey.Scopes, ","))
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	return &domain.APIKey{
+		ID:        apiKey.ID,
+		Name:      apiKeyName,
+		Prefix:    apiKey.KeyPrefix,
+		Hash:      apiKey.KeyHash,
+		Scopes:    scopes,
+		GameID:    apiKey.GameID,
+		CreatedAt: apiKey.CreatedAt,
+		RevokedAt: apiKey.RevokedAt,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// APIキーをハッシュ値で取得でき、失効した日時が記録される
+func TestAPIKeyRepository(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+	gameName, _ := domain.NewGameName(fmt.Sprintf("api-key-%d", suffix))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+
+	// ゲームを限定したキーを登録する
+	repository := NewAPIKeyRepository(db)
+	name, _ := domain.NewAPIKeyName("client")
+	hash := fmt.Sprintf("%064d", suffix)
+	newAPIKey, err := domain.NewAPIKey(name, []domain.Scope{domain.ScopeRead, domain.ScopeScoresWrite}, &game.ID, "grk_abcdefgh", hash)
+	require.NoError(t, err)
+	created, err := repository.Create(ctx, newAPIKey)
+	require.NoError(t, err)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	// ハッシュ値で取得できる
+	found, err := repository.FindByHash(ctx, hash)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, created.ID, found.ID)
+	assert.Equal(t, []domain.Scope{domain.ScopeRead, domain.ScopeScoresWrite}, found.Scopes)
+	require.NotNil(t, found.GameID)
+	assert.Equal(t, game.ID, *found.GameID)
+	missing, err := repository.FindByHash(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// 失効すると失効した日時が記録され、再度の失効はNotFound
+	require.NoError(t, repository.Revoke(ctx, created.ID))
+	found, err = repository.FindByHash(ctx, hash)
+	require.NoError(t, err)
+	assert.True(t, found.IsRevoked())
+	assert.ErrorIs(t, repository.Revoke(ctx, created.ID), domain.ErrNotFound)
+
+	// ゲームを削除するとゲームを限定したキーも削除される
+	require.NoError(t, NewGameRepository(db).Delete(ctx, game.ID))
+	found, err = repository.FindByHash(ctx, hash)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
@@ -0,0 +1,68 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/denisenkom/go-mssqldb" // SQL Server用のドライバ
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mssqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+// データベースの種類
+type Dialect string
+
+const (
+	// SQL Server
+	DialectSQLServer Dialect = "sqlserver"
+
+	// PostgreSQL
+	DialectPostgres Dialect = "postgres"
+
+	// SQLite
+	DialectSQLite Dialect = "sqlite"
+)
+
+// データベースの種類に応じたドライバとBunのダイアレクトで接続する
+func OpenDB(dialect Dialect, dsn string) (*bun.DB, error) {
+	switch dialect {
+	case DialectSQLServer:
+		sqldb, err := sql.Open("sqlserver", dsn)
+		if err != nil {
+			return nil, err
+		}
+		return bun.NewDB(sqldb, mssqldialect.New()), nil
+	case DialectPostgres:
+		sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn)))
+		return bun.NewDB(sqldb, pgdialect.New()), nil
+	case DialectSQLite:
+		sqldb, err := sql.Open(sqliteshim.ShimName, sqliteDSNWithForeignKeys(dsn))
+		if err != nil {
+			return nil, err
+		}
+		// SQLiteは書き込みが直列なため、接続を1本にしてロック待ちのエラーを防ぐ (インメモリのデータベースも接続ごとに分かれない)
+		sqldb.SetMaxOpenConns(1)
+		return bun.NewDB(sqldb, sqlitedialect.New()), nil
+	default:
+		return nil, fmt.Errorf("unsupported database dialect: %q", dialect)
+	}
+}
+
+// SQLiteの接続先に外部キー制約を有効にするパラメーターを加える
+// SQLiteは外部キー制約が既定で無効なため、ON DELETE CASCADEが効くよう接続し直した場合も含めて全ての接続で有効にする
+// パラメーターの書式はsqliteshimがビルドに応じて選ぶドライバによって異なる (cgoが有効な場合はmattn/go-sqlite3、それ以外はmodernc.org/sqlite)
+func sqliteDSNWithForeignKeys(dsn string) string {
+	param := "_pragma=foreign_keys(1)"
+	if sqliteshim.DriverName() == "sqlite3" {
+		param = "_foreign_keys=1"
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+	return dsn + "?" + param
+}
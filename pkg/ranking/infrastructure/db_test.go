@@ -0,0 +1,71 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+// テスト用のデータベースに接続してマイグレーションを適用する
+// TEST_DB_DIALECTが未設定またはsqliteの場合は、テストごとにインメモリのSQLiteを作成する
+// それ以外のデータベースはTEST_DB_DSNに接続先を指定する (未設定の場合はスキップ)
+func newTestDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	dialect := Dialect(os.Getenv("TEST_DB_DIALECT"))
+	if dialect == "" {
+		dialect = DialectSQLite
+	}
+	dsn := os.Getenv("TEST_DB_DSN")
+
+	// インメモリのSQLite
+	if dialect == DialectSQLite && dsn == "" {
+		dsn = fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	}
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN is not set")
+	}
+
+	db, err := OpenDB(dialect, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// スキーマを最新にする
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+// 同時実行のテスト用のデータベースに接続する
+// SQLiteは書き込みが直列で接続も1本のため同時実行の検証にならない。TEST_DB_DIALECTにSQL ServerかPostgreSQLを指定した場合のみ実行する
+func newConcurrentTestDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	dialect := Dialect(os.Getenv("TEST_DB_DIALECT"))
+	if dialect == "" || dialect == DialectSQLite {
+		t.Skip("TEST_DB_DIALECT is not a database server")
+	}
+	return newTestDB(t)
+}
+
+// SQLiteは接続し直しても外部キー制約が有効になる
+func TestOpenDBSQLiteForeignKeys(t *testing.T) {
+	db, err := OpenDB(DialectSQLite, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// 接続を使い回さず、クエリごとに新しい接続で確認する
+	db.SetMaxIdleConns(0)
+	for range 2 {
+		var enabled int
+		require.NoError(t, db.QueryRow("PRAGMA foreign_keys").Scan(&enabled))
+		assert.Equal(t, 1, enabled)
+	}
+}
@@ -0,0 +1,31 @@
+package infrastructure
+
+import (
+	"errors"
+	"strings"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// 一意制約違反のエラーかを判定する
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// SQL Serverの一意制約違反 (2627: 主キー・UNIQUE制約, 2601: 一意インデックス)
+	var mssqlErr mssql.Error
+	if errors.As(err, &mssqlErr) {
+		return mssqlErr.Number == 2627 || mssqlErr.Number == 2601
+	}
+
+	// PostgreSQLの一意制約違反 (SQLSTATE 23505)
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Field('C') == "23505"
+	}
+
+	// SQLiteの一意制約違反 (sqliteshimが選ぶドライバによってエラーの型が異なるため、共通のメッセージで判定する)
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
@@ -0,0 +1,244 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ゲーム
+type Game struct {
+	ID        int       `bun:"id,pk,autoincrement"`
+	Name      string    `bun:"name"`
+	CreatedAt time.Time `bun:"created_at,nullzero,default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,default:CURRENT_TIMESTAMP"`
+
+	// スコアの送信の署名に使う秘密鍵 (NULLの場合は署名を求めない)
+	SubmissionSecret *string `bun:"submission_secret"`
+}
+
+// ゲームリポジトリ
+type GameRepository struct {
+	db bun.IDB
+}
+
+// リポジトリを生成する
+func NewGameRepository(bun bun.IDB) *GameRepository {
+	return &GameRepository{
+		db: bun,
+	}
+}
+
+// ゲームをIDをキーとして取得する
+func (r *GameRepository) FindByID(ctx context.Context, id int) (*domain.Game, error) {
+	// ゲーム
+	game := new(Game)
+
+	// クエリ実行
+	err := dbFromContext(ctx, r.db).NewSelect().Model(game).Where("id = ?", id).Scan(ctx)
+
+	// 存在しない場合はNotFoundエラーを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, gameNotFoundError(id)
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのゲームを返す
+	return toDomainGame(game)
+}
+
+// ゲームを名前をキーとして取得する
+func (r *GameRepository) FindByName(ctx context.Context, name domain.GameName) (*domain.Game, error) {
+	// ゲーム
+	game := new(Game)
+
+	// クエリ実行
+	err := dbFromContext(ctx, r.db).NewSelect().Model(game).Where("name = ?", name.Value).Scan(ctx)
+
+	// 存在しない場合はnilを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのゲームを返す
+	return toDomainGame(game)
+}
+
+// ゲーム一覧を取得する
+func (r *GameRepository) FindAll(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.Game], error) {
+	// ゲームスライス
+	var games []Game
+
+	// クエリ実行
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&games)
+	err := applyIDPage(q, "id", page).Scan(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメイン層のゲーム構造体にマッピング
+	domainGames := make([]domain.Game, 0, len(games))
+	for i := range games {
+		domainGame, err := toDomainGame(&games[i])
+		if err != nil {
+			return nil, err
+		}
+		domainGames = append(domainGames, *domainGame)
+	}
+
+	// ドメインのゲーム一覧をページとして返す
+	return toPage(domainGames, page.Limit, page.Direction), nil
+}
+
+// ゲームを登録する
+func (r *GameRepository) Create(ctx context.Context, name domain.GameName) (*domain.Game, error) {
+	// ゲーム構造体を生成
+	game := &Game{
+		Name: name.Value,
+	}
+
+	// ゲーム登録クエリを実行
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(game).Exec(ctx)
+
+	// 同名のゲームが同時に登録された場合は競合エラーを返す
+	if isUniqueViolation(err) {
+		return nil, gameNameConflictError(name)
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// 挿入後に ID を基に再取得
+	return r.FindByID(ctx, game.ID)
+}
+
+// ゲーム名を変更する
+func (r *GameRepository) Update(ctx context.Context, id int, name domain.GameName) (*domain.Game, error) {
+	// ゲーム更新クエリを実行
+	result, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*Game)(nil)).
+		Set("name = ?", name.Value).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	// 同名のゲームが既に登録されている場合は競合エラーを返す
+	if isUniqueViolation(err) {
+		return nil, gameNameConflictError(name)
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// 更新対象が存在しない場合はNotFoundエラーを返す
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil, gameNotFoundError(id)
+	}
+
+	// 更新後のゲームを返す
+	return r.FindByID(ctx, id)
+}
+
+// スコアの送信の署名に使う秘密鍵を設定する
+func (r *GameRepository) UpdateSubmissionSecret(ctx context.Context, id int, secret *string) (*domain.Game, error) {
+	// ゲーム更新クエリを実行
+	result, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*Game)(nil)).
+		Set("submission_secret = ?", secret).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// 更新対象が存在しない場合はNotFoundエラーを返す
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil, gameNotFoundError(id)
+	}
+
+	// 更新後のゲームを返す
+	return r.FindByID(ctx, id)
+}
+
+// ゲームを削除する
+func (r *GameRepository) Delete(ctx context.Context, id int) error {
+	// ゲーム削除クエリを実行 (ランキングとハイスコアは外部キーのON DELETE CASCADEで削除される)
+	result, err := dbFromContext(ctx, r.db).NewDelete().
+		Model((*Game)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return err
+	}
+
+	// 削除対象が存在しない場合はNotFoundエラーを返す
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return gameNotFoundError(id)
+	}
+
+	return nil
+}
+
+// ドメインのゲームにマッピングする
+func toDomainGame(game *Game) (*domain.Game, error) {
+	// ゲーム名
+	gameName, err := domain.NewGameName(game.Name)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	return &domain.Game{
+		ID:        game.ID,
+		Name:      gameName,
+		CreatedAt: game.CreatedAt,
+		UpdatedAt: game.UpdatedAt,
+
+		SubmissionSecret: game.SubmissionSecret,
+	}, nil
+}
+
+// ゲームが存在しないエラーを生成する
+func gameNotFoundError(id int) error {
+	return domain.NewNotFoundError("game_not_found", fmt.Sprintf("ゲームが存在しません。ゲームID: %d", id))
+}
+
+// ゲーム名が重複するエラーを生成する
+func gameNameConflictError(name domain.GameName) error {
+	return domain.NewConflictError("game_name_conflict", fmt.Sprintf("ゲーム名は既に使用されています。入力された名前: %q", name.Value))
+}
@@ -0,0 +1,77 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ゲーム名の一意制約違反は競合エラーになる
+func TestGameRepositoryNameConflict(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repository := NewGameRepository(db)
+
+	gameName, _ := domain.NewGameName(fmt.Sprintf("conflict-%d", time.Now().UnixNano()))
+	_, err := repository.Create(ctx, gameName)
+	require.NoError(t, err)
+
+	_, err = repository.Create(ctx, gameName)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+// ゲームを削除するとランキングも削除される
+func TestGameRepositoryDeleteCascades(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repository := NewGameRepository(db)
+
+	suffix := time.Now().UnixNano()
+	gameName, _ := domain.NewGameName(fmt.Sprintf("cascade-%d", suffix))
+	game, err := repository.Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName(fmt.Sprintf("cascade-%d", suffix))
+	ranking, err := NewRankingRepository(db).Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}})
+	require.NoError(t, err)
+
+	require.NoError(t, repository.Delete(ctx, game.ID))
+
+	_, err = NewRankingRepository(db).FindByID(ctx, ranking.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// 存在しないゲームは削除できない
+	assert.ErrorIs(t, repository.Delete(ctx, game.ID), domain.ErrNotFound)
+}
+
+// スコアの送信の署名に使う秘密鍵を設定・解除できる
+func TestGameRepositoryUpdateSubmissionSecret(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repository := NewGameRepository(db)
+
+	gameName, _ := domain.NewGameName(fmt.Sprintf("secret-%d", time.Now().UnixNano()))
+	game, err := repository.Create(ctx, gameName)
+	require.NoError(t, err)
+	assert.Nil(t, game.SubmissionSecret)
+
+	// 秘密鍵を設定する
+	secret := "gss_secret"
+	updated, err := repository.UpdateSubmissionSecret(ctx, game.ID, &secret)
+	require.NoError(t, err)
+	require.NotNil(t, updated.SubmissionSecret)
+	assert.Equal(t, secret, *updated.SubmissionSecret)
+
+	// 秘密鍵を解除する
+	updated, err = repository.UpdateSubmissionSecret(ctx, game.ID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, updated.SubmissionSecret)
+
+	// 存在しないゲーム
+	_, err = repository.UpdateSubmissionSecret(ctx, game.ID+1000000, nil)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
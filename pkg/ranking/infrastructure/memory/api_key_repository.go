@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"slices"
+	"time"
+)
+
+// APIキーリポジトリ
+type APIKeyRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewAPIKeyRepository(store *Store) *APIKeyRepository {
+	return &APIKeyRepository{
+		store: store,
+	}
+}
+
+// キーのハッシュ値でAPIキーを取得する
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	var found *domain.APIKey
+	r.store.read(func() {
+		for _, apiKey := range r.store.apiKeys {
+			if apiKey.Hash == hash {
+				found = &apiKey
+				return
+			}
+		}
+	})
+	return found, nil
+}
+
+// APIキー一覧を取得する
+func (r *APIKeyRepository) FindAll(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.APIKey], error) {
+	var apiKeys []domain.APIKey
+	r.store.read(func() {
+		apiKeys = slices.Collect(maps.Values(r.store.apiKeys))
+	})
+	return idPage(apiKeys, func(apiKey domain.APIKey) int { return apiKey.ID }, page), nil
+}
+
+// APIキーを登録する
+func (r *APIKeyRepository) Create(ctx context.Context, apiKey *domain.APIKey) (*domain.APIKey, error) {
+	var created domain.APIKey
+	err := r.store.write(ctx, func() error {
+		// ゲームを限定する場合はゲームの存在を確認する (データベースの外部キー制約に相当)
+		if apiKey.GameID != nil {
+			if _, ok := r.store.games[*apiKey.GameID]; !ok {
+				return gameNotFoundError(*apiKey.GameID)
+			}
+		}
+
+		// IDを採番して登録する
+		r.store.advanceID(&r.store.lastAPIKeyID)
+		created = *apiKey
+		created.ID = r.store.lastAPIKeyID
+		created.Scopes = slices.Clone(apiKey.Scopes)
+		created.CreatedAt = time.Now().UTC()
+		created.RevokedAt = nil
+		setEntry(r.store, r.store.apiKeys, created.ID, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// APIキーを失効させる
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int) error {
+	return r.store.write(ctx, func() error {
+		// 存在しない場合と失効済みの場合はNotFoundエラーを返す
+		apiKey, ok := r.store.apiKeys[id]
+		if !ok || apiKey.IsRevoked() {
+			return apiKeyNotFoundError(id)
+		}
+
+		// 失効した日時を記録する
+		now := time.Now().UTC()
+		apiKey.RevokedAt = &now
+		setEntry(r.store, r.store.apiKeys, id, apiKey)
+		return nil
+	})
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+// データベースのリポジトリと同じエラーコードを返す
+
+// ユーザーが存在しないエラーを生成する
+func userNotFoundError(id int) error {
+	return domain.NewNotFoundError("user_not_found", fmt.Sprintf("ユーザーが存在しません。ユーザーID: %d", id))
+}
+
+// ゲームが存在しないエラーを生成する
+func gameNotFoundError(id int) error {
+	return domain.NewNotFoundError("game_not_found", fmt.Sprintf("ゲームが存在しません。ゲームID: %d", id))
+}
+
+// ゲーム名が重複するエラーを生成する
+func gameNameConflictError(name domain.GameName) error {
+	return domain.NewConflictError("game_name_conflict", fmt.Sprintf("ゲーム名は既に使用されています。入力された名前: %q", name.Value))
+}
+
+// ランキングが存在しないエラーを生成する
+func rankingNotFoundError(id int) error {
+	return domain.NewNotFoundError("ranking_not_found", fmt.Sprintf("ランキングが存在しません。ランキングID: %d", id))
+}
+
+// ランキング名が重複するエラーを生成する
+func rankingNameConflictError(name domain.RankingName) error {
+	return domain.NewConflictError("ranking_name_conflict", fmt.Sprintf("ランキング名はゲーム内で既に使用されています。入力された名前: %q", name.Value))
+}
+
+// ハイスコアが未登録のエラーを生成する
+func userHighScoreNotFoundError(rankingID int, userID int) error {
+	return domain.NewNotFoundError("user_high_score_not_found", fmt.Sprintf("ハイスコアが登録されていません。ランキングID: %d, ユーザーID: %d", rankingID, userID))
+}
+
+// 終了したシーズンが存在しないエラーを生成する
+func seasonNotFoundError(rankingID int, number int) error {
+	return domain.NewNotFoundError("season_not_found", fmt.Sprintf("終了したシーズンが存在しません。ランキングID: %d, シーズン: %d", rankingID, number))
+}
+
+// APIキーが存在しないエラーを生成する
+func apiKeyNotFoundError(id int) error {
+	return domain.NewNotFoundError("api_key_not_found", fmt.Sprintf("APIキーが存在しないか失効しています。APIキーID: %d", id))
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"slices"
+	"time"
+)
+
+// ゲームリポジトリ
+type GameRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewGameRepository(store *Store) *GameRepository {
+	return &GameRepository{
+		store: store,
+	}
+}
+
+// ゲームをIDをキーとして取得する
+func (r *GameRepository) FindByID(ctx context.Context, id int) (*domain.Game, error) {
+	var game domain.Game
+	var ok bool
+	r.store.read(func() {
+		game, ok = r.store.games[id]
+	})
+
+	// 存在しない場合はNotFoundエラーを返す
+	if !ok {
+		return nil, gameNotFoundError(id)
+	}
+	return &game, nil
+}
+
+// ゲームを名前をキーとして取得する
+func (r *GameRepository) FindByName(ctx context.Context, name domain.GameName) (*domain.Game, error) {
+	var game *domain.Game
+	r.store.read(func() {
+		game = r.findByName(name)
+	})
+	return game, nil
+}
+
+// ゲーム一覧を取得する
+func (r *GameRepository) FindAll(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.Game], error) {
+	var games []domain.Game
+	r.store.read(func() {
+		games = slices.Collect(maps.Values(r.store.games))
+	})
+	return idPage(games, func(game domain.Game) int { return game.ID }, page), nil
+}
+
+// ゲームを登録する
+func (r *GameRepository) Create(ctx context.Context, name domain.GameName) (*domain.Game, error) {
+	var game domain.Game
+	err := r.store.write(ctx, func() error {
+		// 同名のゲームが登録されている場合は競合エラーを返す (データベースのユニーク制約に相当)
+		if r.findByName(name) != nil {
+			return gameNameConflictError(name)
+		}
+
+		// IDを採番して登録する
+		r.store.advanceID(&r.store.lastGameID)
+		now := time.Now().UTC()
+		game = domain.Game{
+			ID:        r.store.lastGameID,
+			Name:      name,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		setEntry(r.store, r.store.games, game.ID, game)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+// ゲーム名を変更する
+func (r *GameRepository) Update(ctx context.Context, id int, name domain.GameName) (*domain.Game, error) {
+	var game domain.Game
+	err := r.store.write(ctx, func() error {
+		// 存在しない場合はNotFoundエラーを返す
+		var ok bool
+		game, ok = r.store.games[id]
+		if !ok {
+			return gameNotFoundError(id)
+		}
+
+		// 他のゲームが同じ名前を使っている場合は競合エラーを返す
+		if registered := r.findByName(name); registered != nil && registered.ID != id {
+			return gameNameConflictError(name)
+		}
+
+		game.Name = name
+		game.UpdatedAt = time.Now().UTC()
+		setEntry(r.store, r.store.games, id, game)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+// スコアの送信の署名に使う秘密鍵を設定する
+func (r *GameRepository) UpdateSubmissionSecret(ctx context.Context, id int, secret *string) (*domain.Game, error) {
+	var game domain.Game
+	err := r.store.write(ctx, func() error {
+		// 存在しない場合はNotFoundエラーを返す
+		var ok bool
+		game, ok = r.store.games[id]
+		if !ok {
+			return gameNotFoundError(id)
+		}
+
+		game.SubmissionSecret = secret
+		game.UpdatedAt = time.Now().UTC()
+		setEntry(r.store, r.store.games, id, game)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+// ゲームを削除する
+func (r *GameRepository) Delete(ctx context.Context, id int) error {
+	return r.store.write(ctx, func() error {
+		// 存在しない場合はNotFoundエラーを返す
+		if _, ok := r.store.games[id]; !ok {
+			return gameNotFoundError(id)
+		}
+
+		// ゲームに属するランキングとハイスコア、シーズン、送信履歴、ゲームを限定したAPIキー、使用済みのナンスも削除する (データベースのON DELETE CASCADEに相当)
+		deleteEntry(r.store, r.store.games, id)
+		for rankingID, ranking := range r.store.rankings {
+			if ranking.GameID == id {
+				deleteEntry(r.store, r.store.rankings, rankingID)
+			}
+		}
+		for apiKeyID, apiKey := range r.store.apiKeys {
+			if apiKey.GameID != nil && *apiKey.GameID == id {
+				deleteEntry(r.store, r.store.apiKeys, apiKeyID)
+			}
+		}
+		for key := range r.store.submissionNonces {
+			if key.GameID == id {
+				deleteEntry(r.store, r.store.submissionNonces, key)
+			}
+		}
+		r.store.deleteOrphanedRankingData()
+		return nil
+	})
+}
+
+// 名前が一致するゲームを探す (ロックは呼び出し元で取得する)
+func (r *GameRepository) findByName(name domain.GameName) *domain.Game {
+	for _, game := range r.store.games {
+		if game.Name.Value == name.Value {
+			return &game
+		}
+	}
+	return nil
+}
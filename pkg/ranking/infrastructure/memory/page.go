@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"cmp"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"slices"
+)
+
+// 要素をIDの昇順に並べてページを切り出す
+func idPage[T any](items []T, id func(T) int, page domain.IDPageRequest) *domain.Page[T] {
+	// IDの昇順に並べる
+	slices.SortFunc(items, func(a, b T) int { return cmp.Compare(id(a), id(b)) })
+
+	// 指定したIDより大きい最初の要素の位置を探す
+	position := func(cursorID int) int {
+		i, _ := slices.BinarySearchFunc(items, cursorID, func(item T, target int) int { return cmp.Compare(id(item), target) })
+		return i
+	}
+
+	// 前のページはカーソルの直前から、後ろのページはカーソルの直後から取得する
+	if page.Direction == domain.PagePrev {
+		end := position(page.CursorID)
+		start := max(end-page.Limit, 0)
+		return &domain.Page[T]{
+			Items:   slices.Clone(items[start:end]),
+			HasMore: start > 0,
+		}
+	}
+	start := position(page.CursorID + 1)
+	end := min(start+page.Limit, len(items))
+	return &domain.Page[T]{
+		Items:   slices.Clone(items[start:end]),
+		HasMore: end < len(items),
+	}
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"slices"
+	"time"
+)
+
+// ランキングリポジトリ
+type RankingRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewRankingRepository(store *Store) *RankingRepository {
+	return &RankingRepository{
+		store: store,
+	}
+}
+
+// ランキングをIDをキーとして取得する
+func (r *RankingRepository) FindByID(ctx context.Context, id int) (*domain.Ranking, error) {
+	var ranking domain.Ranking
+	var ok bool
+	r.store.read(func() {
+		ranking, ok = r.store.rankings[id]
+	})
+
+	// 存在しない場合はNotFoundエラーを返す
+	if !ok {
+		return nil, rankingNotFoundError(id)
+	}
+	return &ranking, nil
+}
+
+// ランキングを行ロックして取得する (トランザクションは直列に実行するため、ロックせずに取得する)
+func (r *RankingRepository) FindByIDForUpdate(ctx context.Context, id int) (*domain.Ranking, error) {
+	return r.FindByID(ctx, id)
+}
+
+// ゲーム内のランキングを名前をキーとして取得する
+func (r *RankingRepository) FindByName(ctx context.Context, gameID int, name domain.RankingName) (*domain.Ranking, error) {
+	var ranking *domain.Ranking
+	r.store.read(func() {
+		ranking = r.findByName(gameID, name)
+	})
+	return ranking, nil
+}
+
+// ランキング一覧を取得する
+func (r *RankingRepository) FindAll(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.Ranking], error) {
+	var rankings []domain.Ranking
+	r.store.read(func() {
+		rankings = slices.Collect(maps.Values(r.store.rankings))
+	})
+	return idPage(rankings, func(ranking domain.Ranking) int { return ranking.ID }, page), nil
+}
+
+// ゲームに属するランキング一覧を取得する
+func (r *RankingRepository) FindAllByGameID(ctx context.Context, gameID int, page domain.IDPageRequest) (*domain.Page[domain.Ranking], error) {
+	var rankings []domain.Ranking
+	r.store.read(func() {
+		for _, ranking := range r.store.rankings {
+			if ranking.GameID == gameID {
+				rankings = append(rankings, ranking)
+			}
+		}
+	})
+	return idPage(rankings, func(ranking domain.Ranking) int { return ranking.ID }, page), nil
+}
+
+// 進行中のシーズンが終了したランキング一覧を取得する
+func (r *RankingRepository) FindAllSeasonEnded(ctx context.Context, now time.Time) ([]domain.Ranking, error) {
+	var rankings []domain.Ranking
+	r.store.read(func() {
+		for _, ranking := range r.store.rankings {
+			if ranking.SeasonIsOver(now) {
+				rankings = append(rankings, ranking)
+			}
+		}
+	})
+	slices.SortFunc(rankings, func(a, b domain.Ranking) int { return a.ID - b.ID })
+	return rankings, nil
+}
+
+// ランキングを登録する
+func (r *RankingRepository) Create(ctx context.Context, newRanking *domain.Ranking) (*domain.Ranking, error) {
+	var ranking domain.Ranking
+	err := r.store.write(ctx, func() error {
+		// ゲーム内で同名のランキングが登録されている場合は競合エラーを返す (データベースのユニーク制約に相当)
+		if r.findByName(newRanking.GameID, newRanking.Name) != nil {
+			return rankingNameConflictError(newRanking.Name)
+		}
+
+		// IDを採番して登録する
+		r.store.advanceID(&r.store.lastRankingID)
+		now := time.Now().UTC()
+		ranking = *newRanking
+		ranking.ID = r.store.lastRankingID
+		ranking.CreatedAt = now
+		ranking.UpdatedAt = now
+		setEntry(r.store, r.store.rankings, ranking.ID, ranking)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ranking, nil
+}
+
+// スコアの妥当性のルールを変更する
+func (r *RankingRepository) UpdatePlausibilityRules(ctx context.Context, id int, rules domain.PlausibilityRules) (*domain.Ranking, error) {
+	var ranking domain.Ranking
+	err := r.store.write(ctx, func() error {
+		// 存在しない場合はNotFoundエラーを返す
+		var ok bool
+		ranking, ok = r.store.rankings[id]
+		if !ok {
+			return rankingNotFoundError(id)
+		}
+
+		ranking.PlausibilityRules = rules
+		ranking.UpdatedAt = time.Now().UTC()
+		setEntry(r.store, r.store.rankings, id, ranking)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ranking, nil
+}
+
+// ランキングを削除する
+func (r *RankingRepository) Delete(ctx context.Context, id int) error {
+	return r.store.write(ctx, func() error {
+		// 存在しない場合はNotFoundエラーを返す
+		if _, ok := r.store.rankings[id]; !ok {
+			return rankingNotFoundError(id)
+		}
+
+		// ランキングに属するハイスコア、シーズン、送信履歴も削除する
+		deleteEntry(r.store, r.store.rankings, id)
+		r.store.deleteOrphanedRankingData()
+		return nil
+	})
+}
+
+// ゲーム内で名前が一致するランキングを探す (ロックは呼び出し元で取得する)
+func (r *RankingRepository) findByName(gameID int, name domain.RankingName) *domain.Ranking {
+	for _, ranking := range r.store.rankings {
+		if ranking.GameID == gameID && ranking.Name.Value == name.Value {
+			return &ranking
+		}
+	}
+	return nil
+}
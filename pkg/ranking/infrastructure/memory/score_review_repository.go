@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// スコアの審査リポジトリ
+type ScoreReviewRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewScoreReviewRepository(store *Store) *ScoreReviewRepository {
+	return &ScoreReviewRepository{
+		store: store,
+	}
+}
+
+// 審査を記録する
+func (r *ScoreReviewRepository) Create(ctx context.Context, review *domain.ScoreReview) (*domain.ScoreReview, error) {
+	created := *review
+	err := r.store.write(ctx, func() error {
+		// IDを採番して審査日時を記録する
+		r.store.advanceID(&r.store.lastScoreReviewID)
+		created.ID = r.store.lastScoreReviewID
+		created.ReviewedAt = time.Now().UTC()
+		setEntry(r.store, r.store.scoreReviews, created.ID, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// スコアの送信履歴リポジトリ
+type ScoreSubmissionRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewScoreSubmissionRepository(store *Store) *ScoreSubmissionRepository {
+	return &ScoreSubmissionRepository{
+		store: store,
+	}
+}
+
+// 送信履歴を追加する
+func (r *ScoreSubmissionRepository) Create(ctx context.Context, submission *domain.ScoreSubmission) (*domain.ScoreSubmission, error) {
+	created := *submission
+	err := r.store.write(ctx, func() error {
+		// IDを採番して送信日時を記録する
+		r.store.advanceID(&r.store.lastScoreSubmissionID)
+		created.ID = r.store.lastScoreSubmissionID
+		created.SubmittedAt = time.Now().UTC()
+		setEntry(r.store, r.store.scoreSubmissions, created.ID, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// 送信履歴を取得する
+func (r *ScoreSubmissionRepository) FindByID(ctx context.Context, id int) (*domain.ScoreSubmission, error) {
+	var submission domain.ScoreSubmission
+	var ok bool
+	r.store.read(func() {
+		submission, ok = r.store.scoreSubmissions[id]
+	})
+
+	// 存在しない場合はNotFoundエラーを返す
+	if !ok {
+		return nil, domain.NewNotFoundError("score_submission_not_found", fmt.Sprintf("送信履歴が存在しません。送信履歴ID: %d", id))
+	}
+	return &submission, nil
+}
+
+// ランキングとユーザーを指定して、最後の送信履歴を取得する
+func (r *ScoreSubmissionRepository) FindLatest(ctx context.Context, rankingID int, userID int) (*domain.ScoreSubmission, error) {
+	var latest *domain.ScoreSubmission
+	r.store.read(func() {
+		for _, submission := range r.store.scoreSubmissions {
+			if submission.RankingID == rankingID && submission.UserID == userID && (latest == nil || submission.ID > latest.ID) {
+				latest = &submission
+			}
+		}
+	})
+	return latest, nil
+}
+
+// ランキングとユーザーを指定して、期間内の送信履歴を送信順に取得する
+func (r *ScoreSubmissionRepository) FindAllByRankingIDAndUserID(ctx context.Context, rankingID int, userID int, timeRange domain.TimeRange, page domain.IDPageRequest) (*domain.Page[domain.ScoreSubmission], error) {
+	var submissions []domain.ScoreSubmission
+	r.store.read(func() {
+		for _, submission := range r.store.scoreSubmissions {
+			if submission.RankingID == rankingID && submission.UserID == userID && timeRange.Contains(submission.SubmittedAt) {
+				submissions = append(submissions, submission)
+			}
+		}
+	})
+	return idPage(submissions, func(submission domain.ScoreSubmission) int { return submission.ID }, page), nil
+}
+
+// 審査待ちの送信履歴を送信順に取得する
+func (r *ScoreSubmissionRepository) FindAllPendingReview(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.ScoreSubmission], error) {
+	var submissions []domain.ScoreSubmission
+	r.store.read(func() {
+		for _, submission := range r.store.scoreSubmissions {
+			if submission.Status == domain.SubmissionPendingReview && r.store.isVisibleUser(submission.UserID) {
+				submissions = append(submissions, submission)
+			}
+		}
+	})
+	return idPage(submissions, func(submission domain.ScoreSubmission) int { return submission.ID }, page), nil
+}
+
+// 審査待ちの送信履歴を審査の結果の状態に更新する
+func (r *ScoreSubmissionRepository) Resolve(ctx context.Context, id int, status domain.SubmissionStatus) error {
+	return r.store.write(ctx, func() error {
+		// 審査待ちでない場合はConflictエラーを返す
+		submission, ok := r.store.scoreSubmissions[id]
+		if !ok || submission.Status != domain.SubmissionPendingReview {
+			return domain.NewConflictError("submission_not_pending_review", fmt.Sprintf("送信は審査待ちではありません。送信履歴ID: %d", id))
+		}
+
+		submission.Status = status
+		setEntry(r.store, r.store.scoreSubmissions, id, submission)
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"slices"
+	"time"
+)
+
+// シーズンリポジトリ
+type SeasonRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewSeasonRepository(store *Store) *SeasonRepository {
+	return &SeasonRepository{
+		store: store,
+	}
+}
+
+// ランキングの終了したシーズンを番号をキーとして取得する
+func (r *SeasonRepository) FindByNumber(ctx context.Context, rankingID int, number int) (*domain.Season, error) {
+	var season domain.Season
+	var ok bool
+	r.store.read(func() {
+		season, ok = r.store.seasons[seasonKey{RankingID: rankingID, Season: number}]
+	})
+
+	// 存在しない場合はNotFoundエラーを返す
+	if !ok {
+		return nil, seasonNotFoundError(rankingID, number)
+	}
+	return &season, nil
+}
+
+// ランキングの終了したシーズン一覧を取得する
+func (r *SeasonRepository) FindAllByRankingID(ctx context.Context, rankingID int, page domain.IDPageRequest) (*domain.Page[domain.Season], error) {
+	var seasons []domain.Season
+	r.store.read(func() {
+		for key, season := range r.store.seasons {
+			if key.RankingID == rankingID {
+				seasons = append(seasons, season)
+			}
+		}
+	})
+	return idPage(seasons, func(season domain.Season) int { return season.Number }, page), nil
+}
+
+// 進行中のシーズンを終了して次のシーズンを開始する
+func (r *SeasonRepository) Archive(ctx context.Context, ranking *domain.Ranking, next domain.SeasonPeriod) (*domain.Season, error) {
+	current := ranking.Season
+	var season domain.Season
+	err := r.store.write(ctx, func() error {
+		// 終了するシーズンのままの場合のみ次のシーズンに進める
+		stored, ok := r.store.rankings[ranking.ID]
+		if !ok {
+			return rankingNotFoundError(ranking.ID)
+		}
+		if stored.Season == nil || stored.Season.Number != current.Number {
+			return domain.NewConflictError("season_already_rotated", fmt.Sprintf("シーズンは既に終了しています。ランキングID: %d, シーズン: %d", ranking.ID, current.Number))
+		}
+		stored.Season = &next
+		stored.UpdatedAt = time.Now().UTC()
+		setEntry(r.store, r.store.rankings, ranking.ID, stored)
+
+		// ユーザーハイスコアをランク付けしてアーカイブし、次のシーズンは空のランキングから始める
+		// 論理削除したユーザーのハイスコアはアーカイブしない
+		var highScores []domain.UserHighScore
+		for key, highScore := range r.store.userHighScores {
+			if key.RankingID == ranking.ID {
+				if r.store.isVisibleUser(key.UserID) {
+					highScores = append(highScores, highScore)
+				}
+				deleteEntry(r.store, r.store.userHighScores, key)
+			}
+		}
+		sortUserHighScores(highScores, stored.SortOrder)
+
+		// 次のシーズンのハイスコアの分布は空から集計する
+		deleteEntry(r.store, r.store.scoreStats, ranking.ID)
+
+		// 終了したシーズンを登録する
+		key := seasonKey{RankingID: ranking.ID, Season: current.Number}
+		season = domain.Season{
+			RankingID:  ranking.ID,
+			Number:     current.Number,
+			StartedAt:  current.StartedAt,
+			EndedAt:    current.EndsAt,
+			TotalUsers: len(highScores),
+		}
+		setEntry(r.store, r.store.seasons, key, season)
+		setEntry(r.store, r.store.archivedUserHighScores, key, highScores)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// ハイスコアをランク付けと同じ並び順で並べる
+func sortUserHighScores(highScores []domain.UserHighScore, sortOrder domain.SortOrder) {
+	slices.SortFunc(highScores, func(a, b domain.UserHighScore) int {
+		if ranksAbove(sortOrder, a.Score, a.Timestamp, a.UserID, b.Score, b.Timestamp, b.UserID) {
+			return -1
+		}
+		if ranksAbove(sortOrder, b.Score, b.Timestamp, b.UserID, a.Score, a.Timestamp, a.UserID) {
+			return 1
+		}
+		return 0
+	})
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+)
+
+// 終了したシーズンのユーザーランキングクエリサービス
+type SeasonUserRankingQueryService struct {
+	store *Store
+}
+
+// クエリサービスを生成する
+func NewSeasonUserRankingQueryService(store *Store) *SeasonUserRankingQueryService {
+	return &SeasonUserRankingQueryService{
+		store: store,
+	}
+}
+
+// 終了したシーズンのユーザーランキングを取得する
+func (s *SeasonUserRankingQueryService) FetchSeasonUserRanking(ctx context.Context, rankingID int, number int, page domain.IDPageRequest) (*usecase.SeasonUserRankingDto, error) {
+	var ranking domain.Ranking
+	var season domain.Season
+	var rankingOK, seasonOK bool
+	var userRanks []usecase.UserRankDto
+	s.store.read(func() {
+		ranking, rankingOK = s.store.rankings[rankingID]
+		key := seasonKey{RankingID: rankingID, Season: number}
+		season, seasonOK = s.store.seasons[key]
+
+		// アーカイブ時の並び順でランクを振る (論理削除したユーザーは除外して詰める)
+		for _, highScore := range s.store.archivedUserHighScores[key] {
+			if !s.store.isVisibleUser(highScore.UserID) {
+				continue
+			}
+			userRanks = append(userRanks, usecase.UserRankDto{
+				UserID:     highScore.UserID,
+				UserName:   s.store.users[highScore.UserID].Name.Value,
+				Rank:       len(userRanks) + 1,
+				Score:      usecase.NewScoreDto(highScore.Score, ranking.ScoreType),
+				AchievedAt: highScore.Timestamp,
+			})
+		}
+	})
+
+	// ランキングまたはシーズンが存在しない場合はNotFoundエラーを返す
+	if !rankingOK {
+		return nil, rankingNotFoundError(rankingID)
+	}
+	if !seasonOK {
+		return nil, seasonNotFoundError(rankingID, number)
+	}
+
+	// アーカイブ時のランクをキーとしてページを切り出す
+	userRankPage := idPage(userRanks, func(userRank usecase.UserRankDto) int { return userRank.Rank }, page)
+	seasonUserRanking := &usecase.SeasonUserRankingDto{
+		RankingID:   ranking.ID,
+		RankingName: ranking.Name.Value,
+		Season:      season.Number,
+		StartedAt:   season.StartedAt,
+		EndedAt:     season.EndedAt,
+		TotalUsers:  len(userRanks),
+		UserRanks:   userRankPage.Items,
+	}
+	seasonUserRanking.HasNext, seasonUserRanking.HasPrev = usecase.PageLinks(userRankPage.HasMore, page.CursorID > 0, page.Direction)
+	return seasonUserRanking, nil
+}
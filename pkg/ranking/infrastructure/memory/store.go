@@ -0,0 +1,228 @@
+package memory
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"sync"
+	"time"
+)
+
+// ユーザーハイスコアのキー
+type userHighScoreKey struct {
+	RankingID int
+	UserID    int
+}
+
+// シーズンのキー
+type seasonKey struct {
+	RankingID int
+	Season    int
+}
+
+// スコアの送信の署名の使用済みのナンスのキー
+type submissionNonceKey struct {
+	GameID int
+	Nonce  string
+}
+
+// インメモリのデータストア
+// 各リポジトリはストアを共有し、ミューテックスで排他してデータを読み書きする
+type Store struct {
+	// データの読み書きのロック
+	mu sync.RWMutex
+
+	// トランザクションのロック (トランザクション外の書き込みもトランザクションの終了を待つ)
+	txMu sync.Mutex
+
+	users          map[int]domain.User
+	games          map[int]domain.Game
+	rankings       map[int]domain.Ranking
+	userHighScores map[userHighScoreKey]domain.UserHighScore
+	seasons        map[seasonKey]domain.Season
+
+	// 終了したシーズンのユーザーハイスコア (ランクの昇順)
+	archivedUserHighScores map[seasonKey][]domain.UserHighScore
+
+	// ランキングの進行中のシーズンのハイスコアの分布の集計値 (論理削除したユーザーを除く)
+	scoreStats map[int]domain.ScoreMoments
+
+	// スコアの送信履歴
+	scoreSubmissions map[int]domain.ScoreSubmission
+
+	// スコアの審査
+	scoreReviews map[int]domain.ScoreReview
+
+	// APIキー
+	apiKeys map[int]domain.APIKey
+
+	// スコアの送信の署名の使用済みのナンスと期限
+	submissionNonces map[submissionNonceKey]time.Time
+
+	// 採番済みの最大ID
+	lastUserID            int
+	lastGameID            int
+	lastRankingID         int
+	lastScoreSubmissionID int
+	lastScoreReviewID     int
+	lastAPIKeyID          int
+
+	// トランザクション中の書き込みの取り消し操作 (書き込んだ順。トランザクションは直列に実行するためストアで1つ持つ)
+	recording bool
+	undoLog   []func()
+}
+
+// ストアを生成する
+func NewStore() *Store {
+	return &Store{
+		users:          map[int]domain.User{},
+		games:          map[int]domain.Game{},
+		rankings:       map[int]domain.Ranking{},
+		userHighScores: map[userHighScoreKey]domain.UserHighScore{},
+		seasons:        map[seasonKey]domain.Season{},
+
+		archivedUserHighScores: map[seasonKey][]domain.UserHighScore{},
+		scoreStats:             map[int]domain.ScoreMoments{},
+		scoreSubmissions:       map[int]domain.ScoreSubmission{},
+		scoreReviews:           map[int]domain.ScoreReview{},
+		apiKeys:                map[int]domain.APIKey{},
+		submissionNonces:       map[submissionNonceKey]time.Time{},
+	}
+}
+
+// トランザクションを開始し、書き込みの取り消し操作の記録を始める (txMuは呼び出し元で取得する)
+func (s *Store) begin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recording = true
+	s.undoLog = nil
+}
+
+// トランザクションを終了する (ロールバックする場合は記録した取り消し操作を逆順に実行して開始時点の状態に戻す)
+func (s *Store) end(rollback bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rollback {
+		for i := len(s.undoLog) - 1; i >= 0; i-- {
+			s.undoLog[i]()
+		}
+	}
+	s.recording = false
+	s.undoLog = nil
+}
+
+// トランザクション中の場合は取り消し操作を記録する (ロックは呼び出し元で取得する)
+func (s *Store) recordUndo(undo func()) {
+	if s.recording {
+		s.undoLog = append(s.undoLog, undo)
+	}
+}
+
+// 採番済みの最大IDを進める (ロックは呼び出し元で取得する)
+func (s *Store) advanceID(lastID *int) {
+	previous := *lastID
+	s.recordUndo(func() { *lastID = previous })
+	*lastID++
+}
+
+// マップに値を設定する (ロックは呼び出し元で取得する)
+func setEntry[K comparable, V any](s *Store, m map[K]V, key K, value V) {
+	recordEntryUndo(s, m, key)
+	m[key] = value
+}
+
+// マップから値を削除する (ロックは呼び出し元で取得する)
+func deleteEntry[K comparable, V any](s *Store, m map[K]V, key K) {
+	if _, ok := m[key]; !ok {
+		return
+	}
+	recordEntryUndo(s, m, key)
+	delete(m, key)
+}
+
+// 書き込む前のキーの値に戻す取り消し操作を記録する (キーがなかった場合は削除する)
+func recordEntryUndo[K comparable, V any](s *Store, m map[K]V, key K) {
+	if !s.recording {
+		return
+	}
+	if previous, ok := m[key]; ok {
+		s.recordUndo(func() { m[key] = previous })
+	} else {
+		s.recordUndo(func() { delete(m, key) })
+	}
+}
+
+// ランキングとして表示するユーザーかを判定する (存在し、論理削除されていない。ロックは呼び出し元で取得する)
+func (s *Store) isVisibleUser(userID int) bool {
+	user, ok := s.users[userID]
+	return ok && !user.IsDeleted()
+}
+
+// ランキングのハイスコアの分布の集計値を更新する (ロックは呼び出し元で取得する)
+func (s *Store) updateScoreStats(rankingID int, update func(moments domain.ScoreMoments) domain.ScoreMoments) {
+	setEntry(s, s.scoreStats, rankingID, update(s.scoreStats[rankingID]))
+}
+
+// ユーザーの全てのランキングのハイスコアを分布の集計値に加えるか取り除く (ユーザーの論理削除と復元で使う。ロックは呼び出し元で取得する)
+func (s *Store) updateUserScoreStats(userID int, update func(moments domain.ScoreMoments, score domain.Score) domain.ScoreMoments) {
+	for key, highScore := range s.userHighScores {
+		if key.UserID == userID {
+			s.updateScoreStats(key.RankingID, func(moments domain.ScoreMoments) domain.ScoreMoments {
+				return update(moments, highScore.Score)
+			})
+		}
+	}
+}
+
+// 存在しないランキングに属するハイスコア、シーズン、送信履歴と審査を削除する (データベースのON DELETE CASCADEに相当。ロックは呼び出し元で取得する)
+func (s *Store) deleteOrphanedRankingData() {
+	for key := range s.userHighScores {
+		if _, ok := s.rankings[key.RankingID]; !ok {
+			deleteEntry(s, s.userHighScores, key)
+		}
+	}
+	for rankingID := range s.scoreStats {
+		if _, ok := s.rankings[rankingID]; !ok {
+			deleteEntry(s, s.scoreStats, rankingID)
+		}
+	}
+	for key := range s.seasons {
+		if _, ok := s.rankings[key.RankingID]; !ok {
+			deleteEntry(s, s.seasons, key)
+			deleteEntry(s, s.archivedUserHighScores, key)
+		}
+	}
+	for id, submission := range s.scoreSubmissions {
+		if _, ok := s.rankings[submission.RankingID]; !ok {
+			deleteEntry(s, s.scoreSubmissions, id)
+		}
+	}
+	for id, review := range s.scoreReviews {
+		if _, ok := s.scoreSubmissions[review.SubmissionID]; !ok {
+			deleteEntry(s, s.scoreReviews, id)
+		}
+	}
+}
+
+// データを読み取る
+func (s *Store) read(fn func()) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fn()
+}
+
+// データを書き込む
+// トランザクション外で呼ばれた場合は、実行中のトランザクションのロールバックで書き込みが消えないよう終了を待つ
+func (s *Store) write(ctx context.Context, fn func() error) error {
+	if !inTx(ctx) {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn()
+}
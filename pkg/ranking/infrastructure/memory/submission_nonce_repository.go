@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// スコアの送信の署名の使用済みのナンスのリポジトリ
+type SubmissionNonceRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewSubmissionNonceRepository(store *Store) *SubmissionNonceRepository {
+	return &SubmissionNonceRepository{
+		store: store,
+	}
+}
+
+// ゲームのナンスを期限まで使用済みにする
+func (r *SubmissionNonceRepository) Use(ctx context.Context, gameID int, nonce string, expiresAt time.Time) error {
+	return r.store.write(ctx, func() error {
+		// ゲームの期限切れのナンスを削除する (期限切れのナンスは再び使える)
+		now := time.Now()
+		for key, current := range r.store.submissionNonces {
+			if key.GameID == gameID && !now.Before(current) {
+				deleteEntry(r.store, r.store.submissionNonces, key)
+			}
+		}
+
+		// 期限内に使用済みの場合は競合エラーを返す
+		key := submissionNonceKey{GameID: gameID, Nonce: nonce}
+		if _, ok := r.store.submissionNonces[key]; ok {
+			return domain.NewConflictError("submission_nonce_replayed", fmt.Sprintf("このナンスは既に使用されています。ナンス: %q", nonce))
+		}
+		setEntry(r.store, r.store.submissionNonces, key, expiresAt)
+		return nil
+	})
+}
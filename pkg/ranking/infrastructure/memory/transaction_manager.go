@@ -0,0 +1,43 @@
+package memory
+
+import "context"
+
+// コンテキストにトランザクション中であることを格納するためのキー
+type txContextKey struct{}
+
+// トランザクションマネージャー
+// トランザクションは1つずつ直列に実行し、エラーの場合は書き込みを記録した取り消し操作で開始時点の状態に戻す
+type TransactionManager struct {
+	store *Store
+}
+
+// トランザクションマネージャーを生成する
+func NewTransactionManager(store *Store) *TransactionManager {
+	return &TransactionManager{
+		store: store,
+	}
+}
+
+// 関数をトランザクション内で実行する
+func (m *TransactionManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 既にトランザクション内であればそのトランザクションに参加する
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+
+	// 他のトランザクションとトランザクション外の書き込みを待たせる
+	m.store.txMu.Lock()
+	defer m.store.txMu.Unlock()
+
+	// 関数がエラーを返した場合は開始時点の状態に戻す
+	m.store.begin()
+	err := fn(context.WithValue(ctx, txContextKey{}, true))
+	m.store.end(err != nil)
+	return err
+}
+
+// トランザクション内かを判定する
+func inTx(ctx context.Context) bool {
+	inTx, _ := ctx.Value(txContextKey{}).(bool)
+	return inTx
+}
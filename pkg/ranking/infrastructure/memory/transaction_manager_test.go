@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// トランザクション内の書き込みはエラー時にロールバックされる
+func TestTransactionManagerRollback(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+
+	transactionManager := NewTransactionManager(store)
+	repository := NewUserRepository(store)
+
+	// トランザクション内でユーザーを登録してからエラーを返す
+	var created *domain.User
+	userName, _ := domain.NewUserName("rollback")
+	errRollback := errors.New("rollback")
+	err := transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = repository.Create(ctx, userName)
+		require.NoError(t, err)
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	// ロールバックされているので取得できない
+	_, err = repository.FindByID(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "Expected user to be rolled back")
+}
+
+// トランザクション内の更新と削除も書き込んだ順の逆に取り消され、採番も開始時点に戻る
+func TestTransactionManagerRollbackUpdateAndDelete(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+
+	transactionManager := NewTransactionManager(store)
+	repository := NewUserRepository(store)
+	userName, _ := domain.NewUserName("alice")
+	alice, err := repository.Create(ctx, userName)
+	require.NoError(t, err)
+
+	// 論理削除、復元、再度の論理削除と新規登録をしてからエラーを返す
+	errRollback := errors.New("rollback")
+	err = transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, repository.Delete(ctx, alice.ID))
+		_, err := repository.Restore(ctx, alice.ID)
+		require.NoError(t, err)
+		require.NoError(t, repository.Delete(ctx, alice.ID))
+		bobName, _ := domain.NewUserName("bob")
+		_, err = repository.Create(ctx, bobName)
+		require.NoError(t, err)
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	// 開始時点の状態に戻っている
+	found, err := repository.FindByID(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.False(t, found.IsDeleted())
+	carolName, _ := domain.NewUserName("carol")
+	carol, err := repository.Create(ctx, carolName)
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID+1, carol.ID)
+}
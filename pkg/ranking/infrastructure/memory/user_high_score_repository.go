@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// ユーザーハイスコアリポジトリ
+type UserHighScoreRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewUserHighScoreRepository(store *Store) *UserHighScoreRepository {
+	return &UserHighScoreRepository{
+		store: store,
+	}
+}
+
+// ユーザーハイスコアを取得する
+func (r *UserHighScoreRepository) Find(ctx context.Context, rankingID int, userID int) (*domain.UserHighScore, error) {
+	var userHighScore domain.UserHighScore
+	var ok bool
+	r.store.read(func() {
+		userHighScore, ok = r.store.userHighScores[userHighScoreKey{RankingID: rankingID, UserID: userID}]
+	})
+
+	// 未登録の場合はnilを返す
+	if !ok {
+		return nil, nil
+	}
+	return &userHighScore, nil
+}
+
+// ユーザーハイスコアを保存する
+func (r *UserHighScoreRepository) Upsert(ctx context.Context, rankingID int, userID int, score domain.Score, sortOrder domain.SortOrder) (*domain.UserHighScoreUpsertResult, error) {
+	return r.UpsertAt(ctx, rankingID, userID, score, sortOrder, time.Now())
+}
+
+// 登録日時を指定してユーザーハイスコアを保存する
+func (r *UserHighScoreRepository) UpsertAt(ctx context.Context, rankingID int, userID int, score domain.Score, sortOrder domain.SortOrder, timestamp time.Time) (*domain.UserHighScoreUpsertResult, error) {
+	var result *domain.UserHighScoreUpsertResult
+	err := r.store.write(ctx, func() error {
+		key := userHighScoreKey{RankingID: rankingID, UserID: userID}
+
+		// 保存前のハイスコアから登録結果を判定する
+		var previous *domain.UserHighScore
+		var previousScore *domain.Score
+		if current, ok := r.store.userHighScores[key]; ok {
+			previous = &current
+			previousScore = &current.Score
+		}
+		outcome := domain.DecideHighScoreOutcome(previous, score, sortOrder)
+
+		// 未登録か並び順で既存のハイスコアを上回る場合のみ保存する
+		highScore := previous
+		if outcome != domain.HighScoreUnchanged {
+			highScore = domain.NewUserHighScore(rankingID, userID, score)
+			highScore.Timestamp = timestamp.UTC()
+			setEntry(r.store, r.store.userHighScores, key, *highScore)
+
+			// ハイスコアの分布の集計値を更新する
+			r.store.updateScoreStats(rankingID, func(moments domain.ScoreMoments) domain.ScoreMoments {
+				if previous == nil {
+					return moments.Add(score)
+				}
+				return moments.Replace(previous.Score, score)
+			})
+		}
+
+		result = &domain.UserHighScoreUpsertResult{
+			Outcome:       outcome,
+			PreviousScore: previousScore,
+			HighScore:     *highScore,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ユーザーの全てのランキングのハイスコアを取得する
+func (r *UserHighScoreRepository) FindAllByUserID(ctx context.Context, userID int) ([]domain.UserHighScore, error) {
+	var highScores []domain.UserHighScore
+	r.store.read(func() {
+		for key, highScore := range r.store.userHighScores {
+			if key.UserID == userID {
+				highScores = append(highScores, highScore)
+			}
+		}
+	})
+	return highScores, nil
+}
+
+// ランキングのハイスコアの分布を取得する
+func (r *UserHighScoreRepository) FetchScoreStats(ctx context.Context, rankingID int) (domain.ScoreStats, error) {
+	var moments domain.ScoreMoments
+	r.store.read(func() {
+		moments = r.store.scoreStats[rankingID]
+	})
+	return moments.Stats(), nil
+}
+
+// ユーザーハイスコアを削除する
+func (r *UserHighScoreRepository) Delete(ctx context.Context, rankingID int, userID int) error {
+	return r.store.write(ctx, func() error {
+		// 未登録の場合はNotFoundエラーを返す
+		key := userHighScoreKey{RankingID: rankingID, UserID: userID}
+		highScore, ok := r.store.userHighScores[key]
+		if !ok {
+			return userHighScoreNotFoundError(rankingID, userID)
+		}
+		deleteEntry(r.store, r.store.userHighScores, key)
+
+		// 論理削除したユーザーのハイスコアは分布の集計値に含めていない
+		if r.store.isVisibleUser(userID) {
+			r.store.updateScoreStats(rankingID, func(moments domain.ScoreMoments) domain.ScoreMoments {
+				return moments.Remove(highScore.Score)
+			})
+		}
+		return nil
+	})
+}
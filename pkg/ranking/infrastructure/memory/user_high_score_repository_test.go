@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 同時に大量のスコアが登録されても最大値だけが残る
+func TestUserHighScoreRepositoryUpsertConcurrently(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+	repository := NewUserHighScoreRepository(store)
+
+	// 300件のスコアを並列に登録する
+	const submissions = 300
+	results := make(chan *domain.UserHighScoreUpsertResult, submissions)
+	var wg sync.WaitGroup
+	for i := 1; i <= submissions; i++ {
+		wg.Add(1)
+		go func(score domain.Score) {
+			defer wg.Done()
+			result, err := repository.Upsert(ctx, 1, 1, score, domain.SortOrderDesc)
+			assert.NoError(t, err)
+			results <- result
+		}(domain.Score(i))
+	}
+	wg.Wait()
+	close(results)
+
+	// 新規登録は1件だけで、前回値を下回る更新はないこと
+	created := 0
+	for result := range results {
+		if result.Outcome == domain.HighScoreCreated {
+			created++
+			continue
+		}
+		require.NotNil(t, result.PreviousScore)
+		assert.GreaterOrEqual(t, result.HighScore.Score, *result.PreviousScore, "Expected high score never to decrease")
+	}
+	assert.Equal(t, 1, created, "Expected exactly one created outcome")
+
+	// 最終的に最大のスコアが残ること
+	userHighScore, err := repository.Find(ctx, 1, 1)
+	require.NoError(t, err)
+	require.NotNil(t, userHighScore)
+	assert.Equal(t, domain.Score(submissions), userHighScore.Score, "Expected max score to win")
+}
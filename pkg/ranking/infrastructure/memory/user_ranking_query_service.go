@@ -0,0 +1,234 @@
+package memory
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"slices"
+	"time"
+)
+
+// ユーザーランキングクエリサービス
+type UserRankingQueryService struct {
+	store *Store
+}
+
+// クエリサービスを生成する
+func NewUserRankingQueryService(store *Store) *UserRankingQueryService {
+	return &UserRankingQueryService{
+		store: store,
+	}
+}
+
+// ユーザーランキングを取得する
+func (s *UserRankingQueryService) FetchUserRanking(ctx context.Context, query usecase.UserRankingQuery) (*usecase.UserRankingDto, error) {
+	// ランキングとランク付けしたハイスコアを取得
+	ranking, userRanks, err := s.rankedUserHighScores(query.RankingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 上位から下位に向かって取得する場合はカーソルより下位を、逆の場合は上位をカーソルに近い順に取得する
+	cursor := query.Cursor
+	var selected []usecase.UserRankDto
+	var hasMore bool
+	if query.FetchesDownward() {
+		start := 0
+		if cursor != nil {
+			start = positionBelow(userRanks, ranking.SortOrder, cursor.Score, cursor.Timestamp, cursor.UserID)
+		}
+		end := min(start+query.Limit, len(userRanks))
+		selected = slices.Clone(userRanks[start:end])
+		hasMore = end < len(userRanks)
+	} else {
+		end := len(userRanks)
+		if cursor != nil {
+			end = positionAbove(userRanks, ranking.SortOrder, cursor.Score, cursor.Timestamp, cursor.UserID)
+		}
+		start := max(end-query.Limit, 0)
+		selected = slices.Clone(userRanks[start:end])
+		slices.Reverse(selected)
+		hasMore = start > 0
+	}
+
+	// 前のページを取得した場合は逆順で取得しているため並べ替える
+	direction := domain.PageNext
+	if cursor != nil {
+		direction = cursor.Direction
+	}
+	if direction == domain.PagePrev {
+		slices.Reverse(selected)
+	}
+
+	// ユースケース層のユーザーランキング構造体にマッピング
+	userRanking := &usecase.UserRankingDto{
+		RankingID:   ranking.ID,
+		RankingName: ranking.Name.Value,
+		UserRanks:   selected,
+	}
+	userRanking.HasNext, userRanking.HasPrev = usecase.PageLinks(hasMore, cursor != nil, direction)
+	return userRanking, nil
+}
+
+// ユーザーの前後のユーザーランキングを取得する
+func (s *UserRankingQueryService) FetchUserRankingAround(ctx context.Context, query usecase.UserRankingAroundQuery) (*usecase.UserRankingAroundDto, error) {
+	// ランキングとランク付けしたハイスコアを取得
+	ranking, userRanks, err := s.rankedUserHighScores(query.RankingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 基準となるユーザーの位置を探す
+	i := slices.IndexFunc(userRanks, func(userRank usecase.UserRankDto) bool { return userRank.UserID == query.UserID })
+	if i < 0 {
+		return nil, userHighScoreNotFoundError(query.RankingID, query.UserID)
+	}
+
+	// 上位、自分、下位の順にランクの昇順で並べる (ランキングの端では存在する分だけ)
+	start := max(i-query.Before, 0)
+	end := min(i+1+query.After, len(userRanks))
+	return &usecase.UserRankingAroundDto{
+		RankingID:   ranking.ID,
+		RankingName: ranking.Name.Value,
+		UserID:      query.UserID,
+		Rank:        userRanks[i].Rank,
+		TotalUsers:  len(userRanks),
+		UserRanks:   slices.Clone(userRanks[start:end]),
+	}, nil
+}
+
+// ランキングにおけるユーザーの現在のランクを取得する
+func (s *UserRankingQueryService) FetchUserRank(ctx context.Context, rankingID int, userID int) (*usecase.UserRankDto, error) {
+	// ユーザーの順位
+	userStanding, err := s.FetchUserStanding(ctx, rankingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usecase.UserRankDto{
+		UserID:     userStanding.UserID,
+		UserName:   userStanding.UserName,
+		Rank:       userStanding.Rank,
+		Score:      userStanding.Score,
+		AchievedAt: userStanding.AchievedAt,
+	}, nil
+}
+
+// ランキングにおけるユーザーのランクと参加人数、パーセンタイルを取得する
+func (s *UserRankingQueryService) FetchUserStanding(ctx context.Context, rankingID int, userID int) (*usecase.UserStandingDto, error) {
+	var standing *usecase.UserStandingDto
+	s.store.read(func() {
+		// ハイスコアが未登録の場合と論理削除したユーザーの場合は見つからない
+		me, ok := s.store.userHighScores[userHighScoreKey{RankingID: rankingID, UserID: userID}]
+		if !ok || !s.store.isVisibleUser(userID) {
+			return
+		}
+
+		// ランキング全体を並べ替えず、並び順で自分より上位の件数と全体の件数を数えてランクを求める
+		ranking := s.store.rankings[rankingID]
+		rank, total := 1, 0
+		for key, other := range s.store.userHighScores {
+			if key.RankingID != rankingID || !s.store.isVisibleUser(key.UserID) {
+				continue
+			}
+			total++
+			if ranksAbove(ranking.SortOrder, other.Score, other.Timestamp, other.UserID, me.Score, me.Timestamp, me.UserID) {
+				rank++
+			}
+		}
+
+		standing = &usecase.UserStandingDto{
+			RankingID:  rankingID,
+			UserID:     userID,
+			UserName:   s.store.users[userID].Name.Value,
+			Rank:       rank,
+			Score:      usecase.NewScoreDto(me.Score, ranking.ScoreType),
+			AchievedAt: me.Timestamp,
+			TotalUsers: total,
+			Percentile: usecase.CalculatePercentile(rank, total),
+		}
+	})
+
+	// ハイスコアが未登録の場合はNotFoundエラーを返す
+	if standing == nil {
+		return nil, userHighScoreNotFoundError(rankingID, userID)
+	}
+	return standing, nil
+}
+
+// ランキングとランク付けしたハイスコアを取得する (ランキングが存在しない場合はNotFoundエラー)
+// 論理削除したユーザーのハイスコアは除外する
+// 並び順でスコアが上位の順、同点の場合は登録日時が古い順、それも同じ場合はユーザーIDが小さい順にランク付けする
+func (s *UserRankingQueryService) rankedUserHighScores(rankingID int) (*domain.Ranking, []usecase.UserRankDto, error) {
+	var ranking domain.Ranking
+	var ok bool
+	var userRanks []usecase.UserRankDto
+	s.store.read(func() {
+		ranking, ok = s.store.rankings[rankingID]
+		if !ok {
+			return
+		}
+		for key, highScore := range s.store.userHighScores {
+			if key.RankingID != rankingID || !s.store.isVisibleUser(key.UserID) {
+				continue
+			}
+			userRanks = append(userRanks, usecase.UserRankDto{
+				UserID:     highScore.UserID,
+				UserName:   s.store.users[highScore.UserID].Name.Value,
+				Score:      usecase.NewScoreDto(highScore.Score, ranking.ScoreType),
+				AchievedAt: highScore.Timestamp,
+			})
+		}
+	})
+	if !ok {
+		return nil, nil, rankingNotFoundError(rankingID)
+	}
+
+	// ランク付けと同じ並び順で並べてランクを振る
+	slices.SortFunc(userRanks, func(a, b usecase.UserRankDto) int {
+		if ranksAbove(ranking.SortOrder, a.Score.Value, a.AchievedAt, a.UserID, b.Score.Value, b.AchievedAt, b.UserID) {
+			return -1
+		}
+		if ranksAbove(ranking.SortOrder, b.Score.Value, b.AchievedAt, b.UserID, a.Score.Value, a.AchievedAt, a.UserID) {
+			return 1
+		}
+		return 0
+	})
+	for i := range userRanks {
+		userRanks[i].Rank = i + 1
+	}
+	return &ranking, userRanks, nil
+}
+
+// ランキングの並び順でハイスコアaがハイスコアbより上位かを判定する
+func ranksAbove(sortOrder domain.SortOrder, aScore domain.Score, aTimestamp time.Time, aUserID int, bScore domain.Score, bTimestamp time.Time, bUserID int) bool {
+	if aScore != bScore {
+		return sortOrder.Beats(aScore, bScore)
+	}
+	if !aTimestamp.Equal(bTimestamp) {
+		return aTimestamp.Before(bTimestamp)
+	}
+	return aUserID < bUserID
+}
+
+// 基準のハイスコアより上位の件数 (ランク順に並んだスライスで基準の位置) を返す
+func positionAbove(userRanks []usecase.UserRankDto, sortOrder domain.SortOrder, score domain.Score, timestamp time.Time, userID int) int {
+	i, _ := slices.BinarySearchFunc(userRanks, true, func(userRank usecase.UserRankDto, _ bool) int {
+		if ranksAbove(sortOrder, userRank.Score.Value, userRank.AchievedAt, userRank.UserID, score, timestamp, userID) {
+			return -1
+		}
+		return 1
+	})
+	return i
+}
+
+// 基準のハイスコアより下位の最初の位置を返す
+func positionBelow(userRanks []usecase.UserRankDto, sortOrder domain.SortOrder, score domain.Score, timestamp time.Time, userID int) int {
+	i, _ := slices.BinarySearchFunc(userRanks, true, func(userRank usecase.UserRankDto, _ bool) int {
+		if ranksAbove(sortOrder, score, timestamp, userID, userRank.Score.Value, userRank.AchievedAt, userRank.UserID) {
+			return 1
+		}
+		return -1
+	})
+	return i
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコアの一覧でランキングを作成する (ユーザーIDは1から順に振られる)
+func newRankingWithScores(t *testing.T, scores ...domain.Score) (*Store, int) {
+	store := NewStore()
+	ctx := context.Background()
+	gameName, _ := domain.NewGameName("game")
+	game, err := NewGameRepository(store).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName("ranking")
+	ranking, err := NewRankingRepository(store).Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}})
+	require.NoError(t, err)
+	for i, score := range scores {
+		userName, _ := domain.NewUserName(fmt.Sprintf("user-%d", i+1))
+		user, err := NewUserRepository(store).Create(ctx, userName)
+		require.NoError(t, err)
+		_, err = NewUserHighScoreRepository(store).Upsert(ctx, ranking.ID, user.ID, score, domain.SortOrderDesc)
+		require.NoError(t, err)
+	}
+	return store, ranking.ID
+}
+
+// ランクを取り出す
+func ranksOf(userRanks []usecase.UserRankDto) []int {
+	ranks := make([]int, 0, len(userRanks))
+	for _, userRank := range userRanks {
+		ranks = append(ranks, userRank.Rank)
+	}
+	return ranks
+}
+
+// カーソルで前後のページを行き来できる
+func TestFetchUserRankingPaging(t *testing.T) {
+	store, rankingID := newRankingWithScores(t, 10, 50, 30, 50, 20)
+	queryService := NewUserRankingQueryService(store)
+	ctx := context.Background()
+
+	// 1ページ目 (同点は先に登録したユーザーが上位)
+	query, err := usecase.NewUserRankingQuery(rankingID, "rank_asc", 2, nil)
+	require.NoError(t, err)
+	first, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ranksOf(first.UserRanks))
+	assert.Equal(t, 2, first.UserRanks[0].UserID)
+	assert.True(t, first.HasNext)
+	assert.False(t, first.HasPrev)
+
+	// 2ページ目
+	last := first.UserRanks[1]
+	query.Cursor = &usecase.UserRankingCursor{Score: last.Score.Value, Timestamp: last.AchievedAt, UserID: last.UserID, Direction: domain.PageNext}
+	second, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, ranksOf(second.UserRanks))
+	assert.True(t, second.HasNext)
+	assert.True(t, second.HasPrev)
+
+	// 2ページ目から前のページに戻る
+	head := second.UserRanks[0]
+	query.Cursor = &usecase.UserRankingCursor{Score: head.Score.Value, Timestamp: head.AchievedAt, UserID: head.UserID, Direction: domain.PagePrev}
+	back, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ranksOf(back.UserRanks))
+	assert.True(t, back.HasNext)
+	assert.False(t, back.HasPrev)
+
+	// 下位から並べる
+	query, err = usecase.NewUserRankingQuery(rankingID, "rank_desc", 2, nil)
+	require.NoError(t, err)
+	desc, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 4}, ranksOf(desc.UserRanks))
+	assert.True(t, desc.HasNext)
+}
+
+// 前後のユーザーランクはランキングの端では存在する分だけを返す
+func TestFetchUserRankingAround(t *testing.T) {
+	store, rankingID := newRankingWithScores(t, 50, 40, 30, 20, 10)
+	queryService := NewUserRankingQueryService(store)
+	ctx := context.Background()
+
+	query, err := usecase.NewUserRankingAroundQuery(rankingID, 2, nil, nil)
+	require.NoError(t, err)
+	around, err := queryService.FetchUserRankingAround(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 2, around.Rank)
+	assert.Equal(t, 5, around.TotalUsers)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ranksOf(around.UserRanks))
+
+	// ハイスコアが未登録の場合はNotFound
+	query, err = usecase.NewUserRankingAroundQuery(rankingID, 6, nil, nil)
+	require.NoError(t, err)
+	_, err = queryService.FetchUserRankingAround(ctx, query)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
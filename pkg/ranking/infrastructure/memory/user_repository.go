@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// ユーザーリポジトリ
+type UserRepository struct {
+	store *Store
+}
+
+// リポジトリを生成する
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{
+		store: store,
+	}
+}
+
+// ユーザーを取得する
+func (r *UserRepository) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	var user domain.User
+	var ok bool
+	r.store.read(func() {
+		user, ok = r.store.users[id]
+	})
+
+	// 存在しない場合と論理削除済みの場合はNotFoundエラーを返す
+	if !ok || user.IsDeleted() {
+		return nil, userNotFoundError(id)
+	}
+	return &user, nil
+}
+
+// ユーザー一覧を取得する
+func (r *UserRepository) FindAll(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.User], error) {
+	var users []domain.User
+	r.store.read(func() {
+		for _, user := range r.store.users {
+			if !user.IsDeleted() {
+				users = append(users, user)
+			}
+		}
+	})
+	return idPage(users, func(user domain.User) int { return user.ID }, page), nil
+}
+
+// ユーザーを登録する
+func (r *UserRepository) Create(ctx context.Context, name domain.UserName) (*domain.User, error) {
+	var user domain.User
+	err := r.store.write(ctx, func() error {
+		// IDを採番して登録する
+		r.store.advanceID(&r.store.lastUserID)
+		now := time.Now().UTC()
+		user = domain.User{
+			ID:        r.store.lastUserID,
+			Name:      name,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		setEntry(r.store, r.store.users, user.ID, user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ユーザーを論理削除する
+func (r *UserRepository) Delete(ctx context.Context, id int) error {
+	return r.store.write(ctx, func() error {
+		// 存在しない場合と論理削除済みの場合はNotFoundエラーを返す
+		user, ok := r.store.users[id]
+		if !ok || user.IsDeleted() {
+			return userNotFoundError(id)
+		}
+
+		// 削除日時を記録する (ハイスコアは残す)
+		now := time.Now().UTC()
+		user.DeletedAt = &now
+		user.UpdatedAt = now
+		setEntry(r.store, r.store.users, id, user)
+
+		// 残したハイスコアをランキングの分布の集計値から取り除く
+		r.store.updateUserScoreStats(id, domain.ScoreMoments.Remove)
+		return nil
+	})
+}
+
+// 論理削除したユーザーを元に戻す
+func (r *UserRepository) Restore(ctx context.Context, id int) (*domain.User, error) {
+	var user domain.User
+	err := r.store.write(ctx, func() error {
+		// 存在しない場合はNotFoundエラーを返す
+		var ok bool
+		user, ok = r.store.users[id]
+		if !ok {
+			return userNotFoundError(id)
+		}
+
+		// 削除済みの場合のみ削除日時を消す
+		if user.IsDeleted() {
+			user.DeletedAt = nil
+			user.UpdatedAt = time.Now().UTC()
+			setEntry(r.store, r.store.users, id, user)
+
+			// 残していたハイスコアをランキングの分布の集計値に戻す
+			r.store.updateUserScoreStats(id, domain.ScoreMoments.Add)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ユーザーをシャドウバンする
+func (r *UserRepository) ShadowBan(ctx context.Context, id int) error {
+	return r.store.write(ctx, func() error {
+		// 存在しない場合と論理削除済みの場合はNotFoundエラーを返す
+		user, ok := r.store.users[id]
+		if !ok || user.IsDeleted() {
+			return userNotFoundError(id)
+		}
+
+		// シャドウバンしていない場合のみ日時を記録する
+		if !user.IsShadowBanned() {
+			now := time.Now().UTC()
+			user.ShadowBannedAt = &now
+			user.UpdatedAt = now
+			setEntry(r.store, r.store.users, id, user)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,4 @@
+DROP TABLE user_high_scores;
+DROP TABLE rankings;
+DROP TABLE games;
+DROP TABLE users;
@@ -0,0 +1,37 @@
+-- ユーザーテーブル
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ゲームテーブル
+CREATE TABLE games (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ランキングテーブル
+CREATE TABLE rankings (
+    id SERIAL PRIMARY KEY,
+    game_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_rankings_game_id_name UNIQUE (game_id, name),  -- ランキング名はゲーム内でユニーク
+    CONSTRAINT fk_rankings_game_id FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+
+-- ユーザーハイスコアテーブル
+CREATE TABLE user_high_scores (
+    ranking_id INT NOT NULL,
+    user_id INT NOT NULL,
+    high_score INT NOT NULL,
+    timestamp TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT pk_user_scores PRIMARY KEY (ranking_id, user_id),
+    CONSTRAINT fk_ranking_id FOREIGN KEY (ranking_id) REFERENCES rankings(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
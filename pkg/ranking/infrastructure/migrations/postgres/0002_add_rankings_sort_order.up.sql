@@ -0,0 +1,3 @@
+-- ランキングの並び順 (desc: スコアが大きいほど上位、asc: スコアが小さいほど上位)
+ALTER TABLE rankings ADD COLUMN sort_order VARCHAR(4) NOT NULL DEFAULT 'desc'
+    CONSTRAINT ck_rankings_sort_order CHECK (sort_order IN ('desc', 'asc'));
@@ -0,0 +1,5 @@
+-- ハイスコアを32bitに戻す
+ALTER TABLE user_high_scores ALTER COLUMN high_score TYPE INT;
+
+-- ランキングのスコアの型を削除
+ALTER TABLE rankings DROP COLUMN score_type, DROP COLUMN score_scale;
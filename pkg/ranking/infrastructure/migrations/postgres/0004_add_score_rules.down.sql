@@ -0,0 +1,2 @@
+-- ランキングのスコアの制約を削除
+ALTER TABLE rankings DROP COLUMN min_score, DROP COLUMN max_score, DROP COLUMN score_step;
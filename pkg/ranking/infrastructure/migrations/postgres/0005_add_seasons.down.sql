@@ -0,0 +1,5 @@
+-- シーズンを削除
+DROP TABLE archived_user_high_scores;
+DROP TABLE seasons;
+DROP INDEX ix_rankings_season_ends_at;
+ALTER TABLE rankings DROP COLUMN recurrence, DROP COLUMN timezone, DROP COLUMN season, DROP COLUMN season_started_at, DROP COLUMN season_ends_at;
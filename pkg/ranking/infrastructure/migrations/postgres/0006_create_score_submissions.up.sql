@@ -0,0 +1,17 @@
+-- スコアの送信履歴 (ハイスコアを更新したかどうかに関わらず、送信されたスコアをすべて記録する)
+-- ip_address、user_agent、client_versionは送信したクライアントの情報 (不明な場合は空文字)
+CREATE TABLE score_submissions (
+    id SERIAL PRIMARY KEY,
+    ranking_id INT NOT NULL,
+    user_id INT NOT NULL,
+    score BIGINT NOT NULL,
+    ip_address VARCHAR(256) NOT NULL DEFAULT '',
+    user_agent VARCHAR(256) NOT NULL DEFAULT '',
+    client_version VARCHAR(256) NOT NULL DEFAULT '',
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_score_submissions_ranking_id FOREIGN KEY (ranking_id) REFERENCES rankings(id) ON DELETE CASCADE,
+    CONSTRAINT fk_score_submissions_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- ランキングとユーザーを指定して送信順に取得するためのインデックス
+CREATE INDEX ix_score_submissions_ranking_id_user_id ON score_submissions (ranking_id, user_id, id);
@@ -0,0 +1,2 @@
+-- APIキーを削除
+DROP TABLE api_keys;
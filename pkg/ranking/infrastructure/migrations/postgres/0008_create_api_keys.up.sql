@@ -0,0 +1,14 @@
+-- APIキー (キーの文字列は保存せず、SHA-256のハッシュ値と識別用の先頭部分のみを保存する)
+-- scopesはカンマ区切りのスコープ、game_idは操作できるゲーム (NULLの場合は全てのゲーム)
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes VARCHAR(200) NOT NULL,
+    game_id INT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMPTZ NULL,
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash),
+    CONSTRAINT fk_api_keys_game_id FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
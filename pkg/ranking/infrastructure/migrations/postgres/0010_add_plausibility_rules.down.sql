@@ -0,0 +1,3 @@
+-- スコアの妥当性のルールを削除
+ALTER TABLE score_submissions DROP COLUMN status, DROP COLUMN flagged_rules;
+ALTER TABLE rankings DROP COLUMN max_improvement_ratio, DROP COLUMN min_submission_interval_ms, DROP COLUMN score_cap, DROP COLUMN max_z_score;
@@ -0,0 +1,16 @@
+-- ランキングのスコアの妥当性のルール (NULLの場合はルールなし。満たさないスコアは審査待ちにする)
+-- max_improvement_ratio: 前回のハイスコアからの改善の倍率の上限、min_submission_interval_ms: 同じユーザーの送信の最短間隔 (ミリ秒)
+-- score_cap: 審査なしで受け付けるスコアの上限 (スコアと同じく桁数だけ10倍した整数)、max_z_score: ハイスコアの分布に対する標準得点の上限
+ALTER TABLE rankings
+    ADD COLUMN max_improvement_ratio DOUBLE PRECISION NULL
+        CONSTRAINT ck_rankings_max_improvement_ratio CHECK (max_improvement_ratio > 1),
+    ADD COLUMN min_submission_interval_ms BIGINT NULL
+        CONSTRAINT ck_rankings_min_submission_interval_ms CHECK (min_submission_interval_ms > 0),
+    ADD COLUMN score_cap BIGINT NULL,
+    ADD COLUMN max_z_score DOUBLE PRECISION NULL
+        CONSTRAINT ck_rankings_max_z_score CHECK (max_z_score > 0);
+
+-- 送信の状態 (accepted: ハイスコアに反映した、pending_review: 審査待ち) と審査待ちにした理由のルールの名前 (カンマ区切り)
+ALTER TABLE score_submissions
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'accepted',
+    ADD COLUMN flagged_rules VARCHAR(200) NULL;
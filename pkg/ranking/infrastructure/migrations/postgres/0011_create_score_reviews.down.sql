@@ -0,0 +1,4 @@
+-- スコアの審査とユーザーのシャドウバン日時を削除
+ALTER TABLE users DROP COLUMN shadow_banned_at;
+DROP INDEX ix_score_submissions_status;
+DROP TABLE score_reviews;
@@ -0,0 +1,22 @@
+-- スコアの審査 (審査待ちの送信に対する承認または却下の判断。送信ごとに1件)
+-- reviewer_api_key_idとreviewerは審査したAPIキーのIDと名前 (管理用のキーの場合はIDがNULL)、flagged_atは審査待ちにした日時 (送信日時)
+CREATE TABLE score_reviews (
+    id SERIAL PRIMARY KEY,
+    submission_id INT NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    reviewer_api_key_id INT NULL,
+    reviewer VARCHAR(50) NOT NULL,
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    shadow_banned BOOLEAN NOT NULL DEFAULT FALSE,
+    flagged_at TIMESTAMPTZ NOT NULL,
+    reviewed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_score_reviews_submission_id UNIQUE (submission_id),
+    CONSTRAINT ck_score_reviews_decision CHECK (decision IN ('approved', 'rejected')),
+    CONSTRAINT fk_score_reviews_submission_id FOREIGN KEY (submission_id) REFERENCES score_submissions(id) ON DELETE CASCADE
+);
+
+-- 審査待ちの送信履歴を送信順に取得するためのインデックス
+CREATE INDEX ix_score_submissions_status ON score_submissions (status, id);
+
+-- ユーザーのシャドウバン日時 (NULLの場合はシャドウバンしていない。以降の送信はハイスコアに反映しない)
+ALTER TABLE users ADD COLUMN shadow_banned_at TIMESTAMPTZ NULL;
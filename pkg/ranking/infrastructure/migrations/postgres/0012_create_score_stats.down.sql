@@ -0,0 +1,2 @@
+-- ハイスコアの分布の集計値を削除
+DROP TABLE score_stats;
@@ -0,0 +1,37 @@
+-- ユーザーテーブル
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ゲームテーブル
+CREATE TABLE games (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ランキングテーブル
+CREATE TABLE rankings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    game_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_rankings_game_id_name UNIQUE (game_id, name),  -- ランキング名はゲーム内でユニーク
+    CONSTRAINT fk_rankings_game_id FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+
+-- ユーザーハイスコアテーブル
+CREATE TABLE user_high_scores (
+    ranking_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    high_score INTEGER NOT NULL,
+    timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT pk_user_scores PRIMARY KEY (ranking_id, user_id),
+    CONSTRAINT fk_ranking_id FOREIGN KEY (ranking_id) REFERENCES rankings(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
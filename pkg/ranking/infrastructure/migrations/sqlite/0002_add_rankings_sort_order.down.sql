@@ -0,0 +1,2 @@
+-- ランキングの並び順を削除
+ALTER TABLE rankings DROP COLUMN sort_order;
@@ -0,0 +1,3 @@
+-- ランキングの並び順 (desc: スコアが大きいほど上位、asc: スコアが小さいほど上位)
+ALTER TABLE rankings ADD COLUMN sort_order TEXT NOT NULL DEFAULT 'desc'
+    CHECK (sort_order IN ('desc', 'asc'));
@@ -0,0 +1,3 @@
+-- ランキングのスコアの型を削除
+ALTER TABLE rankings DROP COLUMN score_scale;
+ALTER TABLE rankings DROP COLUMN score_type;
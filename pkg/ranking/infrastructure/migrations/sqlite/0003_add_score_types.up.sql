@@ -0,0 +1,6 @@
+-- ランキングのスコアの型 (integer: 整数、decimal: 固定小数点数、duration: 所要時間(ミリ秒))
+-- score_scaleは固定小数点数の小数点以下の桁数
+-- SQLiteのINTEGERは64bitのため、小数のスコアを桁数だけ10倍した整数で保存してもハイスコアの列は変更不要
+ALTER TABLE rankings ADD COLUMN score_type TEXT NOT NULL DEFAULT 'integer'
+    CHECK (score_type IN ('integer', 'decimal', 'duration'));
+ALTER TABLE rankings ADD COLUMN score_scale INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- ランキングのスコアの制約を削除
+ALTER TABLE rankings DROP COLUMN score_step;
+ALTER TABLE rankings DROP COLUMN max_score;
+ALTER TABLE rankings DROP COLUMN min_score;
@@ -0,0 +1,5 @@
+-- ランキングのスコアの制約 (NULLの場合は制約なし。値はスコアと同じく桁数だけ10倍した整数)
+-- min_score: 最小スコア、max_score: 最大スコア、score_step: 刻み幅
+ALTER TABLE rankings ADD COLUMN min_score INTEGER NULL;
+ALTER TABLE rankings ADD COLUMN max_score INTEGER NULL;
+ALTER TABLE rankings ADD COLUMN score_step INTEGER NULL CHECK (score_step > 0);
@@ -0,0 +1,9 @@
+-- シーズンを削除
+DROP TABLE archived_user_high_scores;
+DROP TABLE seasons;
+DROP INDEX ix_rankings_season_ends_at;
+ALTER TABLE rankings DROP COLUMN season_ends_at;
+ALTER TABLE rankings DROP COLUMN season_started_at;
+ALTER TABLE rankings DROP COLUMN season;
+ALTER TABLE rankings DROP COLUMN timezone;
+ALTER TABLE rankings DROP COLUMN recurrence;
@@ -0,0 +1,2 @@
+-- ユーザーの論理削除日時を削除
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,2 @@
+-- ユーザーの論理削除日時 (NULLの場合は削除されていない)
+ALTER TABLE users ADD COLUMN deleted_at DATETIME NULL;
@@ -0,0 +1,14 @@
+-- APIキー (キーの文字列は保存せず、SHA-256のハッシュ値と識別用の先頭部分のみを保存する)
+-- scopesはカンマ区切りのスコープ、game_idは操作できるゲーム (NULLの場合は全てのゲーム)
+CREATE TABLE api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    key_prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL,
+    scopes TEXT NOT NULL,
+    game_id INTEGER NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME NULL,
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash),
+    CONSTRAINT fk_api_keys_game_id FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
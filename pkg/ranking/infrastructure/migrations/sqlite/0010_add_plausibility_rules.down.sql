@@ -0,0 +1,7 @@
+-- スコアの妥当性のルールを削除
+ALTER TABLE score_submissions DROP COLUMN flagged_rules;
+ALTER TABLE score_submissions DROP COLUMN status;
+ALTER TABLE rankings DROP COLUMN max_z_score;
+ALTER TABLE rankings DROP COLUMN score_cap;
+ALTER TABLE rankings DROP COLUMN min_submission_interval_ms;
+ALTER TABLE rankings DROP COLUMN max_improvement_ratio;
@@ -0,0 +1,11 @@
+-- ランキングのスコアの妥当性のルール (NULLの場合はルールなし。満たさないスコアは審査待ちにする)
+-- max_improvement_ratio: 前回のハイスコアからの改善の倍率の上限、min_submission_interval_ms: 同じユーザーの送信の最短間隔 (ミリ秒)
+-- score_cap: 審査なしで受け付けるスコアの上限 (スコアと同じく桁数だけ10倍した整数)、max_z_score: ハイスコアの分布に対する標準得点の上限
+ALTER TABLE rankings ADD COLUMN max_improvement_ratio REAL NULL CHECK (max_improvement_ratio > 1);
+ALTER TABLE rankings ADD COLUMN min_submission_interval_ms INTEGER NULL CHECK (min_submission_interval_ms > 0);
+ALTER TABLE rankings ADD COLUMN score_cap INTEGER NULL;
+ALTER TABLE rankings ADD COLUMN max_z_score REAL NULL CHECK (max_z_score > 0);
+
+-- 送信の状態 (accepted: ハイスコアに反映した、pending_review: 審査待ち) と審査待ちにした理由のルールの名前 (カンマ区切り)
+ALTER TABLE score_submissions ADD COLUMN status TEXT NOT NULL DEFAULT 'accepted';
+ALTER TABLE score_submissions ADD COLUMN flagged_rules TEXT NULL;
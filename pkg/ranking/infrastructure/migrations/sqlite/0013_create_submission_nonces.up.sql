@@ -0,0 +1,11 @@
+-- スコアの送信の署名の使用済みのナンス (ゲームごとに一意。expires_atを過ぎた後はタイムスタンプで拒否されるため削除してよい)
+CREATE TABLE submission_nonces (
+    game_id INTEGER NOT NULL,
+    nonce TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    CONSTRAINT pk_submission_nonces PRIMARY KEY (game_id, nonce),
+    CONSTRAINT fk_submission_nonces_game_id FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+
+-- 期限切れのナンスを削除するためのインデックス
+CREATE INDEX ix_submission_nonces_expires_at ON submission_nonces (game_id, expires_at);
@@ -6,12 +6,23 @@ CREATE TABLE users (
     updated_at DATETIME2 DEFAULT GETDATE()
 );
 
+-- ゲームテーブル
+CREATE TABLE games (
+    id INT IDENTITY(1,1) PRIMARY KEY,
+    name NVARCHAR(100) NOT NULL UNIQUE,
+    created_at DATETIME2 DEFAULT GETDATE(),
+    updated_at DATETIME2 DEFAULT GETDATE()
+);
+
 -- ランキングテーブル
 CREATE TABLE rankings (
     id INT IDENTITY(1,1) PRIMARY KEY,
-    name NVARCHAR(100) NOT NULL UNIQUE,  -- ユニーク制約を追加
+    game_id INT NOT NULL,
+    name NVARCHAR(100) NOT NULL,
     created_at DATETIME2 DEFAULT GETDATE(),
-    updated_at DATETIME2 DEFAULT GETDATE()
+    updated_at DATETIME2 DEFAULT GETDATE(),
+    CONSTRAINT uq_rankings_game_id_name UNIQUE (game_id, name),  -- ランキング名はゲーム内でユニーク
+    CONSTRAINT fk_rankings_game_id FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
 );
 
 -- ユーザーハイスコアテーブル
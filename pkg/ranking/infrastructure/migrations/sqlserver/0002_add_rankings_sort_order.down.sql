@@ -0,0 +1,3 @@
+-- ランキングの並び順を削除
+ALTER TABLE rankings DROP CONSTRAINT ck_rankings_sort_order, df_rankings_sort_order;
+ALTER TABLE rankings DROP COLUMN sort_order;
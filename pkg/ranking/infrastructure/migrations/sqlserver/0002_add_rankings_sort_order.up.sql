@@ -0,0 +1,4 @@
+-- ランキングの並び順 (desc: スコアが大きいほど上位、asc: スコアが小さいほど上位)
+ALTER TABLE rankings ADD sort_order NVARCHAR(4) NOT NULL
+    CONSTRAINT df_rankings_sort_order DEFAULT 'desc'
+    CONSTRAINT ck_rankings_sort_order CHECK (sort_order IN ('desc', 'asc'));
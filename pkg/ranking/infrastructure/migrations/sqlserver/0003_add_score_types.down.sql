@@ -0,0 +1,6 @@
+-- ハイスコアを32bitに戻す
+ALTER TABLE user_high_scores ALTER COLUMN high_score INT NOT NULL;
+
+-- ランキングのスコアの型を削除
+ALTER TABLE rankings DROP CONSTRAINT ck_rankings_score_type, df_rankings_score_type, df_rankings_score_scale;
+ALTER TABLE rankings DROP COLUMN score_type, score_scale;
@@ -0,0 +1,11 @@
+-- ランキングのスコアの型 (integer: 整数、decimal: 固定小数点数、duration: 所要時間(ミリ秒))
+-- score_scaleは固定小数点数の小数点以下の桁数
+ALTER TABLE rankings ADD
+    score_type NVARCHAR(10) NOT NULL
+        CONSTRAINT df_rankings_score_type DEFAULT 'integer'
+        CONSTRAINT ck_rankings_score_type CHECK (score_type IN ('integer', 'decimal', 'duration')),
+    score_scale INT NOT NULL
+        CONSTRAINT df_rankings_score_scale DEFAULT 0;
+
+-- 小数のスコアは桁数だけ10倍した整数で保存するため64bitにする
+ALTER TABLE user_high_scores ALTER COLUMN high_score BIGINT NOT NULL;
@@ -0,0 +1,3 @@
+-- ランキングのスコアの制約を削除
+ALTER TABLE rankings DROP CONSTRAINT ck_rankings_score_step;
+ALTER TABLE rankings DROP COLUMN min_score, max_score, score_step;
@@ -0,0 +1,7 @@
+-- ランキングのスコアの制約 (NULLの場合は制約なし。値はスコアと同じく桁数だけ10倍した整数)
+-- min_score: 最小スコア、max_score: 最大スコア、score_step: 刻み幅
+ALTER TABLE rankings ADD
+    min_score BIGINT NULL,
+    max_score BIGINT NULL,
+    score_step BIGINT NULL
+        CONSTRAINT ck_rankings_score_step CHECK (score_step > 0);
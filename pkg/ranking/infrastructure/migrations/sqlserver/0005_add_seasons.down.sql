@@ -0,0 +1,6 @@
+-- シーズンを削除
+DROP TABLE archived_user_high_scores;
+DROP TABLE seasons;
+DROP INDEX ix_rankings_season_ends_at ON rankings;
+ALTER TABLE rankings DROP CONSTRAINT ck_rankings_recurrence;
+ALTER TABLE rankings DROP COLUMN recurrence, timezone, season, season_started_at, season_ends_at;
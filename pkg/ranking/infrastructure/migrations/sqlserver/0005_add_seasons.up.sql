@@ -0,0 +1,37 @@
+-- ランキングの繰り返し (daily: 毎日、weekly: 毎週月曜、monthly: 毎月1日。NULLの場合は繰り返さない)
+-- timezoneは期間の区切りを決めるタイムゾーン、season〜season_ends_atは進行中のシーズン
+ALTER TABLE rankings ADD
+    recurrence NVARCHAR(10) NULL
+        CONSTRAINT ck_rankings_recurrence CHECK (recurrence IN ('daily', 'weekly', 'monthly')),
+    timezone NVARCHAR(64) NULL,
+    season INT NULL,
+    season_started_at DATETIME2 NULL,
+    season_ends_at DATETIME2 NULL;
+
+-- シーズンが終了したランキングを検索するためのインデックス
+CREATE INDEX ix_rankings_season_ends_at ON rankings (season_ends_at);
+
+-- 終了したシーズン
+CREATE TABLE seasons (
+    ranking_id INT NOT NULL,
+    season INT NOT NULL,
+    started_at DATETIME2 NOT NULL,
+    ended_at DATETIME2 NOT NULL,
+    total_users INT NOT NULL
+        CONSTRAINT df_seasons_total_users DEFAULT 0,
+    CONSTRAINT pk_seasons PRIMARY KEY (ranking_id, season),
+    CONSTRAINT fk_seasons_ranking_id FOREIGN KEY (ranking_id) REFERENCES rankings(id) ON DELETE CASCADE
+);
+
+-- 終了したシーズンのユーザーハイスコア (終了時点のランク付けで保存する)
+CREATE TABLE archived_user_high_scores (
+    ranking_id INT NOT NULL,
+    season INT NOT NULL,
+    rank INT NOT NULL,
+    user_id INT NOT NULL,
+    high_score BIGINT NOT NULL,
+    timestamp DATETIME2 NOT NULL,
+    CONSTRAINT pk_archived_user_high_scores PRIMARY KEY (ranking_id, season, rank),
+    CONSTRAINT fk_archived_user_high_scores_season FOREIGN KEY (ranking_id, season) REFERENCES seasons(ranking_id, season) ON DELETE CASCADE,
+    CONSTRAINT fk_archived_user_high_scores_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- スコアの送信履歴を削除
+DROP TABLE score_submissions;
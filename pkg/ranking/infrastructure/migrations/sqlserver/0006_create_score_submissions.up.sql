@@ -0,0 +1,21 @@
+-- スコアの送信履歴 (ハイスコアを更新したかどうかに関わらず、送信されたスコアをすべて記録する)
+-- ip_address、user_agent、client_versionは送信したクライアントの情報 (不明な場合は空文字)
+CREATE TABLE score_submissions (
+    id INT IDENTITY(1,1) PRIMARY KEY,
+    ranking_id INT NOT NULL,
+    user_id INT NOT NULL,
+    score BIGINT NOT NULL,
+    ip_address NVARCHAR(256) NOT NULL
+        CONSTRAINT df_score_submissions_ip_address DEFAULT '',
+    user_agent NVARCHAR(256) NOT NULL
+        CONSTRAINT df_score_submissions_user_agent DEFAULT '',
+    client_version NVARCHAR(256) NOT NULL
+        CONSTRAINT df_score_submissions_client_version DEFAULT '',
+    submitted_at DATETIME2 NOT NULL
+        CONSTRAINT df_score_submissions_submitted_at DEFAULT GETDATE(),
+    CONSTRAINT fk_score_submissions_ranking_id FOREIGN KEY (ranking_id) REFERENCES rankings(id) ON DELETE CASCADE,
+    CONSTRAINT fk_score_submissions_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- ランキングとユーザーを指定して送信順に取得するためのインデックス
+CREATE INDEX ix_score_submissions_ranking_id_user_id ON score_submissions (ranking_id, user_id, id);
@@ -0,0 +1,15 @@
+-- APIキー (キーの文字列は保存せず、SHA-256のハッシュ値と識別用の先頭部分のみを保存する)
+-- scopesはカンマ区切りのスコープ、game_idは操作できるゲーム (NULLの場合は全てのゲーム)
+CREATE TABLE api_keys (
+    id INT IDENTITY(1,1) PRIMARY KEY,
+    name NVARCHAR(50) NOT NULL,
+    key_prefix NVARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes NVARCHAR(200) NOT NULL,
+    game_id INT NULL,
+    created_at DATETIME2 NOT NULL
+        CONSTRAINT df_api_keys_created_at DEFAULT GETDATE(),
+    revoked_at DATETIME2 NULL,
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash),
+    CONSTRAINT fk_api_keys_game_id FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- スコアの送信の署名に使う秘密鍵を削除
+ALTER TABLE games DROP COLUMN submission_secret;
@@ -0,0 +1,2 @@
+-- スコアの送信の署名に使う秘密鍵 (NULLの場合は署名を求めない)
+ALTER TABLE games ADD submission_secret NVARCHAR(100) NULL;
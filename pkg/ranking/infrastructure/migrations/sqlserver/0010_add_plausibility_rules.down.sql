@@ -0,0 +1,5 @@
+-- スコアの妥当性のルールを削除
+ALTER TABLE score_submissions DROP CONSTRAINT df_score_submissions_status;
+ALTER TABLE score_submissions DROP COLUMN status, flagged_rules;
+ALTER TABLE rankings DROP CONSTRAINT ck_rankings_max_improvement_ratio, ck_rankings_min_submission_interval_ms, ck_rankings_max_z_score;
+ALTER TABLE rankings DROP COLUMN max_improvement_ratio, min_submission_interval_ms, score_cap, max_z_score;
@@ -0,0 +1,25 @@
+-- スコアの審査 (審査待ちの送信に対する承認または却下の判断。送信ごとに1件)
+-- reviewer_api_key_idとreviewerは審査したAPIキーのIDと名前 (管理用のキーの場合はIDがNULL)、flagged_atは審査待ちにした日時 (送信日時)
+CREATE TABLE score_reviews (
+    id INT IDENTITY(1,1) PRIMARY KEY,
+    submission_id INT NOT NULL,
+    decision NVARCHAR(20) NOT NULL,
+    reviewer_api_key_id INT NULL,
+    reviewer NVARCHAR(50) NOT NULL,
+    reason NVARCHAR(500) NOT NULL
+        CONSTRAINT df_score_reviews_reason DEFAULT '',
+    shadow_banned BIT NOT NULL
+        CONSTRAINT df_score_reviews_shadow_banned DEFAULT 0,
+    flagged_at DATETIME2 NOT NULL,
+    reviewed_at DATETIME2 NOT NULL
+        CONSTRAINT df_score_reviews_reviewed_at DEFAULT GETDATE(),
+    CONSTRAINT uq_score_reviews_submission_id UNIQUE (submission_id),
+    CONSTRAINT ck_score_reviews_decision CHECK (decision IN ('approved', 'rejected')),
+    CONSTRAINT fk_score_reviews_submission_id FOREIGN KEY (submission_id) REFERENCES score_submissions(id) ON DELETE CASCADE
+);
+
+-- 審査待ちの送信履歴を送信順に取得するためのインデックス
+CREATE INDEX ix_score_submissions_status ON score_submissions (status, id);
+
+-- ユーザーのシャドウバン日時 (NULLの場合はシャドウバンしていない。以降の送信はハイスコアに反映しない)
+ALTER TABLE users ADD shadow_banned_at DATETIME2 NULL;
@@ -0,0 +1,38 @@
+-- ランキングの進行中のシーズンのハイスコアの分布の集計値 (論理削除したユーザーを除く。ランキングごとに1件)
+-- score_m2は平均からの偏差の二乗和。ハイスコアの保存ごとにWelfordのアルゴリズムで更新し、シーズンの終了で0に戻す
+CREATE TABLE score_stats (
+    ranking_id INT PRIMARY KEY,
+    score_count INT NOT NULL
+        CONSTRAINT df_score_stats_score_count DEFAULT 0,
+    score_mean FLOAT NOT NULL
+        CONSTRAINT df_score_stats_score_mean DEFAULT 0,
+    score_m2 FLOAT NOT NULL
+        CONSTRAINT df_score_stats_score_m2 DEFAULT 0,
+    CONSTRAINT fk_score_stats_ranking_id FOREIGN KEY (ranking_id) REFERENCES rankings(id) ON DELETE CASCADE
+);
+
+-- 既存のランキングの分布を集計する (平均を求めてから偏差の二乗和を求め、桁落ちを避ける)
+INSERT INTO score_stats (ranking_id, score_count, score_mean, score_m2)
+SELECT rankings.id, COALESCE(means.score_count, 0), COALESCE(means.score_mean, 0), COALESCE(deviations.score_m2, 0)
+FROM rankings
+LEFT JOIN (
+    SELECT uhs.ranking_id, COUNT(*) AS score_count, AVG(CAST(uhs.high_score AS FLOAT)) AS score_mean
+    FROM user_high_scores AS uhs
+    JOIN users ON users.id = uhs.user_id
+    WHERE users.deleted_at IS NULL
+    GROUP BY uhs.ranking_id
+) AS means ON means.ranking_id = rankings.id
+LEFT JOIN (
+    SELECT uhs.ranking_id, SUM((CAST(uhs.high_score AS FLOAT) - m.score_mean) * (CAST(uhs.high_score AS FLOAT) - m.score_mean)) AS score_m2
+    FROM user_high_scores AS uhs
+    JOIN users ON users.id = uhs.user_id
+    JOIN (
+        SELECT uhs.ranking_id, AVG(CAST(uhs.high_score AS FLOAT)) AS score_mean
+        FROM user_high_scores AS uhs
+        JOIN users ON users.id = uhs.user_id
+        WHERE users.deleted_at IS NULL
+        GROUP BY uhs.ranking_id
+    ) AS m ON m.ranking_id = uhs.ranking_id
+    WHERE users.deleted_at IS NULL
+    GROUP BY uhs.ranking_id
+) AS deviations ON deviations.ranking_id = rankings.id;
@@ -0,0 +1,2 @@
+-- スコアの送信の署名の使用済みのナンスを削除
+DROP TABLE submission_nonces;
@@ -0,0 +1,299 @@
+package infrastructure
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// データベースの種類ごとのマイグレーションファイル (migrations/<種類>/<バージョン>_<名前>.{up,down}.sql)
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// 適用済みのマイグレーションを記録するテーブルの作成文
+var schemaMigrationsDDL = map[Dialect]string{
+	DialectSQLServer: `IF OBJECT_ID('schema_migrations', 'U') IS NULL
+		CREATE TABLE schema_migrations (version INT PRIMARY KEY, name NVARCHAR(255) NOT NULL, applied_at DATETIME2 DEFAULT GETDATE())`,
+	DialectPostgres: `CREATE TABLE IF NOT EXISTS schema_migrations (version INT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP)`,
+	DialectSQLite:   `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at DATETIME DEFAULT CURRENT_TIMESTAMP)`,
+}
+
+// マイグレーション
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// 適用済みのマイグレーション
+type SchemaMigration struct {
+	bun.BaseModel `bun:"table:schema_migrations"`
+
+	Version   int       `bun:"version,pk"`
+	Name      string    `bun:"name"`
+	AppliedAt time.Time `bun:"applied_at,nullzero,default:CURRENT_TIMESTAMP"`
+}
+
+// マイグレーションの適用状況
+type MigrationStatus struct {
+	Version int
+	Name    string
+
+	// 適用日時 (未適用の場合はnil)
+	AppliedAt *time.Time
+}
+
+// マイグレーションの実行
+type Migrator struct {
+	db         *bun.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// 接続先のデータベースの種類に応じたマイグレーションを読み込んで生成する
+func NewMigrator(db *bun.DB) (*Migrator, error) {
+	dialect, err := dialectOf(db)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := loadMigrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}, nil
+}
+
+// 最新のマイグレーションのバージョンを返す
+func (m *Migrator) LatestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// 適用済みの最大のバージョンを返す (未適用の場合は0)
+func (m *Migrator) CurrentVersion(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	current := 0
+	for version := range applied {
+		current = max(current, version)
+	}
+	return current, nil
+}
+
+// すべてのマイグレーションの適用状況をバージョン順に返す
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if schemaMigration, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &schemaMigration.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// 未適用のマイグレーションをすべて適用する
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.To(ctx, m.LatestVersion())
+}
+
+// 最後に適用したマイグレーションを1つ取り消す
+func (m *Migrator) Down(ctx context.Context) ([]Migration, error) {
+	current, err := m.CurrentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1つ前のバージョンまで戻す
+	target := 0
+	for _, migration := range m.migrations {
+		if migration.Version < current {
+			target = migration.Version
+		}
+	}
+	return m.To(ctx, target)
+}
+
+// 指定したバージョンまで適用または取り消す (0の場合はすべて取り消す)
+// 実行したマイグレーションを実行順に返す
+func (m *Migrator) To(ctx context.Context, version int) ([]Migration, error) {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == version }) {
+		return nil, fmt.Errorf("unknown migration version: %d", version)
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var executed []Migration
+
+	// 指定したバージョン以下の未適用のマイグレーションを古い順に適用する
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok || migration.Version > version {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return executed, err
+		}
+		executed = append(executed, migration)
+	}
+
+	// 指定したバージョンより新しい適用済みのマイグレーションを新しい順に取り消す
+	for _, migration := range slices.Backward(m.migrations) {
+		if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+			continue
+		}
+		if err := m.revert(ctx, migration); err != nil {
+			return executed, err
+		}
+		executed = append(executed, migration)
+	}
+
+	return executed, nil
+}
+
+// スキーマが最新であることを確認する (未適用のマイグレーションがある場合はエラー)
+func (m *Migrator) EnsureUpToDate(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			return fmt.Errorf("database schema is behind: migration %04d_%s is not applied (run `migrate up`)", status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+// マイグレーションを適用して記録する
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	return m.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.NewInsert().Model(&SchemaMigration{Version: migration.Version, Name: migration.Name}).Exec(ctx)
+		return err
+	})
+}
+
+// マイグレーションを取り消して記録を削除する
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	return m.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return fmt.Errorf("failed to revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.NewDelete().Model((*SchemaMigration)(nil)).Where("version = ?", migration.Version).Exec(ctx)
+		return err
+	})
+}
+
+// 適用済みのマイグレーションをバージョンをキーとして取得する (記録用のテーブルがなければ作成する)
+func (m *Migrator) applied(ctx context.Context) (map[int]SchemaMigration, error) {
+	if _, err := m.db.ExecContext(ctx, schemaMigrationsDDL[m.dialect]); err != nil {
+		return nil, err
+	}
+
+	var schemaMigrations []SchemaMigration
+	if err := m.db.NewSelect().Model(&schemaMigrations).Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]SchemaMigration, len(schemaMigrations))
+	for _, schemaMigration := range schemaMigrations {
+		applied[schemaMigration.Version] = schemaMigration
+	}
+	return applied, nil
+}
+
+// データベースの種類のマイグレーションファイルを読み込んでバージョン順に並べる
+func loadMigrations(dialect Dialect) ([]Migration, error) {
+	dir := path.Join("migrations", string(dialect))
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// ファイル名 (<バージョン>_<名前>.{up,down}.sql) からバージョンごとにまとめる
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		versionText, name, found := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionText)
+		if !ok || !found || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d has different names: %s, %s", version, migration.Name, name)
+		}
+		switch direction {
+		case "up":
+			migration.Up = string(content)
+		case "down":
+			migration.Down = string(content)
+		default:
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+	}
+
+	// 適用と取り消しの両方が揃っていることを確認してバージョン順に並べる
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+	return migrations, nil
+}
+
+// 接続先のデータベースの種類を返す
+func dialectOf(db bun.IDB) (Dialect, error) {
+	switch db.Dialect().Name() {
+	case dialect.MSSQL:
+		return DialectSQLServer, nil
+	case dialect.PG:
+		return DialectPostgres, nil
+	case dialect.SQLite:
+		return DialectSQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported database dialect: %s", db.Dialect().Name())
+	}
+}
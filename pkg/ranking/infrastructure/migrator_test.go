@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// マイグレーションの適用と取り消し
+func TestMigrator(t *testing.T) {
+	db, err := OpenDB(DialectSQLite, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	require.Positive(t, migrator.LatestVersion())
+
+	// 未適用の場合はスキーマが古いため起動できない
+	assert.Error(t, migrator.EnsureUpToDate(ctx))
+
+	// すべて適用する
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, applied)
+	assert.NoError(t, migrator.EnsureUpToDate(ctx))
+	current, err := migrator.CurrentVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, migrator.LatestVersion(), current)
+
+	// 適用済みの場合は何もしない
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// 1つ取り消すとスキーマが古くなる
+	reverted, err := migrator.Down(ctx)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, migrator.LatestVersion(), reverted[0].Version)
+	assert.Error(t, migrator.EnsureUpToDate(ctx))
+
+	// すべて取り消す
+	_, err = migrator.To(ctx, 0)
+	require.NoError(t, err)
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.Nil(t, status.AppliedAt)
+	}
+
+	// 存在しないバージョンは指定できない
+	_, err = migrator.To(ctx, migrator.LatestVersion()+1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"slices"
+
+	"github.com/uptrace/bun"
+)
+
+// IDをキーとしたページングの条件をクエリに追加する
+// 続きの有無を判定するため、取得件数より1件多く取得する
+func applyIDPage(q *bun.SelectQuery, column string, page domain.IDPageRequest) *bun.SelectQuery {
+	switch {
+	case page.Direction == domain.PagePrev:
+		q = q.Where("? < ?", bun.Ident(column), page.CursorID).OrderExpr("? DESC", bun.Ident(column))
+	case page.CursorID > 0:
+		q = q.Where("? > ?", bun.Ident(column), page.CursorID).OrderExpr("? ASC", bun.Ident(column))
+	default:
+		q = q.OrderExpr("? ASC", bun.Ident(column))
+	}
+	return q.Limit(page.Limit + 1)
+}
+
+// 取得件数より1件多く取得した結果をページに変換する
+// 前方向に取得した場合は降順で取得しているため昇順に並べ替える
+func toPage[T any](items []T, limit int, direction domain.PageDirection) *domain.Page[T] {
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if direction == domain.PagePrev {
+		slices.Reverse(items)
+	}
+	return &domain.Page[T]{
+		Items:   items,
+		HasMore: hasMore,
+	}
+}
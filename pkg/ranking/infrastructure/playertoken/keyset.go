@@ -0,0 +1,167 @@
+package playertoken
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// JWKS形式の鍵セット (ファイルの内容)
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// JWK形式のEd25519の鍵 (dは署名に使う秘密鍵で、検証のみに使う鍵では省略できる)
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	X   string `json:"x"`
+	D   string `json:"d,omitempty"`
+}
+
+// 署名に使う鍵
+type signingKey struct {
+	kid        string
+	privateKey ed25519.PrivateKey
+}
+
+// トークンの署名と検証に使う鍵セット
+// 先頭の秘密鍵を持つ鍵で署名し、全ての鍵で検証する (鍵の入れ替えでは新しい鍵を先頭に追加し、古い鍵は発行済みのトークンが失効するまで残す)
+type Keyset struct {
+	signing    signingKey
+	publicKeys map[string]ed25519.PublicKey
+}
+
+// JWKS形式のファイルから鍵セットを読み込む
+func LoadKeyset(path string) (*Keyset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyset(data)
+}
+
+// JWKS形式の鍵セットを読み込む
+func ParseKeyset(data []byte) (*Keyset, error) {
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid keyset: %w", err)
+	}
+
+	keyset := &Keyset{publicKeys: map[string]ed25519.PublicKey{}}
+	for _, key := range set.Keys {
+		// Ed25519の鍵のみ扱う
+		if key.Kty != "OKP" || key.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported key %q: kty=%q crv=%q", key.Kid, key.Kty, key.Crv)
+		}
+		if key.Kid == "" {
+			return nil, errors.New("key without kid")
+		}
+		if _, ok := keyset.publicKeys[key.Kid]; ok {
+			return nil, fmt.Errorf("duplicate kid %q", key.Kid)
+		}
+
+		// 公開鍵
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid public key of %q", key.Kid)
+		}
+		keyset.publicKeys[key.Kid] = ed25519.PublicKey(x)
+
+		// 先頭の秘密鍵を持つ鍵を署名に使う
+		if key.D == "" || keyset.signing.privateKey != nil {
+			continue
+		}
+		d, err := base64.RawURLEncoding.DecodeString(key.D)
+		if err != nil || len(d) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid private key of %q", key.Kid)
+		}
+		privateKey := ed25519.NewKeyFromSeed(d)
+		if !privateKey.Public().(ed25519.PublicKey).Equal(keyset.publicKeys[key.Kid]) {
+			return nil, fmt.Errorf("private key of %q does not match its public key", key.Kid)
+		}
+		keyset.signing = signingKey{kid: key.Kid, privateKey: privateKey}
+	}
+
+	// 署名に使う鍵が必要
+	if keyset.signing.privateKey == nil {
+		return nil, errors.New("keyset has no private key to sign tokens")
+	}
+	return keyset, nil
+}
+
+// ランダムな鍵1つの鍵セットを生成する (鍵セットのファイルを使わない場合。再起動すると発行済みのトークンは使えなくなる)
+func NewEphemeralKeyset() (*Keyset, error) {
+	key, err := generateKey(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(jwks{Keys: []jwk{key}})
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyset(data)
+}
+
+// 鍵セットのファイルの先頭に新しい鍵を追加し、追加した鍵のkidを返す (ファイルがない場合は作成する)
+func RotateKeysetFile(path string, now time.Time) (string, error) {
+	// 既存の鍵
+	var set jwks
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return "", err
+	default:
+		if err := json.Unmarshal(data, &set); err != nil {
+			return "", fmt.Errorf("invalid keyset: %w", err)
+		}
+	}
+
+	// 新しい鍵を先頭に追加する
+	key, err := generateKey(now)
+	if err != nil {
+		return "", err
+	}
+	set.Keys = append([]jwk{key}, set.Keys...)
+
+	// 書き込む前に読み込めることを確認する
+	data, err = json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if _, err := ParseKeyset(data); err != nil {
+		return "", err
+	}
+
+	// 秘密鍵を含むため所有者のみ読み書きできるようにする
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return "", err
+	}
+	return key.Kid, nil
+}
+
+// ランダムなEd25519の鍵を生成する (kidは生成日時)
+func generateKey(now time.Time) (jwk, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return jwk{}, err
+	}
+	return jwk{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		Kid: now.UTC().Format("20060102T150405.000000000Z"),
+		Alg: algorithm,
+		Use: "sig",
+		X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		D:   base64.RawURLEncoding.EncodeToString(privateKey.Seed()),
+	}, nil
+}
@@ -0,0 +1,121 @@
+package playertoken
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// トークンの署名アルゴリズム
+const algorithm = "EdDSA"
+
+// トークンの発行者
+const issuer = "practice-go-game-ranking"
+
+// トークンのヘッダー
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// トークンのクレーム
+type claims struct {
+	Iss string `json:"iss"`
+	Sub string `json:"sub"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// プレイヤーのトークンの発行と検証 (Ed25519で署名したJWT)
+type Service struct {
+	keyset *Keyset
+	ttl    time.Duration
+}
+
+// トークンの発行と検証を生成する
+func NewService(keyset *Keyset, ttl time.Duration) *Service {
+	return &Service{
+		keyset: keyset,
+		ttl:    ttl,
+	}
+}
+
+// ユーザーを主体とするトークンを発行し、有効期限とともに返す
+func (s *Service) Issue(userID int, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(s.ttl).Truncate(time.Second)
+	headerJSON, err := json.Marshal(header{Alg: algorithm, Typ: "JWT", Kid: s.keyset.signing.kid})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	claimsJSON, err := json.Marshal(claims{Iss: issuer, Sub: strconv.Itoa(userID), Iat: now.Unix(), Exp: expiresAt.Unix()})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	// ヘッダーとクレームに署名する
+	signingInput := encodeSegment(headerJSON) + "." + encodeSegment(claimsJSON)
+	signature := ed25519.Sign(s.keyset.signing.privateKey, []byte(signingInput))
+	return signingInput + "." + encodeSegment(signature), expiresAt, nil
+}
+
+// トークンを検証し、主体のユーザーIDを返す (不正なトークンと有効期限切れのトークンはErrUnauthorized)
+func (s *Service) Verify(token string, now time.Time) (int, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, invalidTokenError()
+	}
+
+	// ヘッダーのアルゴリズムと鍵を確認する
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != algorithm {
+		return 0, invalidTokenError()
+	}
+	publicKey, ok := s.keyset.publicKeys[h.Kid]
+	if !ok {
+		return 0, invalidTokenError()
+	}
+
+	// 署名を検証する
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return 0, invalidTokenError()
+	}
+
+	// 発行者、有効期限と主体を確認する
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil || c.Iss != issuer {
+		return 0, invalidTokenError()
+	}
+	if now.Unix() >= c.Exp {
+		return 0, domain.NewUnauthorizedError("player_token_expired", "プレイヤーのトークンの有効期限が切れています。")
+	}
+	userID, err := strconv.Atoi(c.Sub)
+	if err != nil {
+		return 0, invalidTokenError()
+	}
+	return userID, nil
+}
+
+// JWTのセグメントにエンコードする
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// JWTのセグメントをデコードする
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// 不正なトークンのエラーを生成する
+func invalidTokenError() error {
+	return domain.NewUnauthorizedError("invalid_player_token", "プレイヤーのトークンが不正です。")
+}
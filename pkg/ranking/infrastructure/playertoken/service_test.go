@@ -0,0 +1,95 @@
+package playertoken
+
+import (
+	"path/filepath"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 認証されていないエラーのコードを確認する
+func assertErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var domainError *domain.Error
+	require.ErrorAs(t, err, &domainError)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.Equal(t, code, domainError.Code)
+}
+
+// 発行したトークンを検証でき、改ざん・有効期限切れ・不明な鍵のトークンは拒否される
+func TestServiceIssueAndVerify(t *testing.T) {
+	keyset, err := NewEphemeralKeyset()
+	require.NoError(t, err)
+	service := NewService(keyset, 15*time.Minute)
+	now := time.Now()
+
+	token, expiresAt, err := service.Issue(42, now)
+	require.NoError(t, err)
+	assert.WithinDuration(t, now.Add(15*time.Minute), expiresAt, time.Second)
+
+	// 主体のユーザーIDを返す
+	userID, err := service.Verify(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, 42, userID)
+
+	// 有効期限切れ
+	_, err = service.Verify(token, expiresAt)
+	assertErrorCode(t, err, "player_token_expired")
+
+	// クレームの改ざん
+	parts := strings.Split(token, ".")
+	forged := parts[0] + "." + encodeSegment([]byte(`{"iss":"practice-go-game-ranking","sub":"1","iat":0,"exp":9999999999}`)) + "." + parts[2]
+	_, err = service.Verify(forged, now)
+	assertErrorCode(t, err, "invalid_player_token")
+
+	// 形式が不正
+	_, err = service.Verify("not-a-token", now)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+
+	// 別の鍵セットで署名したトークン
+	other, err := NewEphemeralKeyset()
+	require.NoError(t, err)
+	otherToken, _, err := NewService(other, 15*time.Minute).Issue(42, now)
+	require.NoError(t, err)
+	_, err = service.Verify(otherToken, now)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+// 鍵を入れ替えると新しい鍵で署名し、古い鍵で発行したトークンも検証できる
+func TestRotateKeysetFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyset.json")
+	now := time.Now()
+
+	// ファイルがない場合は作成する
+	oldKid, err := RotateKeysetFile(path, now)
+	require.NoError(t, err)
+	oldKeyset, err := LoadKeyset(path)
+	require.NoError(t, err)
+	oldToken, _, err := NewService(oldKeyset, time.Hour).Issue(1, now)
+	require.NoError(t, err)
+
+	// 新しい鍵を追加する
+	newKid, err := RotateKeysetFile(path, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.NotEqual(t, oldKid, newKid)
+	newKeyset, err := LoadKeyset(path)
+	require.NoError(t, err)
+	assert.Equal(t, newKid, newKeyset.signing.kid)
+	assert.Len(t, newKeyset.publicKeys, 2)
+
+	// 古い鍵で発行したトークンを検証できる
+	service := NewService(newKeyset, time.Hour)
+	userID, err := service.Verify(oldToken, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, userID)
+
+	// 新しい鍵で発行したトークンは古い鍵セットでは検証できない
+	newToken, _, err := service.Issue(1, now)
+	require.NoError(t, err)
+	_, err = NewService(oldKeyset, time.Hour).Verify(newToken, now)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
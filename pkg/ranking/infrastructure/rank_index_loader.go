@@ -0,0 +1,46 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/rankindex"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ランク付けのインデックスにデータベースのユーザーハイスコアをすべて読み込む
+// 起動時にサーバがリクエストを受け付ける前に呼ぶ
+func WarmRankIndex(ctx context.Context, db bun.IDB, index *rankindex.Index) (int, error) {
+	// ユーザーハイスコアをランキングのシーズン、並び順、ユーザー名と共に1行ずつ読み込む (全件をメモリに展開しない。論理削除したユーザーは除外する)
+	rows, err := db.NewSelect().
+		TableExpr("user_high_scores AS uhs").
+		Join("JOIN rankings ON rankings.id = uhs.ranking_id").
+		Join("JOIN users ON users.id = uhs.user_id").
+		ColumnExpr("uhs.ranking_id, COALESCE(rankings.season, 0), rankings.sort_order, uhs.user_id, users.name, uhs.high_score, uhs.timestamp").
+		Where("users.deleted_at IS NULL").
+		Rows(ctx)
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var highScore domain.UserHighScore
+		var season int
+		var sortOrder domain.SortOrder
+		var userName string
+		var timestamp time.Time
+		if err := rows.Scan(&highScore.RankingID, &season, &sortOrder, &highScore.UserID, &userName, &highScore.Score, &timestamp); err != nil {
+			log.Printf("Error occurred: %v", err)
+			return count, err
+		}
+		highScore.Timestamp = timestamp.UTC()
+		index.Apply(highScore, season, sortOrder, userName)
+		count++
+	}
+	return count, rows.Err()
+}
@@ -0,0 +1,58 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/rankindex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// データベースのユーザーハイスコアをインデックスに読み込める
+func TestWarmRankIndex(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// テスト用のゲームとランキングを登録
+	suffix := time.Now().UnixNano()
+	gameName, _ := domain.NewGameName(fmt.Sprintf("warm-%d", suffix))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName(fmt.Sprintf("warm-%d", suffix))
+	ranking, err := NewRankingRepository(db).Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}})
+	require.NoError(t, err)
+
+	// スコアを登録
+	userIDs := make([]int, 0, 3)
+	for i, score := range []domain.Score{20, 30, 10} {
+		userName, _ := domain.NewUserName(fmt.Sprintf("warm-%d-%d", suffix, i))
+		user, err := NewUserRepository(db).Create(ctx, userName)
+		require.NoError(t, err)
+		_, err = NewUserHighScoreRepository(db).Upsert(ctx, ranking.ID, user.ID, score, domain.SortOrderDesc)
+		require.NoError(t, err)
+		userIDs = append(userIDs, user.ID)
+	}
+
+	index := rankindex.NewIndex()
+	count, err := WarmRankIndex(ctx, db, index)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, 3)
+
+	// データベースと同じ順にランク付けされること
+	index.Read(ranking.ID, func(list *rankindex.SkipList) {
+		assert.Equal(t, 3, list.Len())
+		for i, expected := range []int{2, 1, 3} {
+			rank, ok := list.Rank(userIDs[i])
+			require.True(t, ok)
+			assert.Equal(t, expected, rank)
+		}
+		entry, ok := list.Get(userIDs[1])
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("warm-%d-1", suffix), entry.UserName)
+		assert.Equal(t, domain.Score(30), entry.Score)
+	})
+}
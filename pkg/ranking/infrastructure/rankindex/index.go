@@ -0,0 +1,165 @@
+package rankindex
+
+import (
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"sync"
+)
+
+// ランキングごとのランク付けのインデックス
+// プロセス内に保持するため、複数のプロセスで同じデータベースを使う場合は他のプロセスの更新が反映されない
+type Index struct {
+	mu    sync.RWMutex
+	lists map[int]*rankingList
+
+	// 論理削除したユーザー (復元するまでハイスコアを反映しない)
+	removedUsers map[int]struct{}
+}
+
+// ランキングのスキップリスト
+type rankingList struct {
+	mu sync.RWMutex
+
+	// スキップリストに反映しているシーズンの番号 (周期なしの場合は0)
+	season int
+	list   *SkipList
+}
+
+// 空のインデックスを生成する
+func NewIndex() *Index {
+	return &Index{
+		lists:        map[int]*rankingList{},
+		removedUsers: map[int]struct{}{},
+	}
+}
+
+// ハイスコアをランキングの並び順で反映する
+// ハイスコアは上位になる一方のため、反映済みの要素より上位の場合のみ置き換える (更新の反映順が前後しても古いハイスコアに戻らない)
+// ランキングの並び順は作成後に変わらないため、最初に反映した時の並び順でスキップリストを作成する
+// シーズンを進めた後に届いた前のシーズンのハイスコアは反映せず、次のシーズンのハイスコアが先に届いた場合はそこでシーズンを進める
+// 論理削除したユーザーのハイスコアは、削除前に保存したハイスコアの反映が削除より後に届いても反映しない
+func (index *Index) Apply(highScore domain.UserHighScore, season int, sortOrder domain.SortOrder, userName string) {
+	entry := Entry{
+		UserID:    highScore.UserID,
+		UserName:  userName,
+		Score:     highScore.Score,
+		Timestamp: highScore.Timestamp,
+	}
+
+	l := index.listFor(highScore.RankingID, sortOrder)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if season < l.season || index.isRemoved(entry.UserID) {
+		return
+	}
+	l.startSeason(season)
+	if current, ok := l.list.Get(entry.UserID); ok && !entry.RanksAbove(current, l.list.SortOrder()) {
+		return
+	}
+	l.list.Upsert(entry)
+}
+
+// ランキングを次のシーズンの空のスキップリストにする (既に同じシーズンのハイスコアを反映済みの場合は何もしない)
+func (index *Index) StartSeason(rankingID int, season int, sortOrder domain.SortOrder) {
+	l := index.listFor(rankingID, sortOrder)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.startSeason(season)
+}
+
+// ランキングのスキップリストを取り除く (次に反映した時に空のスキップリストから作り直す)
+func (index *Index) Reset(rankingID int) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	delete(index.lists, rankingID)
+}
+
+// ランキングからユーザーのハイスコアを取り除く (ランキングが未登録の場合は何もしない)
+func (index *Index) Remove(rankingID int, userID int) {
+	l := index.listFor(rankingID, "")
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.list.Remove(userID)
+}
+
+// 全てのランキングからユーザーのハイスコアを取り除き、復元するまで反映しない
+// 取り除く前に削除済みとして記録するため、各ランキングでは取り除く前に反映したハイスコアは取り除かれ、後に届いたハイスコアは反映されない
+func (index *Index) RemoveUser(userID int) {
+	index.mu.Lock()
+	index.removedUsers[userID] = struct{}{}
+	lists := make([]*rankingList, 0, len(index.lists))
+	for _, l := range index.lists {
+		lists = append(lists, l)
+	}
+	index.mu.Unlock()
+
+	for _, l := range lists {
+		l.mu.Lock()
+		l.list.Remove(userID)
+		l.mu.Unlock()
+	}
+}
+
+// 復元したユーザーのハイスコアを再び反映する (ハイスコアは呼び出し側で反映し直す)
+func (index *Index) RestoreUser(userID int) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	delete(index.removedUsers, userID)
+}
+
+// ユーザーを論理削除したかを判定する
+func (index *Index) isRemoved(userID int) bool {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	_, ok := index.removedUsers[userID]
+	return ok
+}
+
+// ランキングのスキップリストを読み取り用にロックして関数を実行する (ランキングが未登録の場合は空のリスト)
+func (index *Index) Read(rankingID int, fn func(list *SkipList)) {
+	l := index.listFor(rankingID, "")
+	if l == nil {
+		fn(NewSkipList(domain.SortOrderDesc))
+		return
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	fn(l.list)
+}
+
+// ランキングのスキップリストを返す (並び順を指定した場合は未登録なら作成する)
+func (index *Index) listFor(rankingID int, sortOrder domain.SortOrder) *rankingList {
+	index.mu.RLock()
+	l, ok := index.lists[rankingID]
+	index.mu.RUnlock()
+	if ok || sortOrder == "" {
+		return l
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if l, ok := index.lists[rankingID]; ok {
+		return l
+	}
+	l = &rankingList{list: NewSkipList(sortOrder)}
+	index.lists[rankingID] = l
+	return l
+}
+
+// 反映しているシーズンより後のシーズンの場合は空のスキップリストから始める (呼び出し側でロックする)
+func (l *rankingList) startSeason(season int) {
+	if season <= l.season {
+		return
+	}
+	l.season = season
+	l.list = NewSkipList(l.list.SortOrder())
+}
@@ -0,0 +1,272 @@
+package rankindex
+
+import (
+	"math/rand/v2"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+const (
+	// スキップリストの最大の段数 (4^16 ≒ 43億件まで段数が足りる)
+	maxLevel = 16
+
+	// 1段上に上がる確率
+	levelProbability = 0.25
+)
+
+// インデックスの要素
+// ランキングの並び順でスコアが上位の順、同点の場合は登録日時が古い順、それも同じ場合はユーザーIDが小さい順に並ぶ
+type Entry struct {
+	UserID    int
+	UserName  string
+	Score     domain.Score
+	Timestamp time.Time
+}
+
+// ランキングの並び順で要素がもう一方の要素より上位かを判定する
+func (e Entry) RanksAbove(other Entry, sortOrder domain.SortOrder) bool {
+	if e.Score != other.Score {
+		return sortOrder.Beats(e.Score, other.Score)
+	}
+	if !e.Timestamp.Equal(other.Timestamp) {
+		return e.Timestamp.Before(other.Timestamp)
+	}
+	return e.UserID < other.UserID
+}
+
+// スキップリストのノード
+type node struct {
+	entry Entry
+
+	// 1つ上位のノード (先頭の場合はnil)
+	prev *node
+
+	// 段ごとの次のノード
+	levels []level
+}
+
+// スキップリストの段
+type level struct {
+	next *node
+
+	// 次のノードまでに飛ばす要素数 (ランクの計算に使う)
+	span int
+}
+
+// 順位統計付きのスキップリスト
+// 挿入、削除、ランクの取得、ランクを指定した取得をO(log n)で行う
+// 並行して使う場合は呼び出し元で排他する
+type SkipList struct {
+	head   *node
+	level  int
+	length int
+
+	// ランキングの並び順
+	sortOrder domain.SortOrder
+
+	// ユーザーIDから要素のノードを引く
+	nodes map[int]*node
+}
+
+// ランキングの並び順で並べる空のスキップリストを生成する
+func NewSkipList(sortOrder domain.SortOrder) *SkipList {
+	return &SkipList{
+		head:      &node{levels: make([]level, maxLevel)},
+		level:     1,
+		sortOrder: sortOrder,
+		nodes:     map[int]*node{},
+	}
+}
+
+// ランキングの並び順を返す
+func (s *SkipList) SortOrder() domain.SortOrder {
+	return s.sortOrder
+}
+
+// 要素数を返す
+func (s *SkipList) Len() int {
+	return s.length
+}
+
+// ユーザーの要素を返す
+func (s *SkipList) Get(userID int) (Entry, bool) {
+	n, ok := s.nodes[userID]
+	if !ok {
+		return Entry{}, false
+	}
+	return n.entry, true
+}
+
+// 要素を登録する (同じユーザーの要素があれば置き換える)
+func (s *SkipList) Upsert(entry Entry) {
+	s.Remove(entry.UserID)
+
+	// 段ごとに挿入位置の直前のノードと、そこまでの要素数を求める
+	var update [maxLevel]*node
+	var rank [maxLevel]int
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].next != nil && x.levels[i].next.entry.RanksAbove(entry, s.sortOrder) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+
+	// 新しいノードの段数を決め、段が増えた場合は先頭から繋ぐ
+	newLevel := randomLevel()
+	if newLevel > s.level {
+		for i := s.level; i < newLevel; i++ {
+			rank[i] = 0
+			update[i] = s.head
+			update[i].levels[i].span = s.length
+		}
+		s.level = newLevel
+	}
+
+	// 段ごとにノードを繋ぎ、飛ばす要素数を更新する
+	n := &node{entry: entry, levels: make([]level, newLevel)}
+	for i := 0; i < newLevel; i++ {
+		n.levels[i].next = update[i].levels[i].next
+		update[i].levels[i].next = n
+		n.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	for i := newLevel; i < s.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	// 1つ上位のノードを繋ぐ
+	if update[0] != s.head {
+		n.prev = update[0]
+	}
+	if n.levels[0].next != nil {
+		n.levels[0].next.prev = n
+	}
+
+	s.length++
+	s.nodes[entry.UserID] = n
+}
+
+// ユーザーの要素を削除する (存在しない場合は何もしない)
+func (s *SkipList) Remove(userID int) {
+	target, ok := s.nodes[userID]
+	if !ok {
+		return
+	}
+
+	// 段ごとに削除するノードの直前のノードを求める
+	var update [maxLevel]*node
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && x.levels[i].next != target && x.levels[i].next.entry.RanksAbove(target.entry, s.sortOrder) {
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+
+	// 段ごとにノードを外し、飛ばす要素数を更新する
+	for i := 0; i < s.level; i++ {
+		if update[i].levels[i].next == target {
+			update[i].levels[i].span += target.levels[i].span - 1
+			update[i].levels[i].next = target.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	if target.levels[0].next != nil {
+		target.levels[0].next.prev = target.prev
+	}
+
+	// 使われなくなった上の段を減らす
+	for s.level > 1 && s.head.levels[s.level-1].next == nil {
+		s.level--
+	}
+
+	s.length--
+	delete(s.nodes, userID)
+}
+
+// ユーザーのランク (1始まり) を返す
+func (s *SkipList) Rank(userID int) (int, bool) {
+	target, ok := s.nodes[userID]
+	if !ok {
+		return 0, false
+	}
+	return s.CountAbove(target.entry) + 1, true
+}
+
+// 基準の要素より上位の要素数を返す (基準の要素が登録されていなくてもよい)
+func (s *SkipList) CountAbove(entry Entry) int {
+	count := 0
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && x.levels[i].next.entry.RanksAbove(entry, s.sortOrder) {
+			count += x.levels[i].span
+			x = x.levels[i].next
+		}
+	}
+	return count
+}
+
+// 基準の要素と同じか上位の要素数を返す (基準の要素が登録されていなくてもよい)
+func (s *SkipList) CountNotBelow(entry Entry) int {
+	count := 0
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && !entry.RanksAbove(x.levels[i].next.entry, s.sortOrder) {
+			count += x.levels[i].span
+			x = x.levels[i].next
+		}
+	}
+	return count
+}
+
+// 指定したランクから上位順に最大limit件の要素を返す (ランクは1始まり)
+func (s *SkipList) RangeDown(rank int, limit int) []Entry {
+	entries := make([]Entry, 0, max(min(limit, s.length-rank+1), 0))
+	for n := s.nodeAt(rank); n != nil && len(entries) < limit; n = n.levels[0].next {
+		entries = append(entries, n.entry)
+	}
+	return entries
+}
+
+// 指定したランクから下位順 (ランクの降順) に最大limit件の要素を返す (ランクは1始まり)
+func (s *SkipList) RangeUp(rank int, limit int) []Entry {
+	entries := make([]Entry, 0, max(min(limit, rank), 0))
+	for n := s.nodeAt(rank); n != nil && len(entries) < limit; n = n.prev {
+		entries = append(entries, n.entry)
+	}
+	return entries
+}
+
+// 指定したランクのノードを返す (範囲外の場合はnil)
+func (s *SkipList) nodeAt(rank int) *node {
+	if rank < 1 || rank > s.length {
+		return nil
+	}
+	traversed := 0
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && traversed+x.levels[i].span <= rank {
+			traversed += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if traversed == rank {
+			return x
+		}
+	}
+	return nil
+}
+
+// ノードの段数を確率的に決める
+func randomLevel() int {
+	level := 1
+	for level < maxLevel && rand.Float64() < levelProbability {
+		level++
+	}
+	return level
+}
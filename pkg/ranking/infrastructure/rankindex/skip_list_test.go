@@ -0,0 +1,162 @@
+package rankindex
+
+import (
+	"math/rand/v2"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 比較用に要素を全件並べ替えてランク付けする
+func sortedEntries(entries map[int]Entry, sortOrder domain.SortOrder) []Entry {
+	sorted := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		sorted = append(sorted, entry)
+	}
+	slices.SortFunc(sorted, func(a, b Entry) int {
+		if a.RanksAbove(b, sortOrder) {
+			return -1
+		}
+		if b.RanksAbove(a, sortOrder) {
+			return 1
+		}
+		return 0
+	})
+	return sorted
+}
+
+// ランダムな登録・削除を繰り返しても全件を並べ替えた結果と一致する
+func TestSkipListMatchesSortedEntries(t *testing.T) {
+	for _, sortOrder := range []domain.SortOrder{domain.SortOrderDesc, domain.SortOrderAsc} {
+		t.Run(string(sortOrder), func(t *testing.T) {
+			testSkipListMatchesSortedEntries(t, sortOrder)
+		})
+	}
+}
+
+// 並び順を指定してランダムな登録・削除を繰り返す
+func testSkipListMatchesSortedEntries(t *testing.T, sortOrder domain.SortOrder) {
+	list := NewSkipList(sortOrder)
+	expected := map[int]Entry{}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	random := rand.New(rand.NewPCG(1, 2))
+
+	for i := 0; i < 3000; i++ {
+		userID := random.IntN(300) + 1
+		if random.IntN(5) == 0 {
+			list.Remove(userID)
+			delete(expected, userID)
+		} else {
+			// 同点や同時刻が発生するよう狭い範囲で値を決める
+			entry := Entry{
+				UserID:    userID,
+				Score:     domain.Score(random.IntN(50)),
+				Timestamp: base.Add(time.Duration(random.IntN(10)) * time.Second),
+			}
+			list.Upsert(entry)
+			expected[userID] = entry
+		}
+
+		if i%100 != 0 {
+			continue
+		}
+		sorted := sortedEntries(expected, sortOrder)
+		require.Equal(t, len(sorted), list.Len())
+		for j, entry := range sorted {
+			rank, ok := list.Rank(entry.UserID)
+			require.True(t, ok)
+			require.Equal(t, j+1, rank, "Expected rank of user %d", entry.UserID)
+		}
+		assert.Equal(t, sorted, list.RangeDown(1, len(sorted)))
+	}
+}
+
+// ランクを指定して上位順・下位順に取得できる
+func TestSkipListRange(t *testing.T) {
+	list := NewSkipList(domain.SortOrderDesc)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, score := range []domain.Score{10, 50, 30, 50, 20} {
+		list.Upsert(Entry{UserID: i + 1, Score: score, Timestamp: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	userIDs := func(entries []Entry) []int {
+		ids := make([]int, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.UserID)
+		}
+		return ids
+	}
+	assert.Equal(t, []int{2, 4, 3}, userIDs(list.RangeDown(1, 3)))
+	assert.Equal(t, []int{5, 1}, userIDs(list.RangeDown(4, 10)))
+	assert.Equal(t, []int{3, 4, 2}, userIDs(list.RangeUp(3, 10)))
+	assert.Empty(t, list.RangeDown(6, 10))
+	assert.Empty(t, list.RangeUp(0, 10))
+
+	// 登録されていない要素の前後の件数を数えられる
+	probe := Entry{UserID: 99, Score: 30, Timestamp: base}
+	assert.Equal(t, 2, list.CountAbove(probe))
+	assert.Equal(t, 3, list.CountNotBelow(Entry{UserID: 3, Score: 30, Timestamp: base.Add(2 * time.Second)}))
+
+	// 削除すると後続のランクが繰り上がる
+	list.Remove(2)
+	rank, ok := list.Rank(1)
+	require.True(t, ok)
+	assert.Equal(t, 4, rank)
+	_, ok = list.Rank(2)
+	assert.False(t, ok)
+}
+
+// ベンチマーク用の件数
+const benchmarkEntries = 1_000_000
+
+// ベンチマーク用に100万件のスキップリストを作成する
+func newBenchmarkSkipList(b *testing.B) *SkipList {
+	b.Helper()
+	list := NewSkipList(domain.SortOrderDesc)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	random := rand.New(rand.NewPCG(1, 2))
+	for userID := 1; userID <= benchmarkEntries; userID++ {
+		list.Upsert(Entry{
+			UserID:    userID,
+			Score:     domain.Score(random.IntN(1_000_000)),
+			Timestamp: base.Add(time.Duration(userID) * time.Millisecond),
+		})
+	}
+	return list
+}
+
+// 100万件からユーザーのランクを取得する
+func BenchmarkSkipListRank(b *testing.B) {
+	list := newBenchmarkSkipList(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.Rank(i%benchmarkEntries + 1)
+	}
+}
+
+// 100万件に対してハイスコアを更新する
+func BenchmarkSkipListUpsert(b *testing.B) {
+	list := newBenchmarkSkipList(b)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.Upsert(Entry{
+			UserID:    i%benchmarkEntries + 1,
+			Score:     domain.Score(1_000_000 + i),
+			Timestamp: base,
+		})
+	}
+}
+
+// 100万件の中ほどから1ページ分を取得する
+func BenchmarkSkipListRangeDown(b *testing.B) {
+	list := newBenchmarkSkipList(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.RangeDown(benchmarkEntries/2, 100)
+	}
+}
@@ -0,0 +1,189 @@
+package rankindex
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"slices"
+)
+
+// インデックスを使うユーザーランキングクエリサービス
+// ランクをデータベースで数えずにインデックスからO(log n)で求める
+type UserRankingQueryService struct {
+	index             *Index
+	rankingRepository domain.RankingRepositoryInterface
+}
+
+// クエリサービスを生成する
+func NewUserRankingQueryService(index *Index, rankingRepository domain.RankingRepositoryInterface) *UserRankingQueryService {
+	return &UserRankingQueryService{
+		index:             index,
+		rankingRepository: rankingRepository,
+	}
+}
+
+// ユーザーランキングを取得する
+func (s *UserRankingQueryService) FetchUserRanking(ctx context.Context, query usecase.UserRankingQuery) (*usecase.UserRankingDto, error) {
+	// ランキングを取得
+	ranking, err := s.rankingRepository.FindByID(ctx, query.RankingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 上位から下位に向かって取得する場合はカーソルより下位を、逆の場合は上位をカーソルに近い順に取得する
+	cursor := query.Cursor
+	var userRanks []usecase.UserRankDto
+	var hasMore bool
+	s.index.Read(query.RankingID, func(list *SkipList) {
+		if query.FetchesDownward() {
+			// カーソルの直後のランクから取得する
+			start := 1
+			if cursor != nil {
+				start = list.CountNotBelow(cursorEntry(cursor)) + 1
+			}
+			userRanks = toUserRankDtos(list.RangeDown(start, query.Limit), start, 1, ranking.ScoreType)
+			hasMore = start+query.Limit <= list.Len()
+		} else {
+			// カーソルの直前のランクから取得する
+			end := list.Len()
+			if cursor != nil {
+				end = list.CountAbove(cursorEntry(cursor))
+			}
+			userRanks = toUserRankDtos(list.RangeUp(end, query.Limit), end, -1, ranking.ScoreType)
+			hasMore = end > query.Limit
+		}
+	})
+
+	// 前のページを取得した場合は逆順で取得しているため並べ替える
+	direction := domain.PageNext
+	if cursor != nil {
+		direction = cursor.Direction
+	}
+	if direction == domain.PagePrev {
+		slices.Reverse(userRanks)
+	}
+
+	// ユースケース層のユーザーランキング構造体にマッピング
+	userRanking := &usecase.UserRankingDto{
+		RankingID:   ranking.ID,
+		RankingName: ranking.Name.Value,
+		UserRanks:   userRanks,
+	}
+	userRanking.HasNext, userRanking.HasPrev = usecase.PageLinks(hasMore, cursor != nil, direction)
+	return userRanking, nil
+}
+
+// ユーザーの前後のユーザーランキングを取得する
+func (s *UserRankingQueryService) FetchUserRankingAround(ctx context.Context, query usecase.UserRankingAroundQuery) (*usecase.UserRankingAroundDto, error) {
+	// ランキングを取得
+	ranking, err := s.rankingRepository.FindByID(ctx, query.RankingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 上位、自分、下位の順にランクの昇順で並べる (ランキングの端では存在する分だけ)
+	var around *usecase.UserRankingAroundDto
+	s.index.Read(query.RankingID, func(list *SkipList) {
+		rank, ok := list.Rank(query.UserID)
+		if !ok {
+			return
+		}
+		start := max(rank-query.Before, 1)
+		around = &usecase.UserRankingAroundDto{
+			RankingID:   ranking.ID,
+			RankingName: ranking.Name.Value,
+			UserID:      query.UserID,
+			Rank:        rank,
+			TotalUsers:  list.Len(),
+			UserRanks:   toUserRankDtos(list.RangeDown(start, rank-start+1+query.After), start, 1, ranking.ScoreType),
+		}
+	})
+
+	// ハイスコアが未登録の場合はNotFoundエラーを返す
+	if around == nil {
+		return nil, userHighScoreNotFoundError(query.RankingID, query.UserID)
+	}
+	return around, nil
+}
+
+// ランキングにおけるユーザーの現在のランクを取得する
+func (s *UserRankingQueryService) FetchUserRank(ctx context.Context, rankingID int, userID int) (*usecase.UserRankDto, error) {
+	// ユーザーの順位
+	userStanding, err := s.FetchUserStanding(ctx, rankingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usecase.UserRankDto{
+		UserID:     userStanding.UserID,
+		UserName:   userStanding.UserName,
+		Rank:       userStanding.Rank,
+		Score:      userStanding.Score,
+		AchievedAt: userStanding.AchievedAt,
+	}, nil
+}
+
+// ランキングにおけるユーザーのランクと参加人数、パーセンタイルを取得する
+func (s *UserRankingQueryService) FetchUserStanding(ctx context.Context, rankingID int, userID int) (*usecase.UserStandingDto, error) {
+	// ランキングを取得 (スコアの型を表示に使う)
+	ranking, err := s.rankingRepository.FindByID(ctx, rankingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var standing *usecase.UserStandingDto
+	s.index.Read(rankingID, func(list *SkipList) {
+		entry, ok := list.Get(userID)
+		if !ok {
+			return
+		}
+		rank, _ := list.Rank(userID)
+		standing = &usecase.UserStandingDto{
+			RankingID:  rankingID,
+			UserID:     userID,
+			UserName:   entry.UserName,
+			Rank:       rank,
+			Score:      usecase.NewScoreDto(entry.Score, ranking.ScoreType),
+			AchievedAt: entry.Timestamp,
+			TotalUsers: list.Len(),
+			Percentile: usecase.CalculatePercentile(rank, list.Len()),
+		}
+	})
+
+	// ハイスコアが未登録の場合はNotFoundエラーを返す
+	if standing == nil {
+		return nil, userHighScoreNotFoundError(rankingID, userID)
+	}
+	return standing, nil
+}
+
+// カーソルの位置を表す要素を生成する
+func cursorEntry(cursor *usecase.UserRankingCursor) Entry {
+	return Entry{
+		UserID:    cursor.UserID,
+		Score:     cursor.Score,
+		Timestamp: cursor.Timestamp,
+	}
+}
+
+// 要素をユースケース層のユーザーランクにマッピングする (先頭のランクから1件ごとにstepずつランクを進める)
+func toUserRankDtos(entries []Entry, rank int, step int, scoreType domain.ScoreType) []usecase.UserRankDto {
+	userRanks := make([]usecase.UserRankDto, 0, len(entries))
+	for _, entry := range entries {
+		userRanks = append(userRanks, usecase.UserRankDto{
+			UserID:     entry.UserID,
+			UserName:   entry.UserName,
+			Rank:       rank,
+			Score:      usecase.NewScoreDto(entry.Score, scoreType),
+			AchievedAt: entry.Timestamp,
+		})
+		rank += step
+	}
+	return userRanks
+}
+
+// ハイスコアが未登録のエラーを生成する
+func userHighScoreNotFoundError(rankingID int, userID int) error {
+	return domain.NewNotFoundError("user_high_score_not_found", fmt.Sprintf("ハイスコアが登録されていません。ランキングID: %d, ユーザーID: %d", rankingID, userID))
+}
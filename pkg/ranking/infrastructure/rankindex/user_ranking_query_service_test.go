@@ -0,0 +1,171 @@
+package rankindex
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/memory"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコアの一覧でランキングを作成し、インデックスに反映する (ユーザーIDは1から順に振られる)
+func newIndexedRanking(t *testing.T, scores ...domain.Score) (*UserRankingQueryService, int) {
+	store := memory.NewStore()
+	ctx := context.Background()
+	gameName, _ := domain.NewGameName("game")
+	game, err := memory.NewGameRepository(store).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName("ranking")
+	ranking, err := memory.NewRankingRepository(store).Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}})
+	require.NoError(t, err)
+
+	index := NewIndex()
+	for i, score := range scores {
+		userName, _ := domain.NewUserName(fmt.Sprintf("user-%d", i+1))
+		user, err := memory.NewUserRepository(store).Create(ctx, userName)
+		require.NoError(t, err)
+		result, err := memory.NewUserHighScoreRepository(store).Upsert(ctx, ranking.ID, user.ID, score, domain.SortOrderDesc)
+		require.NoError(t, err)
+		index.Apply(result.HighScore, ranking.SeasonNumber(), ranking.SortOrder, user.Name.Value)
+	}
+	return NewUserRankingQueryService(index, memory.NewRankingRepository(store)), ranking.ID
+}
+
+// ランクを取り出す
+func ranksOf(userRanks []usecase.UserRankDto) []int {
+	ranks := make([]int, 0, len(userRanks))
+	for _, userRank := range userRanks {
+		ranks = append(ranks, userRank.Rank)
+	}
+	return ranks
+}
+
+// カーソルで前後のページを行き来できる
+func TestFetchUserRankingPaging(t *testing.T) {
+	queryService, rankingID := newIndexedRanking(t, 10, 50, 30, 50, 20)
+	ctx := context.Background()
+
+	// 1ページ目 (同点は先に登録したユーザーが上位)
+	query, err := usecase.NewUserRankingQuery(rankingID, "rank_asc", 2, nil)
+	require.NoError(t, err)
+	first, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ranksOf(first.UserRanks))
+	assert.Equal(t, 2, first.UserRanks[0].UserID)
+	assert.Equal(t, "user-2", first.UserRanks[0].UserName)
+	assert.True(t, first.HasNext)
+	assert.False(t, first.HasPrev)
+
+	// 2ページ目
+	last := first.UserRanks[1]
+	query.Cursor = &usecase.UserRankingCursor{Score: last.Score.Value, Timestamp: last.AchievedAt, UserID: last.UserID, Direction: domain.PageNext}
+	second, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, ranksOf(second.UserRanks))
+	assert.True(t, second.HasNext)
+	assert.True(t, second.HasPrev)
+
+	// 2ページ目から前のページに戻る
+	head := second.UserRanks[0]
+	query.Cursor = &usecase.UserRankingCursor{Score: head.Score.Value, Timestamp: head.AchievedAt, UserID: head.UserID, Direction: domain.PagePrev}
+	back, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ranksOf(back.UserRanks))
+	assert.True(t, back.HasNext)
+	assert.False(t, back.HasPrev)
+
+	// 下位から並べる
+	query, err = usecase.NewUserRankingQuery(rankingID, "rank_desc", 2, nil)
+	require.NoError(t, err)
+	desc, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 4}, ranksOf(desc.UserRanks))
+	assert.True(t, desc.HasNext)
+}
+
+// 前後のユーザーランクと順位を取得できる
+func TestFetchUserRankingAround(t *testing.T) {
+	queryService, rankingID := newIndexedRanking(t, 50, 40, 30, 20, 10)
+	ctx := context.Background()
+
+	query, err := usecase.NewUserRankingAroundQuery(rankingID, 2, nil, nil)
+	require.NoError(t, err)
+	around, err := queryService.FetchUserRankingAround(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 2, around.Rank)
+	assert.Equal(t, 5, around.TotalUsers)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ranksOf(around.UserRanks))
+
+	standing, err := queryService.FetchUserStanding(ctx, rankingID, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, standing.Rank)
+	assert.Equal(t, 5, standing.TotalUsers)
+
+	// ハイスコアが未登録の場合はNotFound
+	query, err = usecase.NewUserRankingAroundQuery(rankingID, 6, nil, nil)
+	require.NoError(t, err)
+	_, err = queryService.FetchUserRankingAround(ctx, query)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = queryService.FetchUserRank(ctx, rankingID, 6)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// 取り除いたハイスコアはランク付けから除外される
+func TestIndexRemove(t *testing.T) {
+	queryService, rankingID := newIndexedRanking(t, 10, 30, 20)
+	ctx := context.Background()
+
+	queryService.index.Remove(rankingID, 2)
+	standing, err := queryService.FetchUserStanding(ctx, rankingID, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 1, standing.Rank)
+	assert.Equal(t, 2, standing.TotalUsers)
+
+	queryService.index.RemoveUser(3)
+	_, err = queryService.FetchUserStanding(ctx, rankingID, 3)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	rank, err := queryService.FetchUserRank(ctx, rankingID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rank.Rank)
+
+	// 論理削除より後に届いた削除前のハイスコアの反映は無視し、復元した後は反映する
+	highScore := domain.UserHighScore{RankingID: rankingID, UserID: 3, Score: 50, Timestamp: time.Now()}
+	queryService.index.Apply(highScore, 0, domain.SortOrderDesc, "user-3")
+	_, err = queryService.FetchUserStanding(ctx, rankingID, 3)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	queryService.index.RestoreUser(3)
+	queryService.index.Apply(highScore, 0, domain.SortOrderDesc, "user-3")
+	standing, err = queryService.FetchUserStanding(ctx, rankingID, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 1, standing.Rank)
+}
+
+// シーズンを進めた後に届いた前のシーズンのハイスコアは反映せず、次のシーズンのハイスコアが先に届いた場合もシーズンを進める
+func TestIndexSeason(t *testing.T) {
+	queryService, rankingID := newIndexedRanking(t, 10, 30, 20)
+	ctx := context.Background()
+	index := queryService.index
+	now := time.Now().UTC()
+
+	// 次のシーズンのハイスコアがシーズンを進めるより先に届く
+	index.Apply(domain.UserHighScore{RankingID: rankingID, UserID: 1, Score: 5, Timestamp: now}, 1, domain.SortOrderDesc, "user-1")
+	standing, err := queryService.FetchUserStanding(ctx, rankingID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, standing.TotalUsers)
+
+	// 後からシーズンを進めても反映済みのハイスコアは残る
+	index.StartSeason(rankingID, 1, domain.SortOrderDesc)
+	standing, err = queryService.FetchUserStanding(ctx, rankingID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, standing.TotalUsers)
+
+	// シーズンを進めた後に届いた前のシーズンのハイスコアは反映しない
+	index.Apply(domain.UserHighScore{RankingID: rankingID, UserID: 2, Score: 40, Timestamp: now}, 0, domain.SortOrderDesc, "user-2")
+	_, err = queryService.FetchUserStanding(ctx, rankingID, 2)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
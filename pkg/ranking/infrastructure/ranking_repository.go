@@ -2,28 +2,53 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"practice-go-game-ranking/pkg/ranking/domain"
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
 )
 
 // ランキング
 type Ranking struct {
-	ID        int       `bun:"id,pk,autoincrement"`
-	Name      string    `bun:"name"`
+	ID         int    `bun:"id,pk,autoincrement"`
+	GameID     int    `bun:"game_id"`
+	Name       string `bun:"name"`
+	SortOrder  string `bun:"sort_order"`
+	ScoreType  string `bun:"score_type"`
+	ScoreScale int    `bun:"score_scale"`
+	MinScore   *int64 `bun:"min_score"`
+	MaxScore   *int64 `bun:"max_score"`
+	ScoreStep  *int64 `bun:"score_step"`
+
+	// スコアの妥当性のルール (NULLの場合はルールなし)
+	MaxImprovementRatio     *float64 `bun:"max_improvement_ratio"`
+	MinSubmissionIntervalMs *int64   `bun:"min_submission_interval_ms"`
+	ScoreCap                *int64   `bun:"score_cap"`
+	MaxZScore               *float64 `bun:"max_z_score"`
+
+	// シーズン (周期なしの場合はすべてNULL)
+	Recurrence      *string    `bun:"recurrence"`
+	Timezone        *string    `bun:"timezone"`
+	Season          *int       `bun:"season"`
+	SeasonStartedAt *time.Time `bun:"season_started_at"`
+	SeasonEndsAt    *time.Time `bun:"season_ends_at"`
+
 	CreatedAt time.Time `bun:"created_at,nullzero,default:CURRENT_TIMESTAMP"`
 	UpdatedAt time.Time `bun:"updated_at,nullzero,default:CURRENT_TIMESTAMP"`
 }
 
 // ランキングリポジトリ
 type RankingRepository struct {
-	db *bun.DB
+	db bun.IDB
 }
 
 // リポジトリを生成する
-func NewRankingRepository(bun *bun.DB) *RankingRepository {
+func NewRankingRepository(bun bun.IDB) *RankingRepository {
 	return &RankingRepository{
 		db: bun,
 	}
@@ -35,7 +60,12 @@ func (r *RankingRepository) FindByID(ctx context.Context, id int) (*domain.Ranki
 	ranking := new(Ranking)
 
 	// クエリ実行
-	err := r.db.NewSelect().Model(&ranking).Where("id = ?", id).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(ranking).Where("id = ?", id).Scan(ctx)
+
+	// 存在しない場合はNotFoundエラーを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewNotFoundError("ranking_not_found", fmt.Sprintf("ランキングが存在しません。ランキングID: %d", id))
+	}
 
 	// エラーハンドリング
 	if err != nil {
@@ -43,8 +73,31 @@ func (r *RankingRepository) FindByID(ctx context.Context, id int) (*domain.Ranki
 		return nil, err
 	}
 
-	// ランキング名
-	rankingName, err := domain.NewRankingName(ranking.Name)
+	// ドメインのランキングを返す
+	return toDomainRanking(ranking)
+}
+
+// ランキングを行ロックして取得する
+// PostgreSQLはFOR NO KEY UPDATE (ハイスコアの外部キーの参照とは競合しない)、SQL ServerはUPDLOCKでロックする。SQLiteは書き込みが直列のため不要
+func (r *RankingRepository) FindByIDForUpdate(ctx context.Context, id int) (*domain.Ranking, error) {
+	// ランキング
+	ranking := new(Ranking)
+
+	// クエリ実行
+	db := dbFromContext(ctx, r.db)
+	q := db.NewSelect().Model(ranking).Where("id = ?", id)
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		q = q.For("NO KEY UPDATE")
+	case dialect.MSSQL:
+		q = q.ModelTableExpr("rankings AS ranking WITH (UPDLOCK, ROWLOCK)")
+	}
+	err := q.Scan(ctx)
+
+	// 存在しない場合はNotFoundエラーを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewNotFoundError("ranking_not_found", fmt.Sprintf("ランキングが存在しません。ランキングID: %d", id))
+	}
 
 	// エラーハンドリング
 	if err != nil {
@@ -53,21 +106,21 @@ func (r *RankingRepository) FindByID(ctx context.Context, id int) (*domain.Ranki
 	}
 
 	// ドメインのランキングを返す
-	return &domain.Ranking{
-		ID:        ranking.ID,
-		Name:      rankingName,
-		CreatedAt: ranking.CreatedAt,
-		UpdatedAt: ranking.UpdatedAt,
-	}, nil
+	return toDomainRanking(ranking)
 }
 
-// ランキングを名前をキーとして取得する
-func (r *RankingRepository) FindByName(ctx context.Context, name domain.RankingName) (*domain.Ranking, error) {
+// ゲーム内のランキングを名前をキーとして取得する
+func (r *RankingRepository) FindByName(ctx context.Context, gameID int, name domain.RankingName) (*domain.Ranking, error) {
 	// ランキング
 	ranking := new(Ranking)
 
 	// クエリ実行
-	err := r.db.NewSelect().Model(&ranking).Where("name = ?", name.Value).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(ranking).Where("game_id = ? AND name = ?", gameID, name.Value).Scan(ctx)
+
+	// 存在しない場合はnilを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 
 	// エラーハンドリング
 	if err != nil {
@@ -76,21 +129,17 @@ func (r *RankingRepository) FindByName(ctx context.Context, name domain.RankingN
 	}
 
 	// ドメインのランキングを返す
-	return &domain.Ranking{
-		ID:        ranking.ID,
-		Name:      name,
-		CreatedAt: ranking.CreatedAt,
-		UpdatedAt: ranking.UpdatedAt,
-	}, nil
+	return toDomainRanking(ranking)
 }
 
 // ランキング一覧を取得する
-func (r *RankingRepository) FindAll(ctx context.Context) ([]domain.Ranking, error) {
+func (r *RankingRepository) FindAll(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.Ranking], error) {
 	// ランキングスライス
 	var rankings []Ranking
 
 	// クエリ実行
-	err := r.db.NewSelect().Model(&rankings).Scan(ctx)
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&rankings)
+	err := applyIDPage(q, "id", page).Scan(ctx)
 
 	// エラーハンドリング
 	if err != nil {
@@ -98,40 +147,95 @@ func (r *RankingRepository) FindAll(ctx context.Context) ([]domain.Ranking, erro
 		return nil, err
 	}
 
-	// ドメイン層のランキング構造体にマッピング
-	var domainRankings []domain.Ranking
-	for _, ranking := range rankings {
-		// ランキング名
-		rankingName, err := domain.NewRankingName(ranking.Name)
+	// ドメインのランキング一覧をページとして返す
+	return toDomainRankingPage(rankings, page)
+}
 
-		// エラーハンドリング
+// ゲームに属するランキング一覧を取得する
+func (r *RankingRepository) FindAllByGameID(ctx context.Context, gameID int, page domain.IDPageRequest) (*domain.Page[domain.Ranking], error) {
+	// ランキングスライス
+	var rankings []Ranking
+
+	// クエリ実行
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&rankings).Where("game_id = ?", gameID)
+	err := applyIDPage(q, "id", page).Scan(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのランキング一覧をページとして返す
+	return toDomainRankingPage(rankings, page)
+}
+
+// 進行中のシーズンが終了したランキング一覧を取得する
+func (r *RankingRepository) FindAllSeasonEnded(ctx context.Context, now time.Time) ([]domain.Ranking, error) {
+	// ランキングスライス
+	var rankings []Ranking
+
+	// クエリ実行
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&rankings).Where("season_ends_at <= ?", now.UTC()).Order("id").Scan(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのランキング一覧を返す
+	domainRankings := make([]domain.Ranking, 0, len(rankings))
+	for i := range rankings {
+		domainRanking, err := toDomainRanking(&rankings[i])
 		if err != nil {
-			log.Printf("Error occurred: %v", err)
 			return nil, err
 		}
-
-		// domainRankingsに詰める
-		domainRankings = append(domainRankings, domain.Ranking{
-			ID:        ranking.ID,
-			Name:      rankingName,
-			CreatedAt: ranking.CreatedAt,
-			UpdatedAt: ranking.UpdatedAt,
-		})
+		domainRankings = append(domainRankings, *domainRanking)
 	}
-
-	// ドメインのランキングスライスを返す
 	return domainRankings, nil
 }
 
 // ランキングを登録する
-func (r *RankingRepository) Create(ctx context.Context, name domain.RankingName) (*domain.Ranking, error) {
+func (r *RankingRepository) Create(ctx context.Context, newRanking *domain.Ranking) (*domain.Ranking, error) {
 	// ランキング構造体を生成
+	name := newRanking.Name
 	ranking := &Ranking{
-		Name: name.Value,
+		GameID:     newRanking.GameID,
+		Name:       name.Value,
+		SortOrder:  string(newRanking.SortOrder),
+		ScoreType:  string(newRanking.ScoreType.Kind),
+		ScoreScale: newRanking.ScoreType.Scale,
+		MinScore:   (*int64)(newRanking.ScoreRules.Min),
+		MaxScore:   (*int64)(newRanking.ScoreRules.Max),
+		ScoreStep:  (*int64)(newRanking.ScoreRules.Step),
+	}
+
+	// 周期がある場合はシーズン
+	if newRanking.Recurrence.IsRecurring() {
+		recurrence := string(newRanking.Recurrence.Period)
+		timezone := newRanking.Recurrence.Timezone()
+		ranking.Recurrence = &recurrence
+		ranking.Timezone = &timezone
+	}
+	if season := newRanking.Season; season != nil {
+		ranking.Season = &season.Number
+		ranking.SeasonStartedAt = &season.StartedAt
+		ranking.SeasonEndsAt = &season.EndsAt
 	}
 
-	// ランキング名を生成
-	rankingName, err := domain.NewRankingName(name.Value)
+	// ランキング登録クエリを実行し、同一トランザクション内でハイスコアの分布の空の集計値を登録する
+	err := runInTx(ctx, r.db, func(ctx context.Context, db bun.IDB) error {
+		if _, err := db.NewInsert().Model(ranking).Exec(ctx); err != nil {
+			return err
+		}
+		return createScoreStats(ctx, db, ranking.ID)
+	})
+
+	// ゲーム内で同名のランキングが同時に登録された場合は競合エラーを返す
+	if isUniqueViolation(err) {
+		return nil, domain.NewConflictError("ranking_name_conflict", fmt.Sprintf("ランキング名はゲーム内で既に使用されています。入力された名前: %q", name.Value))
+	}
 
 	// エラーハンドリング
 	if err != nil {
@@ -139,25 +243,165 @@ func (r *RankingRepository) Create(ctx context.Context, name domain.RankingName)
 		return nil, err
 	}
 
-	// ランキング登録クエリを実行
-	_, err = r.db.NewInsert().Model(ranking).Exec(ctx)
+	// 挿入後に ID を基に再取得
+	err = dbFromContext(ctx, r.db).NewSelect().Model(ranking).Where("id = ?", ranking.ID).Scan(ctx)
 	if err != nil {
 		log.Printf("Error occurred: %v", err)
 		return nil, err
 	}
 
-	// 挿入後に ID を基に再取得
-	err = r.db.NewSelect().Model(ranking).Where("id = ?", ranking.ID).Scan(ctx)
+	// ドメインのランキングを返す
+	return toDomainRanking(ranking)
+}
+
+// スコアの妥当性のルールを変更する
+func (r *RankingRepository) UpdatePlausibilityRules(ctx context.Context, id int, rules domain.PlausibilityRules) (*domain.Ranking, error) {
+	// 送信の最短間隔はミリ秒で保存する
+	var minSubmissionIntervalMs *int64
+	if rules.MinSubmissionInterval != nil {
+		ms := rules.MinSubmissionInterval.Milliseconds()
+		minSubmissionIntervalMs = &ms
+	}
+
+	// ランキング更新クエリを実行
+	result, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*Ranking)(nil)).
+		Set("max_improvement_ratio = ?", rules.MaxImprovementRatio).
+		Set("min_submission_interval_ms = ?", minSubmissionIntervalMs).
+		Set("score_cap = ?", (*int64)(rules.ScoreCap)).
+		Set("max_z_score = ?", rules.MaxZScore).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	// エラーハンドリング
 	if err != nil {
 		log.Printf("Error occurred: %v", err)
 		return nil, err
 	}
 
-	// ドメインのランキングを返す
+	// 更新対象が存在しない場合はNotFoundエラーを返す
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil, domain.NewNotFoundError("ranking_not_found", fmt.Sprintf("ランキングが存在しません。ランキングID: %d", id))
+	}
+
+	// 更新後のランキングを返す
+	return r.FindByID(ctx, id)
+}
+
+// ランキングを削除する
+func (r *RankingRepository) Delete(ctx context.Context, id int) error {
+	// ランキング削除クエリを実行 (ハイスコア、シーズン、送信履歴は外部キーのON DELETE CASCADEで削除される)
+	result, err := dbFromContext(ctx, r.db).NewDelete().
+		Model((*Ranking)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return err
+	}
+
+	// 削除対象が存在しない場合はNotFoundエラーを返す
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.NewNotFoundError("ranking_not_found", fmt.Sprintf("ランキングが存在しません。ランキングID: %d", id))
+	}
+
+	return nil
+}
+
+// ドメインのランキングにマッピングする
+func toDomainRanking(ranking *Ranking) (*domain.Ranking, error) {
+	// ランキング名
+	rankingName, err := domain.NewRankingName(ranking.Name)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// 並び順
+	sortOrder, err := domain.NewSortOrder(ranking.SortOrder)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// スコアの型
+	scoreType, err := domain.NewScoreType(ranking.ScoreType, ranking.ScoreScale)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// シーズンの繰り返し
+	var recurrence domain.Recurrence
+	if ranking.Recurrence != nil {
+		recurrence, err = domain.NewRecurrence(*ranking.Recurrence, *ranking.Timezone)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("Error occurred: %v", err)
+			return nil, err
+		}
+	}
+
+	// 進行中のシーズン
+	var season *domain.SeasonPeriod
+	if ranking.Season != nil {
+		season = &domain.SeasonPeriod{
+			Number:    *ranking.Season,
+			StartedAt: *ranking.SeasonStartedAt,
+			EndsAt:    *ranking.SeasonEndsAt,
+		}
+	}
+
+	// スコアの妥当性のルール
+	var minSubmissionInterval *time.Duration
+	if ranking.MinSubmissionIntervalMs != nil {
+		interval := time.Duration(*ranking.MinSubmissionIntervalMs) * time.Millisecond
+		minSubmissionInterval = &interval
+	}
+
 	return &domain.Ranking{
 		ID:        ranking.ID,
+		GameID:    ranking.GameID,
 		Name:      rankingName,
-		CreatedAt: ranking.CreatedAt,
-		UpdatedAt: ranking.UpdatedAt,
+		SortOrder: sortOrder,
+		ScoreType: scoreType,
+		ScoreRules: domain.ScoreRules{
+			Min:  (*domain.Score)(ranking.MinScore),
+			Max:  (*domain.Score)(ranking.MaxScore),
+			Step: (*domain.Score)(ranking.ScoreStep),
+		},
+		Recurrence: recurrence,
+		Season:     season,
+		CreatedAt:  ranking.CreatedAt,
+		UpdatedAt:  ranking.UpdatedAt,
+		PlausibilityRules: domain.PlausibilityRules{
+			MaxImprovementRatio:   ranking.MaxImprovementRatio,
+			MinSubmissionInterval: minSubmissionInterval,
+			ScoreCap:              (*domain.Score)(ranking.ScoreCap),
+			MaxZScore:             ranking.MaxZScore,
+		},
 	}, nil
 }
+
+// ドメインのランキング一覧のページにマッピングする
+func toDomainRankingPage(rankings []Ranking, page domain.IDPageRequest) (*domain.Page[domain.Ranking], error) {
+	domainRankings := make([]domain.Ranking, 0, len(rankings))
+	for i := range rankings {
+		domainRanking, err := toDomainRanking(&rankings[i])
+		if err != nil {
+			return nil, err
+		}
+		domainRankings = append(domainRankings, *domainRanking)
+	}
+	return toPage(domainRankings, page.Limit, page.Direction), nil
+}
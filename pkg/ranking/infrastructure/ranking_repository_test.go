@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコアの妥当性のルールを変更・解除できる
+func TestRankingRepositoryUpdatePlausibilityRules(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	rankingID, _ := newRankingWithScores(t, db, domain.SortOrderDesc)
+	repository := NewRankingRepository(db)
+
+	// ルールを設定する
+	ratio := 3.0
+	interval := 1500 * time.Millisecond
+	scoreCap := domain.Score(10000)
+	z := 4.0
+	rules := domain.PlausibilityRules{MaxImprovementRatio: &ratio, MinSubmissionInterval: &interval, ScoreCap: &scoreCap, MaxZScore: &z}
+	ranking, err := repository.UpdatePlausibilityRules(ctx, rankingID, rules)
+	require.NoError(t, err)
+	assert.Equal(t, rules, ranking.PlausibilityRules)
+
+	// ルールを解除する
+	ranking, err = repository.UpdatePlausibilityRules(ctx, rankingID, domain.PlausibilityRules{})
+	require.NoError(t, err)
+	assert.Equal(t, domain.PlausibilityRules{}, ranking.PlausibilityRules)
+
+	// 存在しないランキング
+	_, err = repository.UpdatePlausibilityRules(ctx, rankingID+1000000, rules)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
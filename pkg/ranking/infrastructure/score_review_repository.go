@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// スコアの審査
+type ScoreReview struct {
+	ID               int       `bun:"id,pk,autoincrement"`
+	SubmissionID     int       `bun:"submission_id"`
+	Decision         string    `bun:"decision"`
+	ReviewerAPIKeyID *int      `bun:"reviewer_api_key_id"`
+	Reviewer         string    `bun:"reviewer"`
+	Reason           string    `bun:"reason"`
+	ShadowBanned     bool      `bun:"shadow_banned"`
+	FlaggedAt        time.Time `bun:"flagged_at"`
+	ReviewedAt       time.Time `bun:"reviewed_at"`
+}
+
+// スコアの審査リポジトリ
+type ScoreReviewRepository struct {
+	db bun.IDB
+}
+
+// リポジトリを生成する
+func NewScoreReviewRepository(bun bun.IDB) *ScoreReviewRepository {
+	return &ScoreReviewRepository{
+		db: bun,
+	}
+}
+
+// 審査を記録する
+func (r *ScoreReviewRepository) Create(ctx context.Context, review *domain.ScoreReview) (*domain.ScoreReview, error) {
+	// 審査 (審査日時をSQLに埋め込める精度のミリ秒に丸める)
+	scoreReview := &ScoreReview{
+		SubmissionID:     review.SubmissionID,
+		Decision:         string(review.Decision),
+		ReviewerAPIKeyID: review.ReviewerAPIKeyID,
+		Reviewer:         review.Reviewer,
+		Reason:           review.Reason,
+		ShadowBanned:     review.ShadowBanned,
+		FlaggedAt:        review.FlaggedAt.UTC(),
+		ReviewedAt:       time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	// クエリ実行
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(scoreReview).Exec(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインの審査を返す
+	return &domain.ScoreReview{
+		ID:               scoreReview.ID,
+		SubmissionID:     scoreReview.SubmissionID,
+		Decision:         domain.ReviewDecision(scoreReview.Decision),
+		ReviewerAPIKeyID: scoreReview.ReviewerAPIKeyID,
+		Reviewer:         scoreReview.Reviewer,
+		Reason:           scoreReview.Reason,
+		ShadowBanned:     scoreReview.ShadowBanned,
+		FlaggedAt:        scoreReview.FlaggedAt,
+		ReviewedAt:       scoreReview.ReviewedAt,
+	}, nil
+}
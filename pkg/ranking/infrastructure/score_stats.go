@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+
+	"github.com/uptrace/bun"
+)
+
+// ランキングの進行中のシーズンのハイスコアの分布の集計値 (論理削除したユーザーを除く)
+// domain.ScoreMomentsと同じWelfordのアルゴリズムで、1文のUPDATEで更新する (右辺は更新前の値を参照するため、同時に更新しても失われない)
+// 整数の除算にならないよう、スコアは浮動小数点数にキャストして渡す
+type ScoreStats struct {
+	bun.BaseModel `bun:"table:score_stats"`
+
+	RankingID int     `bun:"ranking_id,pk"`
+	Count     int     `bun:"score_count"`
+	Mean      float64 `bun:"score_mean"`
+	M2        float64 `bun:"score_m2"`
+}
+
+// ランキングの空の集計値を登録する
+func createScoreStats(ctx context.Context, db bun.IDB, rankingID int) error {
+	_, err := db.NewInsert().Model(&ScoreStats{RankingID: rankingID}).Exec(ctx)
+	return err
+}
+
+// ランキングの集計値を取得する (登録されていない場合は空)
+func findScoreStats(ctx context.Context, db bun.IDB, rankingID int) (domain.ScoreMoments, error) {
+	var stats []ScoreStats
+	if err := db.NewSelect().Model(&stats).Where("ranking_id = ?", rankingID).Scan(ctx); err != nil {
+		return domain.ScoreMoments{}, err
+	}
+	if len(stats) == 0 {
+		return domain.ScoreMoments{}, nil
+	}
+	return domain.ScoreMoments{Count: stats[0].Count, Mean: stats[0].Mean, M2: stats[0].M2}, nil
+}
+
+// ハイスコアを集計値に加える
+func addScoreStats(ctx context.Context, db bun.IDB, rankingID int, score domain.Score) error {
+	x := float64(score)
+	_, err := db.NewUpdate().
+		Model((*ScoreStats)(nil)).
+		Set("score_count = score_count + 1").
+		Set("score_mean = score_mean + (CAST(? AS FLOAT) - score_mean) / (score_count + 1)", x).
+		Set("score_m2 = score_m2 + (CAST(? AS FLOAT) - score_mean) * (CAST(? AS FLOAT) - score_mean - (CAST(? AS FLOAT) - score_mean) / (score_count + 1))", x, x, x).
+		Where("ranking_id = ?", rankingID).
+		Exec(ctx)
+	return err
+}
+
+// ハイスコアを集計値から取り除く (最後の1件を取り除いた場合は空にする)
+func removeScoreStats(ctx context.Context, db bun.IDB, rankingID int, score domain.Score) error {
+	x := float64(score)
+	_, err := db.NewUpdate().
+		Model((*ScoreStats)(nil)).
+		Set("score_count = CASE WHEN score_count > 1 THEN score_count - 1 ELSE 0 END").
+		Set("score_mean = CASE WHEN score_count > 1 THEN (score_mean * score_count - CAST(? AS FLOAT)) / (score_count - 1) ELSE 0 END", x).
+		Set("score_m2 = CASE WHEN score_count > 1 THEN score_m2 - (CAST(? AS FLOAT) - score_mean) * (CAST(? AS FLOAT) - (score_mean * score_count - CAST(? AS FLOAT)) / (score_count - 1)) ELSE 0 END", x, x, x).
+		Where("ranking_id = ?", rankingID).
+		Exec(ctx)
+	return err
+}
+
+// 集計値のハイスコアを置き換える (件数は変わらない)
+func replaceScoreStats(ctx context.Context, db bun.IDB, rankingID int, previous domain.Score, score domain.Score) error {
+	x, old := float64(score), float64(previous)
+	delta := x - old
+	_, err := db.NewUpdate().
+		Model((*ScoreStats)(nil)).
+		Set("score_mean = score_mean + CAST(? AS FLOAT) / score_count", delta).
+		Set("score_m2 = score_m2 + CAST(? AS FLOAT) * (CAST(? AS FLOAT) - (score_mean + CAST(? AS FLOAT) / score_count) + CAST(? AS FLOAT) - score_mean)", delta, x, delta, old).
+		Where("ranking_id = ? AND score_count > 0", rankingID).
+		Exec(ctx)
+	return err
+}
+
+// ユーザーの全てのランキングのハイスコアを集計値に加えるか取り除く (ユーザーの論理削除と復元で使う)
+func updateUserScoreStats(ctx context.Context, db bun.IDB, userID int, update func(ctx context.Context, db bun.IDB, rankingID int, score domain.Score) error) error {
+	var userHighScores []UserHighScore
+	if err := db.NewSelect().Model(&userHighScores).Where("user_id = ?", userID).Scan(ctx); err != nil {
+		return err
+	}
+	for _, userHighScore := range userHighScores {
+		if err := update(ctx, db, userHighScore.RankingID, domain.Score(userHighScore.HighScore)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// シーズンの終了で集計値を空にする
+func resetScoreStats(ctx context.Context, db bun.IDB, rankingID int) error {
+	_, err := db.NewUpdate().
+		Model((*ScoreStats)(nil)).
+		Set("score_count = 0").
+		Set("score_mean = 0").
+		Set("score_m2 = 0").
+		Where("ranking_id = ?", rankingID).
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,224 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// スコアの送信履歴
+type ScoreSubmission struct {
+	ID            int       `bun:"id,pk,autoincrement"`
+	RankingID     int       `bun:"ranking_id"`
+	UserID        int       `bun:"user_id"`
+	Score         int64     `bun:"score"`
+	IPAddress     string    `bun:"ip_address"`
+	UserAgent     string    `bun:"user_agent"`
+	ClientVersion string    `bun:"client_version"`
+	SubmittedAt   time.Time `bun:"submitted_at"`
+
+	// 送信の状態と審査待ちにした理由のルールの名前 (カンマ区切り。受け付けた場合はNULL)
+	Status       string  `bun:"status"`
+	FlaggedRules *string `bun:"flagged_rules"`
+}
+
+// スコアの送信履歴リポジトリ
+type ScoreSubmissionRepository struct {
+	db bun.IDB
+}
+
+// リポジトリを生成する
+func NewScoreSubmissionRepository(bun bun.IDB) *ScoreSubmissionRepository {
+	return &ScoreSubmissionRepository{
+		db: bun,
+	}
+}
+
+// 送信履歴を追加する
+func (r *ScoreSubmissionRepository) Create(ctx context.Context, submission *domain.ScoreSubmission) (*domain.ScoreSubmission, error) {
+	// 送信履歴 (期間での絞り込みが一致するよう、送信日時をSQLに埋め込める精度のミリ秒に丸める)
+	scoreSubmission := &ScoreSubmission{
+		RankingID:     submission.RankingID,
+		UserID:        submission.UserID,
+		Score:         int64(submission.Score),
+		IPAddress:     submission.Client.IPAddress,
+		UserAgent:     submission.Client.UserAgent,
+		ClientVersion: submission.Client.ClientVersion,
+		SubmittedAt:   time.Now().UTC().Truncate(time.Millisecond),
+		Status:        string(submission.Status),
+	}
+	if len(submission.FlaggedRules) > 0 {
+		flaggedRules := strings.Join(submission.FlaggedRules, ",")
+		scoreSubmission.FlaggedRules = &flaggedRules
+	}
+
+	// クエリ実行
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(scoreSubmission).Exec(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインの送信履歴を返す
+	return toDomainScoreSubmission(scoreSubmission), nil
+}
+
+// 送信履歴を取得する
+func (r *ScoreSubmissionRepository) FindByID(ctx context.Context, id int) (*domain.ScoreSubmission, error) {
+	// 送信履歴
+	scoreSubmission := new(ScoreSubmission)
+
+	// クエリ実行
+	err := dbFromContext(ctx, r.db).NewSelect().Model(scoreSubmission).Where("id = ?", id).Scan(ctx)
+
+	// 存在しない場合はNotFoundエラーを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewNotFoundError("score_submission_not_found", fmt.Sprintf("送信履歴が存在しません。送信履歴ID: %d", id))
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインの送信履歴を返す
+	return toDomainScoreSubmission(scoreSubmission), nil
+}
+
+// ランキングとユーザーを指定して、最後の送信履歴を取得する
+func (r *ScoreSubmissionRepository) FindLatest(ctx context.Context, rankingID int, userID int) (*domain.ScoreSubmission, error) {
+	// 送信履歴
+	scoreSubmission := new(ScoreSubmission)
+
+	// クエリ実行
+	err := dbFromContext(ctx, r.db).NewSelect().Model(scoreSubmission).
+		Where("ranking_id = ? AND user_id = ?", rankingID, userID).
+		OrderExpr("id DESC").
+		Limit(1).
+		Scan(ctx)
+
+	// 未送信の場合はnilを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインの送信履歴を返す
+	return toDomainScoreSubmission(scoreSubmission), nil
+}
+
+// ランキングとユーザーを指定して、期間内の送信履歴を送信順に取得する
+func (r *ScoreSubmissionRepository) FindAllByRankingIDAndUserID(ctx context.Context, rankingID int, userID int, timeRange domain.TimeRange, page domain.IDPageRequest) (*domain.Page[domain.ScoreSubmission], error) {
+	// 送信履歴スライス
+	var scoreSubmissions []ScoreSubmission
+
+	// クエリ実行 (IDをキーとしてページングする)
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&scoreSubmissions).Where("ranking_id = ? AND user_id = ?", rankingID, userID)
+	if timeRange.From != nil {
+		q = q.Where("submitted_at >= ?", timeRange.From.UTC())
+	}
+	if timeRange.To != nil {
+		q = q.Where("submitted_at < ?", timeRange.To.UTC())
+	}
+	err := applyIDPage(q, "id", page).Scan(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインの送信履歴一覧をページとして返す
+	submissions := make([]domain.ScoreSubmission, 0, len(scoreSubmissions))
+	for i := range scoreSubmissions {
+		submissions = append(submissions, *toDomainScoreSubmission(&scoreSubmissions[i]))
+	}
+	return toPage(submissions, page.Limit, page.Direction), nil
+}
+
+// 審査待ちの送信履歴を送信順に取得する
+func (r *ScoreSubmissionRepository) FindAllPendingReview(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.ScoreSubmission], error) {
+	// 送信履歴スライス
+	var scoreSubmissions []ScoreSubmission
+
+	// クエリ実行 (IDをキーとしてページングする。論理削除したユーザーは除外する)
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&scoreSubmissions).
+		Where("status = ?", string(domain.SubmissionPendingReview)).
+		Where("user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)")
+	err := applyIDPage(q, "id", page).Scan(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインの送信履歴一覧をページとして返す
+	submissions := make([]domain.ScoreSubmission, 0, len(scoreSubmissions))
+	for i := range scoreSubmissions {
+		submissions = append(submissions, *toDomainScoreSubmission(&scoreSubmissions[i]))
+	}
+	return toPage(submissions, page.Limit, page.Direction), nil
+}
+
+// 審査待ちの送信履歴を審査の結果の状態に更新する
+func (r *ScoreSubmissionRepository) Resolve(ctx context.Context, id int, status domain.SubmissionStatus) error {
+	// 審査待ちの場合のみ更新するクエリを実行
+	result, err := dbFromContext(ctx, r.db).NewUpdate().
+		Model((*ScoreSubmission)(nil)).
+		Set("status = ?", string(status)).
+		Where("id = ? AND status = ?", id, string(domain.SubmissionPendingReview)).
+		Exec(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return err
+	}
+
+	// 審査待ちでない場合はConflictエラーを返す
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.NewConflictError("submission_not_pending_review", fmt.Sprintf("送信は審査待ちではありません。送信履歴ID: %d", id))
+	}
+
+	return nil
+}
+
+// ドメインの送信履歴にマッピングする
+func toDomainScoreSubmission(scoreSubmission *ScoreSubmission) *domain.ScoreSubmission {
+	// 審査待ちにした理由のルールの名前
+	var flaggedRules []string
+	if scoreSubmission.FlaggedRules != nil {
+		flaggedRules = strings.Split(*scoreSubmission.FlaggedRules, ",")
+	}
+
+	return &domain.ScoreSubmission{
+		ID:        scoreSubmission.ID,
+		RankingID: scoreSubmission.RankingID,
+		UserID:    scoreSubmission.UserID,
+		Score:     domain.Score(scoreSubmission.Score),
+		Client: domain.ClientMetadata{
+			IPAddress:     scoreSubmission.IPAddress,
+			UserAgent:     scoreSubmission.UserAgent,
+			ClientVersion: scoreSubmission.ClientVersion,
+		},
+		SubmittedAt:  scoreSubmission.SubmittedAt,
+		Status:       domain.SubmissionStatus(scoreSubmission.Status),
+		FlaggedRules: flaggedRules,
+	}
+}
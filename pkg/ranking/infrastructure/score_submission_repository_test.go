@@ -0,0 +1,161 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 送信履歴を送信順にページングし、期間で絞り込める
+func TestScoreSubmissionRepositoryFindAllByRankingIDAndUserID(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// テスト用のユーザー、ゲームとランキングを登録
+	suffix := time.Now().UnixNano()
+	userName, _ := domain.NewUserName(fmt.Sprintf("submission-%d", suffix))
+	user, err := NewUserRepository(db).Create(ctx, userName)
+	require.NoError(t, err)
+	gameName, _ := domain.NewGameName(fmt.Sprintf("submission-%d", suffix))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName(fmt.Sprintf("submission-%d", suffix))
+	ranking, err := NewRankingRepository(db).Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}})
+	require.NoError(t, err)
+
+	// 3件の送信履歴を追加する
+	repository := NewScoreSubmissionRepository(db)
+	client := domain.ClientMetadata{IPAddress: "192.0.2.1", UserAgent: "game-client", ClientVersion: "1.2.3"}
+	var created []*domain.ScoreSubmission
+	for _, score := range []domain.Score{100, 50, 150} {
+		submission, err := repository.Create(ctx, domain.NewScoreSubmission(ranking.ID, user.ID, score, client))
+		require.NoError(t, err)
+		created = append(created, submission)
+	}
+
+	// 送信順にページングできる
+	firstPage, err := repository.FindAllByRankingIDAndUserID(ctx, ranking.ID, user.ID, domain.TimeRange{}, domain.IDPageRequest{Direction: domain.PageNext, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, firstPage.Items, 2)
+	assert.True(t, firstPage.HasMore)
+	assert.Equal(t, domain.Score(100), firstPage.Items[0].Score)
+	assert.Equal(t, client, firstPage.Items[0].Client)
+	secondPage, err := repository.FindAllByRankingIDAndUserID(ctx, ranking.ID, user.ID, domain.TimeRange{}, domain.IDPageRequest{CursorID: firstPage.Items[1].ID, Direction: domain.PageNext, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, secondPage.Items, 1)
+	assert.Equal(t, domain.Score(150), secondPage.Items[0].Score)
+
+	// 開始日時を含み、終了日時を含まない
+	from := created[1].SubmittedAt
+	to := created[1].SubmittedAt.Add(time.Millisecond)
+	timeRange, err := domain.NewTimeRange(&from, &to)
+	require.NoError(t, err)
+	filtered, err := repository.FindAllByRankingIDAndUserID(ctx, ranking.ID, user.ID, timeRange, domain.IDPageRequest{Direction: domain.PageNext, Limit: 10})
+	require.NoError(t, err)
+	for _, submission := range filtered.Items {
+		assert.True(t, timeRange.Contains(submission.SubmittedAt))
+	}
+	assert.Contains(t, submissionIDs(filtered.Items), created[1].ID)
+
+	// 期間外の場合は空
+	future := time.Now().Add(time.Hour)
+	timeRange, err = domain.NewTimeRange(&future, nil)
+	require.NoError(t, err)
+	filtered, err = repository.FindAllByRankingIDAndUserID(ctx, ranking.ID, user.ID, timeRange, domain.IDPageRequest{Direction: domain.PageNext, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, filtered.Items)
+}
+
+// 送信履歴のIDの一覧
+func submissionIDs(submissions []domain.ScoreSubmission) []int {
+	ids := make([]int, 0, len(submissions))
+	for _, submission := range submissions {
+		ids = append(ids, submission.ID)
+	}
+	return ids
+}
+
+// 最後の送信履歴を審査待ちの状態とともに取得する
+func TestScoreSubmissionRepositoryFindLatest(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	rankingID, userIDs := newRankingWithScores(t, db, domain.SortOrderDesc, 100)
+	repository := NewScoreSubmissionRepository(db)
+
+	// 未送信の場合はnil
+	latest, err := repository.FindLatest(ctx, rankingID, userIDs[0])
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	// 受け付けた送信と審査待ちの送信
+	_, err = repository.Create(ctx, domain.NewScoreSubmission(rankingID, userIDs[0], 100, domain.ClientMetadata{}))
+	require.NoError(t, err)
+	held := domain.NewScoreSubmission(rankingID, userIDs[0], 10000, domain.ClientMetadata{})
+	held.HoldForReview([]string{domain.PlausibilityRuleScoreCap, domain.PlausibilityRuleZScore})
+	created, err := repository.Create(ctx, held)
+	require.NoError(t, err)
+
+	latest, err = repository.FindLatest(ctx, rankingID, userIDs[0])
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, created.ID, latest.ID)
+	assert.Equal(t, domain.SubmissionPendingReview, latest.Status)
+	assert.Equal(t, []string{domain.PlausibilityRuleScoreCap, domain.PlausibilityRuleZScore}, latest.FlaggedRules)
+}
+
+// 審査待ちの送信履歴は審査するまでキューに並び、審査は一度だけ記録できる
+func TestScoreSubmissionRepositoryReview(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	rankingID, userIDs := newRankingWithScores(t, db, domain.SortOrderDesc, 100, 200)
+	repository := NewScoreSubmissionRepository(db)
+	reviewRepository := NewScoreReviewRepository(db)
+
+	// 受け付けた送信と、2人のユーザーの審査待ちの送信
+	_, err := repository.Create(ctx, domain.NewScoreSubmission(rankingID, userIDs[0], 100, domain.ClientMetadata{}))
+	require.NoError(t, err)
+	var held []int
+	for _, userID := range userIDs {
+		submission := domain.NewScoreSubmission(rankingID, userID, 10000, domain.ClientMetadata{})
+		submission.HoldForReview([]string{domain.PlausibilityRuleScoreCap})
+		created, err := repository.Create(ctx, submission)
+		require.NoError(t, err)
+		held = append(held, created.ID)
+	}
+
+	// 審査待ちの送信のみ送信順に取得する
+	page := domain.IDPageRequest{Direction: domain.PageNext, Limit: 10}
+	queue, err := repository.FindAllPendingReview(ctx, page)
+	require.NoError(t, err)
+	assert.Equal(t, held, submissionIDs(queue.Items))
+
+	// 審査した送信はキューから外れ、二度目の審査はConflict
+	submission, err := repository.FindByID(ctx, held[0])
+	require.NoError(t, err)
+	require.NoError(t, repository.Resolve(ctx, held[0], domain.SubmissionRejected))
+	assert.ErrorIs(t, repository.Resolve(ctx, held[0], domain.SubmissionApproved), domain.ErrConflict)
+	queue, err = repository.FindAllPendingReview(ctx, page)
+	require.NoError(t, err)
+	assert.Equal(t, held[1:], submissionIDs(queue.Items))
+
+	// 審査を記録する
+	reviewer, err := domain.NewAPIKeyName("moderator")
+	require.NoError(t, err)
+	review, err := domain.NewScoreReview(submission, domain.ReviewRejected, &domain.APIKey{ID: 1, Name: reviewer}, "改造クライアント", true)
+	require.NoError(t, err)
+	created, err := reviewRepository.Create(ctx, review)
+	require.NoError(t, err)
+	assert.Positive(t, created.ID)
+	assert.Equal(t, held[0], created.SubmissionID)
+	assert.Equal(t, "moderator", created.Reviewer)
+	assert.False(t, created.ReviewedAt.IsZero())
+
+	// 存在しない送信履歴はNotFound
+	_, err = repository.FindByID(ctx, held[1]+1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
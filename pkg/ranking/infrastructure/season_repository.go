@@ -0,0 +1,171 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 終了したシーズン
+type Season struct {
+	RankingID  int       `bun:"ranking_id,pk"`
+	Season     int       `bun:"season,pk"`
+	StartedAt  time.Time `bun:"started_at"`
+	EndedAt    time.Time `bun:"ended_at"`
+	TotalUsers int       `bun:"total_users"`
+}
+
+// シーズンリポジトリ
+type SeasonRepository struct {
+	db bun.IDB
+}
+
+// リポジトリを生成する
+func NewSeasonRepository(bun bun.IDB) *SeasonRepository {
+	return &SeasonRepository{
+		db: bun,
+	}
+}
+
+// ランキングの終了したシーズンを番号をキーとして取得する
+func (r *SeasonRepository) FindByNumber(ctx context.Context, rankingID int, number int) (*domain.Season, error) {
+	return findSeason(ctx, dbFromContext(ctx, r.db), rankingID, number)
+}
+
+// ランキングの終了したシーズン一覧を取得する
+func (r *SeasonRepository) FindAllByRankingID(ctx context.Context, rankingID int, page domain.IDPageRequest) (*domain.Page[domain.Season], error) {
+	// シーズンスライス
+	var seasons []Season
+
+	// クエリ実行 (シーズンの番号をキーとしてページングする)
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&seasons).Where("ranking_id = ?", rankingID)
+	err := applyIDPage(q, "season", page).Scan(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのシーズン一覧をページとして返す
+	domainSeasons := make([]domain.Season, 0, len(seasons))
+	for i := range seasons {
+		domainSeasons = append(domainSeasons, *toDomainSeason(&seasons[i]))
+	}
+	return toPage(domainSeasons, page.Limit, page.Direction), nil
+}
+
+// 進行中のシーズンを終了して次のシーズンを開始する
+func (r *SeasonRepository) Archive(ctx context.Context, ranking *domain.Ranking, next domain.SeasonPeriod) (*domain.Season, error) {
+	// 終了するシーズン
+	current := ranking.Season
+	season := &Season{
+		RankingID: ranking.ID,
+		Season:    current.Number,
+		StartedAt: current.StartedAt,
+		EndedAt:   current.EndsAt,
+	}
+
+	// ランキングの更新、アーカイブ、ユーザーハイスコアの削除をアトミックに行う
+	err := runInTx(ctx, r.db, func(ctx context.Context, db bun.IDB) error {
+		// 終了するシーズンのままの場合のみ次のシーズンに進める (ランキングの行ロックで同時に進めるのを防ぐ)
+		result, err := db.NewUpdate().
+			Model((*Ranking)(nil)).
+			Set("season = ?", next.Number).
+			Set("season_started_at = ?", next.StartedAt).
+			Set("season_ends_at = ?", next.EndsAt).
+			Set("updated_at = ?", time.Now().UTC()).
+			Where("id = ? AND season = ?", ranking.ID, current.Number).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return domain.NewConflictError("season_already_rotated", fmt.Sprintf("シーズンは既に終了しています。ランキングID: %d, シーズン: %d", ranking.ID, current.Number))
+		}
+
+		// 終了したシーズンを登録する
+		if _, err := db.NewInsert().Model(season).Exec(ctx); err != nil {
+			return err
+		}
+
+		// ユーザーハイスコアをランク付けしてアーカイブする (論理削除したユーザーのハイスコアはアーカイブしない)
+		result, err = db.NewRaw(`
+			INSERT INTO archived_user_high_scores (ranking_id, season, rank, user_id, high_score, timestamp)
+			SELECT ?, ?, ranked.rank, ranked.user_id, ranked.score, ranked.achieved_at FROM (?) AS ranked`,
+			ranking.ID, current.Number, rankedUserHighScores(db, ranking.ID, ranking.SortOrder)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// アーカイブした件数を参加人数として記録する
+		totalUsers, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		season.TotalUsers = int(totalUsers)
+		_, err = db.NewUpdate().Model(season).Column("total_users").WherePK().Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// 次のシーズンは空のランキングと空のハイスコアの分布から始める
+		_, err = db.NewDelete().Model((*UserHighScore)(nil)).Where("ranking_id = ?", ranking.ID).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return resetScoreStats(ctx, db, ranking.ID)
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		if !errors.Is(err, domain.ErrConflict) {
+			log.Printf("Error occurred: %v", err)
+		}
+		return nil, err
+	}
+
+	// ドメインのシーズンを返す
+	return toDomainSeason(season), nil
+}
+
+// ランキングの終了したシーズンを取得する (存在しない場合はNotFoundエラー)
+func findSeason(ctx context.Context, db bun.IDB, rankingID int, number int) (*domain.Season, error) {
+	// シーズン
+	season := new(Season)
+
+	// クエリ実行
+	err := db.NewSelect().Model(season).Where("ranking_id = ? AND season = ?", rankingID, number).Scan(ctx)
+
+	// 存在しない場合はNotFoundエラーを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewNotFoundError("season_not_found", fmt.Sprintf("終了したシーズンが存在しません。ランキングID: %d, シーズン: %d", rankingID, number))
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのシーズンを返す
+	return toDomainSeason(season), nil
+}
+
+// ドメインのシーズンにマッピングする
+func toDomainSeason(season *Season) *domain.Season {
+	return &domain.Season{
+		RankingID:  season.RankingID,
+		Number:     season.Season,
+		StartedAt:  season.StartedAt,
+		EndedAt:    season.EndedAt,
+		TotalUsers: season.TotalUsers,
+	}
+}
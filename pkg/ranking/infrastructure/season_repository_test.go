@@ -0,0 +1,152 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// シーズンを終了すると終了時点の順位でアーカイブされ、ユーザーハイスコアは空になる
+func TestSeasonRepositoryArchive(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// テスト用のゲームと毎日繰り返すランキングを登録
+	suffix := time.Now().UnixNano()
+	gameName, _ := domain.NewGameName(fmt.Sprintf("season-%d", suffix))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName(fmt.Sprintf("season-%d", suffix))
+	recurrence, err := domain.NewRecurrence("daily", "UTC")
+	require.NoError(t, err)
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	firstSeason := recurrence.SeasonAt(1, started)
+	rankingRepository := NewRankingRepository(db)
+	ranking, err := rankingRepository.Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}, Recurrence: recurrence, Season: &firstSeason})
+	require.NoError(t, err)
+
+	// 3人のハイスコアを登録
+	userHighScoreRepository := NewUserHighScoreRepository(db)
+	var userIDs []int
+	for i, score := range []domain.Score{100, 300, 200} {
+		userName, _ := domain.NewUserName(fmt.Sprintf("season-%d-%d", suffix, i))
+		user, err := NewUserRepository(db).Create(ctx, userName)
+		require.NoError(t, err)
+		_, err = userHighScoreRepository.Upsert(ctx, ranking.ID, user.ID, score, domain.SortOrderDesc)
+		require.NoError(t, err)
+		userIDs = append(userIDs, user.ID)
+	}
+
+	// 終了したランキングとして検索される
+	now := firstSeason.EndsAt.Add(time.Minute)
+	ended, err := rankingRepository.FindAllSeasonEnded(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, ended, 1)
+	assert.Equal(t, ranking.ID, ended[0].ID)
+
+	// シーズンを終了する
+	repository := NewSeasonRepository(db)
+	season, err := repository.Archive(ctx, &ended[0], ended[0].NextSeason(now))
+	require.NoError(t, err)
+	assert.Equal(t, 1, season.Number)
+	assert.Equal(t, 3, season.TotalUsers)
+	assert.True(t, firstSeason.EndsAt.Equal(season.EndedAt))
+
+	// 同じシーズンは二重に終了できない
+	_, err = repository.Archive(ctx, &ended[0], ended[0].NextSeason(now))
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	// ランキングは次のシーズンに進み、ユーザーハイスコアは空になる
+	rotated, err := rankingRepository.FindByID(ctx, ranking.ID)
+	require.NoError(t, err)
+	require.NotNil(t, rotated.Season)
+	assert.Equal(t, 2, rotated.Season.Number)
+	userHighScore, err := userHighScoreRepository.Find(ctx, ranking.ID, userIDs[0])
+	require.NoError(t, err)
+	assert.Nil(t, userHighScore)
+	stats, err := userHighScoreRepository.FetchScoreStats(ctx, ranking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ScoreStats{}, stats)
+
+	// アーカイブした順位をランクをキーとしてページングできる
+	queryService := NewSeasonUserRankingQueryService(db)
+	firstPage, err := queryService.FetchSeasonUserRanking(ctx, ranking.ID, 1, domain.IDPageRequest{Direction: domain.PageNext, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, firstPage.TotalUsers)
+	require.Len(t, firstPage.UserRanks, 2)
+	assert.Equal(t, userIDs[1], firstPage.UserRanks[0].UserID)
+	assert.Equal(t, 1, firstPage.UserRanks[0].Rank)
+	assert.Equal(t, userIDs[2], firstPage.UserRanks[1].UserID)
+	assert.True(t, firstPage.HasNext)
+	secondPage, err := queryService.FetchSeasonUserRanking(ctx, ranking.ID, 1, domain.IDPageRequest{CursorID: 2, Direction: domain.PageNext, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, secondPage.UserRanks, 1)
+	assert.Equal(t, userIDs[0], secondPage.UserRanks[0].UserID)
+	assert.Equal(t, 3, secondPage.UserRanks[0].Rank)
+	assert.False(t, secondPage.HasNext)
+	assert.True(t, secondPage.HasPrev)
+
+	// 終了したシーズン一覧
+	seasons, err := repository.FindAllByRankingID(ctx, ranking.ID, domain.IDPageRequest{Direction: domain.PageNext, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, seasons.Items, 1)
+	assert.Equal(t, 1, seasons.Items[0].Number)
+}
+
+// 送信のトランザクションが行ロックしたランキングは、コミットするまでシーズンを進められない
+func TestSeasonRepositoryArchiveWaitsForRankingLock(t *testing.T) {
+	db := newConcurrentTestDB(t)
+	ctx := context.Background()
+
+	// テスト用のゲームと毎日繰り返すランキングを登録
+	suffix := time.Now().UnixNano()
+	gameName, _ := domain.NewGameName(fmt.Sprintf("season-lock-%d", suffix))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName(fmt.Sprintf("season-lock-%d", suffix))
+	recurrence, err := domain.NewRecurrence("daily", "UTC")
+	require.NoError(t, err)
+	firstSeason := recurrence.SeasonAt(1, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	rankingRepository := NewRankingRepository(db)
+	ranking, err := rankingRepository.Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}, Recurrence: recurrence, Season: &firstSeason})
+	require.NoError(t, err)
+	userName, _ := domain.NewUserName(fmt.Sprintf("season-lock-%d", suffix))
+	user, err := NewUserRepository(db).Create(ctx, userName)
+	require.NoError(t, err)
+
+	// ランキングを行ロックしている間にシーズンを進める
+	archived := make(chan error, 1)
+	err = NewTransactionManager(db).RunInTx(ctx, func(ctx context.Context) error {
+		current, err := rankingRepository.FindByIDForUpdate(ctx, ranking.ID)
+		if err != nil {
+			return err
+		}
+		go func() {
+			now := firstSeason.EndsAt.Add(time.Minute)
+			_, err := NewSeasonRepository(db).Archive(context.Background(), current, current.NextSeason(now))
+			archived <- err
+		}()
+
+		// シーズンを進める処理はロックを待つ
+		select {
+		case err := <-archived:
+			t.Errorf("Expected archive to wait for the ranking lock, got %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+		_, err = NewUserHighScoreRepository(db).Upsert(ctx, ranking.ID, user.ID, 100, domain.SortOrderDesc)
+		return err
+	})
+	require.NoError(t, err)
+
+	// コミット後にシーズンを進め、読み取ったシーズンのハイスコアとしてアーカイブする
+	require.NoError(t, <-archived)
+	archivedUserRanking, err := NewSeasonUserRankingQueryService(db).FetchSeasonUserRanking(ctx, ranking.ID, 1, domain.IDPageRequest{Direction: domain.PageNext, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, archivedUserRanking.UserRanks, 1)
+	assert.Equal(t, user.ID, archivedUserRanking.UserRanks[0].UserID)
+}
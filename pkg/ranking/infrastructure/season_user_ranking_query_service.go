@@ -0,0 +1,86 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+
+	"github.com/uptrace/bun"
+)
+
+// 終了したシーズンのユーザーランキングクエリサービス
+type SeasonUserRankingQueryService struct {
+	db bun.IDB
+}
+
+// クエリサービスを生成する
+func NewSeasonUserRankingQueryService(bun bun.IDB) *SeasonUserRankingQueryService {
+	return &SeasonUserRankingQueryService{
+		db: bun,
+	}
+}
+
+// 終了したシーズンのユーザーランキングを取得する
+func (s *SeasonUserRankingQueryService) FetchSeasonUserRanking(ctx context.Context, rankingID int, number int, page domain.IDPageRequest) (*usecase.SeasonUserRankingDto, error) {
+	db := dbFromContext(ctx, s.db)
+
+	// ランキングとシーズンを取得
+	ranking, err := findRanking(ctx, db, rankingID)
+	if err != nil {
+		return nil, err
+	}
+	season, err := findSeason(ctx, db, rankingID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// 論理削除したユーザーを除外したアーカイブ
+	archived := db.NewSelect().
+		TableExpr("archived_user_high_scores AS auhs").
+		Join("JOIN users ON users.id = auhs.user_id").
+		Where("auhs.ranking_id = ? AND auhs.season = ? AND users.deleted_at IS NULL", rankingID, number)
+
+	// 表示する参加人数
+	totalUsers, err := archived.Count(ctx)
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ユーザーランクスライス
+	var userRanks []UserRank
+
+	// アーカイブ時のランクの順に詰め直したランクをキーとしてページングする
+	ranked := archived.
+		ColumnExpr("ROW_NUMBER() OVER (ORDER BY auhs.rank ASC) AS rank").
+		ColumnExpr("auhs.user_id, users.name AS user_name, auhs.high_score AS score, auhs.timestamp AS achieved_at")
+	q := db.NewSelect().
+		TableExpr("(?) AS ranked", ranked).
+		ColumnExpr("ranked.*")
+	err = applyIDPage(q, "ranked.rank", page).Scan(ctx, &userRanks)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ユースケース層のユーザーランキング構造体にマッピング
+	userRankPage := toPage(userRanks, page.Limit, page.Direction)
+	userRankDtos := make([]usecase.UserRankDto, 0, len(userRankPage.Items))
+	for _, userRank := range userRankPage.Items {
+		userRankDtos = append(userRankDtos, toUserRankDto(userRank, ranking.ScoreType))
+	}
+	seasonUserRanking := &usecase.SeasonUserRankingDto{
+		RankingID:   ranking.ID,
+		RankingName: ranking.Name.Value,
+		Season:      season.Number,
+		StartedAt:   season.StartedAt,
+		EndedAt:     season.EndedAt,
+		TotalUsers:  totalUsers,
+		UserRanks:   userRankDtos,
+	}
+	seasonUserRanking.HasNext, seasonUserRanking.HasPrev = usecase.PageLinks(userRankPage.HasMore, page.CursorID > 0, page.Direction)
+	return seasonUserRanking, nil
+}
@@ -0,0 +1,64 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// スコアの送信の署名の使用済みのナンス
+type SubmissionNonce struct {
+	GameID    int       `bun:"game_id,pk"`
+	Nonce     string    `bun:"nonce,pk"`
+	ExpiresAt time.Time `bun:"expires_at"`
+}
+
+// スコアの送信の署名の使用済みのナンスのリポジトリ
+// 複数のプロセスで同じデータベースを使う場合も、ゲームとナンスの一意制約で再送を検出する
+type SubmissionNonceRepository struct {
+	db bun.IDB
+}
+
+// リポジトリを生成する
+func NewSubmissionNonceRepository(bun bun.IDB) *SubmissionNonceRepository {
+	return &SubmissionNonceRepository{
+		db: bun,
+	}
+}
+
+// ゲームのナンスを期限まで使用済みにする
+func (r *SubmissionNonceRepository) Use(ctx context.Context, gameID int, nonce string, expiresAt time.Time) error {
+	err := runInTx(ctx, r.db, func(ctx context.Context, db bun.IDB) error {
+		// ゲームの期限切れのナンスを削除する (期限切れのナンスは再び使える)
+		_, err := db.NewDelete().
+			Model((*SubmissionNonce)(nil)).
+			Where("game_id = ?", gameID).
+			Where("expires_at <= ?", time.Now().UTC()).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// ナンスを記録する (期限をSQLに埋め込める精度のミリ秒に丸める)
+		_, err = db.NewInsert().
+			Model(&SubmissionNonce{GameID: gameID, Nonce: nonce, ExpiresAt: expiresAt.UTC().Truncate(time.Millisecond)}).
+			Exec(ctx)
+		return err
+	})
+
+	// 期限内に使用済みの場合は競合エラーを返す
+	if isUniqueViolation(err) {
+		return domain.NewConflictError("submission_nonce_replayed", fmt.Sprintf("このナンスは既に使用されています。ナンス: %q", nonce))
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return err
+	}
+	return nil
+}
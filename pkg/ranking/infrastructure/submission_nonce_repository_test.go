@@ -0,0 +1,48 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 期限内のナンスの再利用は競合エラーになり、ロールバックしたナンスと期限切れのナンスは再び使える
+func TestSubmissionNonceRepositoryUse(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repository := NewSubmissionNonceRepository(db)
+	transactionManager := NewTransactionManager(db)
+
+	gameName, _ := domain.NewGameName(fmt.Sprintf("nonce-%d", time.Now().UnixNano()))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+	now := time.Now()
+
+	// 期限内は再利用できない (別のゲームでは使える)
+	require.NoError(t, repository.Use(ctx, game.ID, "a", now.Add(5*time.Minute)))
+	err = repository.Use(ctx, game.ID, "a", now.Add(5*time.Minute))
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	otherName, _ := domain.NewGameName(fmt.Sprintf("nonce-other-%d", time.Now().UnixNano()))
+	other, err := NewGameRepository(db).Create(ctx, otherName)
+	require.NoError(t, err)
+	assert.NoError(t, repository.Use(ctx, other.ID, "a", now.Add(5*time.Minute)))
+
+	// トランザクションがロールバックした場合はナンスを消費しない
+	errRollback := errors.New("rollback")
+	err = transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, repository.Use(ctx, game.ID, "b", now.Add(5*time.Minute)))
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+	assert.NoError(t, repository.Use(ctx, game.ID, "b", now.Add(5*time.Minute)))
+
+	// 期限切れのナンスは再び使える
+	require.NoError(t, repository.Use(ctx, game.ID, "c", now.Add(-time.Second)))
+	assert.NoError(t, repository.Use(ctx, game.ID, "c", now.Add(5*time.Minute)))
+}
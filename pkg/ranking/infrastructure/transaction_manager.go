@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// コンテキストにトランザクションを格納するためのキー
+type txContextKey struct{}
+
+// トランザクションマネージャー
+type TransactionManager struct {
+	db *bun.DB
+}
+
+// トランザクションマネージャーを生成する
+func NewTransactionManager(bun *bun.DB) *TransactionManager {
+	return &TransactionManager{
+		db: bun,
+	}
+}
+
+// 関数をトランザクション内で実行する
+func (m *TransactionManager) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 既にトランザクション内であればそのトランザクションに参加する
+	if _, ok := ctx.Value(txContextKey{}).(bun.Tx); ok {
+		return fn(ctx)
+	}
+
+	// トランザクションをコンテキストに格納して関数を実行する
+	return m.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// コンテキストにトランザクションがあればそれを、なければ元の接続を返す
+func dbFromContext(ctx context.Context, db bun.IDB) bun.IDB {
+	if tx, ok := ctx.Value(txContextKey{}).(bun.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// コンテキストのトランザクション内で関数を実行する (トランザクション外の場合はこの関数のためにトランザクションを開始する)
+// 複数の文をまとめてアトミックに実行する必要があるリポジトリで使う
+func runInTx(ctx context.Context, db bun.IDB, fn func(ctx context.Context, db bun.IDB) error) error {
+	if tx, ok := ctx.Value(txContextKey{}).(bun.Tx); ok {
+		return fn(ctx, tx)
+	}
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx), tx)
+	})
+}
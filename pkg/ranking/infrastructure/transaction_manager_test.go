@@ -0,0 +1,38 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// トランザクション内の書き込みはエラー時にロールバックされる
+func TestTransactionManagerRollback(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	transactionManager := NewTransactionManager(db)
+	repository := NewUserRepository(db)
+
+	// トランザクション内でユーザーを登録してからエラーを返す
+	var created *domain.User
+	userName, _ := domain.NewUserName(fmt.Sprintf("rollback-%d", time.Now().UnixNano()))
+	errRollback := errors.New("rollback")
+	err := transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = repository.Create(ctx, userName)
+		require.NoError(t, err)
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	// ロールバックされているので取得できない
+	_, err = repository.FindByID(ctx, created.ID)
+	assert.Error(t, err, "Expected user to be rolled back")
+}
@@ -4,41 +4,47 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"practice-go-game-ranking/pkg/ranking/domain"
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
 )
 
 // ユーザーハイスコア
 type UserHighScore struct {
 	RankingID int       `bun:"ranking_id,pk"`
 	UserID    int       `bun:"user_id,pk"`
-	HighScore int       `bun:"high_score"`
+	HighScore int64     `bun:"high_score"`
 	Timestamp time.Time `bun:"timestamp,nullzero,default:CURRENT_TIMESTAMP"`
 }
 
 // ユーザーハイスコアリポジトリ
 type UserHighScoreRepository struct {
-	db *bun.DB
+	db bun.IDB
 }
 
 // リポジトリを生成する
-func NewUserHighScoreRepository(bun *bun.DB) *UserHighScoreRepository {
+func NewUserHighScoreRepository(bun bun.IDB) *UserHighScoreRepository {
 	return &UserHighScoreRepository{
 		db: bun,
 	}
 }
 
 // ユーザーハイスコアを取得する
-// ランキングをIDをキーとして取得する
 func (r *UserHighScoreRepository) Find(ctx context.Context, rankingID int, userID int) (*domain.UserHighScore, error) {
 	// ユーザーハイスコア
 	userHighScore := new(UserHighScore)
 
 	// クエリ実行
-	err := r.db.NewSelect().Model(&userHighScore).Where("ranking_id = ? and user_id = ?", rankingID, userID).Scan(ctx)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(userHighScore).Where("ranking_id = ? and user_id = ?", rankingID, userID).Scan(ctx)
+
+	// 未登録の場合はnilを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 
 	// エラーハンドリング
 	if err != nil {
@@ -50,55 +56,248 @@ func (r *UserHighScoreRepository) Find(ctx context.Context, rankingID int, userI
 	return &domain.UserHighScore{
 		RankingID: userHighScore.RankingID,
 		UserID:    userHighScore.UserID,
-		Score:     userHighScore.HighScore,
+		Score:     domain.Score(userHighScore.HighScore),
+		Timestamp: userHighScore.Timestamp,
 	}, nil
 }
 
+// ユーザーハイスコア保存結果
+type UserHighScoreUpsertRow struct {
+	PreviousScore *int64    `bun:"previous_score"`
+	HighScore     int64     `bun:"high_score"`
+	Timestamp     time.Time `bun:"timestamp"`
+}
+
 // ユーザーハイスコアを保存する
-func (r *UserHighScoreRepository) Store(ctx context.Context, rankingID int, userID int, score int) error {
-	// ユーザーハイスコア
-	userHighScore := new(UserHighScore)
+func (r *UserHighScoreRepository) Upsert(ctx context.Context, rankingID int, userID int, score domain.Score, sortOrder domain.SortOrder) (*domain.UserHighScoreUpsertResult, error) {
+	return r.UpsertAt(ctx, rankingID, userID, score, sortOrder, time.Now())
+}
 
-	// 既にスコア登録されているかを確認するクエリを投げる
-	err := r.db.NewSelect().
-		Model(&userHighScore).
-		Where("ranking_id = ? and user_id = ?", rankingID, userID).
-		Scan(ctx)
+// 登録日時を指定してユーザーハイスコアを保存する
+func (r *UserHighScoreRepository) UpsertAt(ctx context.Context, rankingID int, userID int, score domain.Score, sortOrder domain.SortOrder, timestamp time.Time) (*domain.UserHighScoreUpsertResult, error) {
+	// 登録日時 (カーソルでの比較が一致するよう、SQLに埋め込める精度のミリ秒に丸める)
+	timestamp = timestamp.UTC().Truncate(time.Millisecond)
+
+	// データベースの種類に応じた方法でアトミックに保存し、同一トランザクション内でハイスコアの分布の集計値を更新する
+	var row *UserHighScoreUpsertRow
+	err := runInTx(ctx, r.db, func(ctx context.Context, db bun.IDB) error {
+		var err error
+		if r.db.Dialect().Name() == dialect.MSSQL {
+			row, err = r.upsertWithMerge(ctx, rankingID, userID, score, sortOrder, timestamp)
+		} else {
+			row, err = r.upsertWithRowLock(ctx, rankingID, userID, score, sortOrder, timestamp)
+		}
+		if err != nil {
+			return err
+		}
+
+		// 登録した場合は加え、更新した場合は置き換える
+		if row.PreviousScore == nil {
+			return addScoreStats(ctx, db, rankingID, domain.Score(row.HighScore))
+		}
+		if row.HighScore != *row.PreviousScore {
+			return replaceScoreStats(ctx, db, rankingID, domain.Score(*row.PreviousScore), domain.Score(row.HighScore))
+		}
+		return nil
+	})
 
 	// エラーハンドリング
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		// データ取得時の予期せぬエラー
-		log.Printf("Failed to query user score: %v", err)
-		return err
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
 	}
 
-	// データがなければINSERT
-	if errors.Is(err, sql.ErrNoRows) {
-		userHighScore = &UserHighScore{
+	// 保存前のハイスコアから登録結果を判定する
+	var previous *domain.UserHighScore
+	var previousScore *domain.Score
+	if row.PreviousScore != nil {
+		previous = domain.NewUserHighScore(rankingID, userID, domain.Score(*row.PreviousScore))
+		previousScore = &previous.Score
+	}
+
+	// ドメインのユーザーハイスコア保存結果を返す
+	return &domain.UserHighScoreUpsertResult{
+		Outcome:       domain.DecideHighScoreOutcome(previous, score, sortOrder),
+		PreviousScore: previousScore,
+		HighScore: domain.UserHighScore{
 			RankingID: rankingID,
 			UserID:    userID,
-			HighScore: score,
-		}
+			Score:     domain.Score(row.HighScore),
+			Timestamp: row.Timestamp,
+		},
+	}, nil
+}
+
+// MERGE文で保存する (SQL Server)
+func (r *UserHighScoreRepository) upsertWithMerge(ctx context.Context, rankingID int, userID int, score domain.Score, sortOrder domain.SortOrder, timestamp time.Time) (*UserHighScoreUpsertRow, error) {
+	// ユーザーハイスコア保存結果
+	row := new(UserHighScoreUpsertRow)
+
+	// 未登録ならINSERT、並び順で既存のハイスコアを上回る場合のみUPDATEを1文で行う
+	// HOLDLOCKでキー範囲をロックし、同時に登録された場合の主キー重複や低いスコアでの上書きを防ぐ
+	err := dbFromContext(ctx, r.db).NewRaw(`
+		MERGE user_high_scores WITH (HOLDLOCK) AS target
+		USING (SELECT ? AS ranking_id, ? AS user_id, CAST(? AS BIGINT) AS high_score, CAST(? AS DATETIME2) AS timestamp) AS source
+		ON target.ranking_id = source.ranking_id AND target.user_id = source.user_id
+		WHEN MATCHED THEN UPDATE SET
+			target.high_score = CASE WHEN source.high_score ? target.high_score THEN source.high_score ELSE target.high_score END,
+			target.timestamp = CASE WHEN source.high_score ? target.high_score THEN source.timestamp ELSE target.timestamp END
+		WHEN NOT MATCHED THEN
+			INSERT (ranking_id, user_id, high_score, timestamp)
+			VALUES (source.ranking_id, source.user_id, source.high_score, source.timestamp)
+		OUTPUT deleted.high_score AS previous_score, inserted.high_score, inserted.timestamp;`,
+		rankingID, userID, int64(score), timestamp, beatsOperator(sortOrder), beatsOperator(sortOrder)).
+		Scan(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// 登録を試みてから既存の行をロックして保存する (PostgreSQL, SQLite)
+// 同時に登録された場合は一方の登録が何もせずに終わり、既存の行のロックを待ってから比較するため低いスコアで上書きしない
+func (r *UserHighScoreRepository) upsertWithRowLock(ctx context.Context, rankingID int, userID int, score domain.Score, sortOrder domain.SortOrder, timestamp time.Time) (*UserHighScoreUpsertRow, error) {
+	// ユーザーハイスコア保存結果
+	row := new(UserHighScoreUpsertRow)
 
-		// スコア登録クエリを実行
-		_, err = r.db.NewInsert().Model(userHighScore).Exec(ctx)
+	err := runInTx(ctx, r.db, func(ctx context.Context, db bun.IDB) error {
+		// 未登録なら登録する (既に登録されている場合は何もしない)
+		result, err := db.NewInsert().
+			Model(&UserHighScore{RankingID: rankingID, UserID: userID, HighScore: int64(score), Timestamp: timestamp}).
+			On("CONFLICT (ranking_id, user_id) DO NOTHING").
+			Exec(ctx)
 		if err != nil {
-			log.Printf("Error occurred: %v", err)
 			return err
 		}
-	} else {
-		// データがあればUPDATE
-		_, err := r.db.NewUpdate().
-			Table("user_high_scores").
-			Set("score = ?, timestamp = getdate()", score).
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			row.HighScore = int64(score)
+			row.Timestamp = timestamp
+			return nil
+		}
+
+		// 既存のハイスコアを取得する (PostgreSQLでは更新まで行ロックする。SQLiteは書き込みが直列のため不要)
+		current := new(UserHighScore)
+		q := db.NewSelect().Model(current).Where("ranking_id = ? AND user_id = ?", rankingID, userID)
+		if db.Dialect().Name() == dialect.PG {
+			q = q.For("UPDATE")
+		}
+		if err := q.Scan(ctx); err != nil {
+			return err
+		}
+		row.PreviousScore = &current.HighScore
+		row.HighScore = current.HighScore
+		row.Timestamp = current.Timestamp
+
+		// 並び順で既存のハイスコアを上回る場合のみ更新する
+		if !sortOrder.Beats(score, domain.Score(current.HighScore)) {
+			return nil
+		}
+		_, err = db.NewUpdate().
+			Model((*UserHighScore)(nil)).
+			Set("high_score = ?", int64(score)).
+			Set("timestamp = ?", timestamp).
 			Where("ranking_id = ? AND user_id = ?", rankingID, userID).
 			Exec(ctx)
-
 		if err != nil {
-			log.Printf("Error occurred: %v", err)
 			return err
 		}
+		row.HighScore = int64(score)
+		row.Timestamp = timestamp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// ユーザーの全てのランキングのハイスコアを取得する
+func (r *UserHighScoreRepository) FindAllByUserID(ctx context.Context, userID int) ([]domain.UserHighScore, error) {
+	// ユーザーハイスコアスライス
+	var userHighScores []UserHighScore
+
+	// クエリ実行
+	err := dbFromContext(ctx, r.db).NewSelect().Model(&userHighScores).Where("user_id = ?", userID).OrderExpr("ranking_id ASC").Scan(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのユーザーハイスコア一覧を返す
+	highScores := make([]domain.UserHighScore, 0, len(userHighScores))
+	for _, userHighScore := range userHighScores {
+		highScores = append(highScores, domain.UserHighScore{
+			RankingID: userHighScore.RankingID,
+			UserID:    userHighScore.UserID,
+			Score:     domain.Score(userHighScore.HighScore),
+			Timestamp: userHighScore.Timestamp,
+		})
+	}
+	return highScores, nil
+}
+
+// ランキングのハイスコアの分布を取得する (ハイスコアの保存ごとに更新した集計値を読む)
+func (r *UserHighScoreRepository) FetchScoreStats(ctx context.Context, rankingID int) (domain.ScoreStats, error) {
+	// クエリ実行
+	moments, err := findScoreStats(ctx, dbFromContext(ctx, r.db), rankingID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return domain.ScoreStats{}, err
 	}
 
-	return nil
+	// ドメインの分布を返す
+	return moments.Stats(), nil
+}
+
+// ユーザーハイスコア削除前の行
+type UserHighScoreDeleteRow struct {
+	HighScore int64      `bun:"high_score"`
+	DeletedAt *time.Time `bun:"deleted_at"`
+}
+
+// ユーザーハイスコアを削除する
+func (r *UserHighScoreRepository) Delete(ctx context.Context, rankingID int, userID int) error {
+	err := runInTx(ctx, r.db, func(ctx context.Context, db bun.IDB) error {
+		// 削除するハイスコアとユーザーの論理削除日時を取得する
+		var rows []UserHighScoreDeleteRow
+		err := db.NewSelect().
+			TableExpr("user_high_scores AS uhs").
+			Join("JOIN users ON users.id = uhs.user_id").
+			ColumnExpr("uhs.high_score, users.deleted_at").
+			Where("uhs.ranking_id = ? AND uhs.user_id = ?", rankingID, userID).
+			Scan(ctx, &rows)
+		if err != nil {
+			return err
+		}
+
+		// 削除対象が存在しない場合はNotFoundエラーを返す
+		if len(rows) == 0 {
+			return domain.NewNotFoundError("user_high_score_not_found", fmt.Sprintf("ハイスコアが登録されていません。ランキングID: %d, ユーザーID: %d", rankingID, userID))
+		}
+
+		// ユーザーハイスコア削除クエリを実行 (送信履歴は残す)
+		_, err = db.NewDelete().
+			Model((*UserHighScore)(nil)).
+			Where("ranking_id = ? AND user_id = ?", rankingID, userID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// 論理削除したユーザーのハイスコアは分布の集計値に含めていない
+		if rows[0].DeletedAt != nil {
+			return nil
+		}
+		return removeScoreStats(ctx, db, rankingID, domain.Score(rows[0].HighScore))
+	})
+
+	// エラーハンドリング
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		log.Printf("Error occurred: %v", err)
+	}
+	return err
 }
@@ -0,0 +1,162 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 複数のユーザーのスコアが同時に大量に登録されても、更新を失わずにユーザーごとの最大値だけが残る
+func TestUserHighScoreRepositoryUpsertConcurrently(t *testing.T) {
+	db := newConcurrentTestDB(t)
+	ctx := context.Background()
+
+	// テスト用のゲーム、ランキングとユーザーを登録
+	suffix := time.Now().UnixNano()
+	gameName, _ := domain.NewGameName(fmt.Sprintf("concurrent-%d", suffix))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName(fmt.Sprintf("concurrent-%d", suffix))
+	ranking, err := NewRankingRepository(db).Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}})
+	require.NoError(t, err)
+	const users = 5
+	userIDs := make([]int, users)
+	for i := range userIDs {
+		userName, _ := domain.NewUserName(fmt.Sprintf("concurrent-%d-%d", suffix, i))
+		user, err := NewUserRepository(db).Create(ctx, userName)
+		require.NoError(t, err)
+		userIDs[i] = user.ID
+	}
+
+	repository := NewUserHighScoreRepository(db)
+
+	// ユーザーごとに100件のスコアを全て並列に登録する
+	const submissions = 100
+	type upsertResult struct {
+		userID int
+		result *domain.UserHighScoreUpsertResult
+	}
+	results := make(chan upsertResult, users*submissions)
+	errs := make(chan error, users*submissions)
+	var wg sync.WaitGroup
+	for _, userID := range userIDs {
+		for i := 1; i <= submissions; i++ {
+			wg.Add(1)
+			go func(userID int, score domain.Score) {
+				defer wg.Done()
+				result, err := repository.Upsert(ctx, ranking.ID, userID, score, domain.SortOrderDesc)
+				if err != nil {
+					errs <- err
+					return
+				}
+				results <- upsertResult{userID: userID, result: result}
+			}(userID, domain.Score(i))
+		}
+	}
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	// 主キー重複などのエラーが発生しないこと
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	// ユーザーごとに新規登録は1件だけで、前回値を下回る更新はないこと
+	created := map[int]int{}
+	for r := range results {
+		if r.result.Outcome == domain.HighScoreCreated {
+			created[r.userID]++
+			continue
+		}
+		require.NotNil(t, r.result.PreviousScore)
+		assert.GreaterOrEqual(t, r.result.HighScore.Score, *r.result.PreviousScore, "Expected high score never to decrease")
+	}
+	for _, userID := range userIDs {
+		assert.Equal(t, 1, created[userID], "Expected exactly one created outcome per user")
+
+		// 最終的に最大のスコアが残ること
+		userHighScore, err := repository.Find(ctx, ranking.ID, userID)
+		require.NoError(t, err)
+		require.NotNil(t, userHighScore)
+		assert.Equal(t, domain.Score(submissions), userHighScore.Score, "Expected max score to win")
+	}
+
+	// ハイスコアの分布の集計値も更新を失っていないこと
+	stats, err := repository.FetchScoreStats(ctx, ranking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, users, stats.Count)
+	assert.InDelta(t, float64(submissions), stats.Mean, 1e-6)
+	assert.InDelta(t, 0, stats.StdDev, 1e-6)
+}
+
+// ハイスコアの分布を集計する
+func TestUserHighScoreRepositoryFetchScoreStats(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repository := NewUserHighScoreRepository(db)
+
+	// 2, 4, 4, 4, 5, 5, 7, 9 の平均は5、母標準偏差は2 (9は1から更新して集計値を置き換える)
+	rankingID, userIDs := newRankingWithScores(t, db, domain.SortOrderDesc, 2, 4, 4, 4, 5, 5, 7, 1)
+	_, err := repository.Upsert(ctx, rankingID, userIDs[7], 9, domain.SortOrderDesc)
+	require.NoError(t, err)
+	_, err = repository.Upsert(ctx, rankingID, userIDs[7], 3, domain.SortOrderDesc)
+	require.NoError(t, err)
+	stats, err := repository.FetchScoreStats(ctx, rankingID)
+	require.NoError(t, err)
+	assert.Equal(t, 8, stats.Count)
+	assert.InDelta(t, 5.0, stats.Mean, 1e-9)
+	assert.InDelta(t, 2.0, stats.StdDev, 1e-9)
+
+	// ハイスコアの削除とユーザーの論理削除で取り除き、復元で戻す (4, 4, 4, 5, 5, 7 の平均は29/6)
+	require.NoError(t, repository.Delete(ctx, rankingID, userIDs[0]))
+	userRepository := NewUserRepository(db)
+	require.NoError(t, userRepository.Delete(ctx, userIDs[7]))
+	stats, err = repository.FetchScoreStats(ctx, rankingID)
+	require.NoError(t, err)
+	assert.Equal(t, 6, stats.Count)
+	assert.InDelta(t, 29.0/6, stats.Mean, 1e-9)
+	_, err = userRepository.Restore(ctx, userIDs[7])
+	require.NoError(t, err)
+	stats, err = repository.FetchScoreStats(ctx, rankingID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, stats.Count)
+	assert.InDelta(t, 38.0/7, stats.Mean, 1e-9)
+
+	// ハイスコアがない場合は空
+	stats, err = repository.FetchScoreStats(ctx, rankingID+1000000)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ScoreStats{}, stats)
+}
+
+// 登録日時を指定した保存は、指定した日時を同点の順位付けに使う
+func TestUserHighScoreRepositoryUpsertAt(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repository := NewUserHighScoreRepository(db)
+	rankingID, userIDs := newRankingWithScores(t, db, domain.SortOrderDesc, 100)
+
+	// 既存のハイスコアを上回る場合は指定した日時で更新する
+	achievedAt := time.Date(2026, 1, 2, 3, 4, 5, 678000000, time.UTC)
+	result, err := repository.UpsertAt(ctx, rankingID, userIDs[0], 200, domain.SortOrderDesc, achievedAt)
+	require.NoError(t, err)
+	assert.Equal(t, domain.HighScoreImproved, result.Outcome)
+	stored, err := repository.Find(ctx, rankingID, userIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, domain.Score(200), stored.Score)
+	assert.True(t, achievedAt.Equal(stored.Timestamp))
+
+	// 上回らない場合は日時も変えない
+	result, err = repository.UpsertAt(ctx, rankingID, userIDs[0], 150, domain.SortOrderDesc, achievedAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, domain.HighScoreUnchanged, result.Outcome)
+	stored, err = repository.Find(ctx, rankingID, userIDs[0])
+	require.NoError(t, err)
+	assert.True(t, achievedAt.Equal(stored.Timestamp))
+}
@@ -2,27 +2,34 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
 	"practice-go-game-ranking/pkg/ranking/usecase"
+	"slices"
+	"time"
 
 	"github.com/uptrace/bun"
 )
 
 // ユーザーランク
 type UserRank struct {
-	UserID   int    `bun:"user_id"`
-	UserName string `bun:"user_name"`
-	Rank     int    `bun:"rank"`
-	Score    int    `bun:"score"`
+	UserID     int       `bun:"user_id"`
+	UserName   string    `bun:"user_name"`
+	Rank       int       `bun:"rank"`
+	Score      int64     `bun:"score"`
+	AchievedAt time.Time `bun:"achieved_at"`
 }
 
 // ユーザーランキングクエリサービス
 type UserRankingQueryService struct {
-	db *bun.DB
+	db bun.IDB
 }
 
 // リポジトリを生成する
-func NewUserRankingQueryService(bun *bun.DB) *UserRankingQueryService {
+func NewUserRankingQueryService(bun bun.IDB) *UserRankingQueryService {
 	return &UserRankingQueryService{
 		db: bun,
 	}
@@ -30,29 +37,40 @@ func NewUserRankingQueryService(bun *bun.DB) *UserRankingQueryService {
 
 // ユーザーランキングを取得する
 func (userRankingQueryService *UserRankingQueryService) FetchUserRanking(ctx context.Context, query usecase.UserRankingQuery) (*usecase.UserRankingDto, error) {
-	// ランキング
-	ranking := new(Ranking)
-
-	// ランキング取得クエリ実行
-	err := userRankingQueryService.db.NewSelect().Model(&ranking).Where("id = ?", query.RankingID).Scan(ctx)
+	db := dbFromContext(ctx, userRankingQueryService.db)
 
-	// エラーハンドリング
+	// ランキングを取得
+	ranking, err := findRanking(ctx, db, query.RankingID)
 	if err != nil {
-		log.Printf("Error occurred: %v", err)
 		return nil, err
 	}
 
-	// ユーザーランクスライス
+	// ユーザーハイスコアランキング取得クエリ
+	sortOrder := ranking.SortOrder
+	q := db.NewSelect().
+		TableExpr("(?) AS ranked", rankedUserHighScores(db, query.RankingID, sortOrder)).
+		ColumnExpr("ranked.*")
+
+	// 上位から下位に向かって取得する場合はカーソルより下位を、逆の場合は上位を取得する
+	// 並び順はホワイトリストの値のみをSQLに埋め込む
+	cursor := query.Cursor
+	if query.FetchesDownward() {
+		if cursor != nil {
+			q = whereRankedBelow(q, sortOrder, int64(cursor.Score), cursor.Timestamp, cursor.UserID)
+		}
+		q = q.OrderExpr("ranked.rank ASC")
+	} else {
+		if cursor != nil {
+			q = whereRankedAbove(q, sortOrder, int64(cursor.Score), cursor.Timestamp, cursor.UserID)
+		}
+		q = q.OrderExpr("ranked.rank DESC")
+	}
+
+	// ユーザーランクスライス (続きの有無を判定するため1件多く取得する)
 	var userRanks []UserRank
 
 	// ユーザーハイスコアランキング取得クエリ実行
-	err = userRankingQueryService.db.NewSelect().
-		Table("user_high_scores").
-		Join("JOIN users ON user_high_scores.user_id = users.id").
-		Column("users.id AS user_id", "users.name AS user_name", "user_high_scores.score", "ROW_NUMBER() OVER (ORDER BY user_high_scores.ここはqueryから撮りたい) AS rank").
-		Order("user_high_scores.rank DESC").
-		Where("ranking_id = ?", query.RankingID).
-		Scan(ctx, userRanks)
+	err = q.Limit(query.Limit+1).Scan(ctx, &userRanks)
 
 	// エラーハンドリング
 	if err != nil {
@@ -60,24 +78,278 @@ func (userRankingQueryService *UserRankingQueryService) FetchUserRanking(ctx con
 		return nil, err
 	}
 
+	// 取得件数を超えた分は続きの有無の判定に使う
+	hasMore := len(userRanks) > query.Limit
+	if hasMore {
+		userRanks = userRanks[:query.Limit]
+	}
+
+	// 前のページを取得した場合は逆順で取得しているため並べ替える
+	direction := domain.PageNext
+	if cursor != nil {
+		direction = cursor.Direction
+	}
+	if direction == domain.PagePrev {
+		slices.Reverse(userRanks)
+	}
+
 	// ユースケース層のユーザーランク構造体にマッピング
-	var usecaseUserRanks []usecase.UserRankDto
+	usecaseUserRanks := make([]usecase.UserRankDto, 0, len(userRanks))
 	for _, userRank := range userRanks {
-		usecaseUserRanks = append(usecaseUserRanks, usecase.UserRankDto{
-			UserID:   userRank.UserID,
-			UserName: userRank.UserName,
-			Rank:     userRank.Rank,
-			Score:    userRank.Score,
-		})
+		usecaseUserRanks = append(usecaseUserRanks, toUserRankDto(userRank, ranking.ScoreType))
 	}
 
 	// ユースケース層のユーザーランキング構造体にマッピング
 	usecaseUserRanking := &usecase.UserRankingDto{
 		RankingID:   ranking.ID,
-		RankingName: ranking.Name,
+		RankingName: ranking.Name.Value,
 		UserRanks:   usecaseUserRanks,
 	}
+	usecaseUserRanking.HasNext, usecaseUserRanking.HasPrev = usecase.PageLinks(hasMore, cursor != nil, direction)
 
 	// ユーザーランキングを返却する
 	return usecaseUserRanking, nil
 }
+
+// ユーザーの前後のユーザーランキングを取得する
+func (userRankingQueryService *UserRankingQueryService) FetchUserRankingAround(ctx context.Context, query usecase.UserRankingAroundQuery) (*usecase.UserRankingAroundDto, error) {
+	db := dbFromContext(ctx, userRankingQueryService.db)
+
+	// ランキングを取得
+	ranking, err := findRanking(ctx, db, query.RankingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 基準となるユーザーの順位を取得
+	sortOrder := ranking.SortOrder
+	me, err := userRankingQueryService.fetchUserStanding(ctx, query.RankingID, sortOrder, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 上位のユーザーランク (近い順に取得する)
+	var above []UserRank
+	if query.Before > 0 {
+		q := db.NewSelect().
+			TableExpr("(?) AS ranked", rankedUserHighScores(db, query.RankingID, sortOrder)).
+			ColumnExpr("ranked.*")
+		err = whereRankedAbove(q, sortOrder, me.Score, me.AchievedAt, me.UserID).
+			OrderExpr("ranked.rank DESC").
+			Limit(query.Before).
+			Scan(ctx, &above)
+		if err != nil {
+			log.Printf("Error occurred: %v", err)
+			return nil, err
+		}
+	}
+
+	// 下位のユーザーランク (近い順に取得する)
+	var below []UserRank
+	if query.After > 0 {
+		q := db.NewSelect().
+			TableExpr("(?) AS ranked", rankedUserHighScores(db, query.RankingID, sortOrder)).
+			ColumnExpr("ranked.*")
+		err = whereRankedBelow(q, sortOrder, me.Score, me.AchievedAt, me.UserID).
+			OrderExpr("ranked.rank ASC").
+			Limit(query.After).
+			Scan(ctx, &below)
+		if err != nil {
+			log.Printf("Error occurred: %v", err)
+			return nil, err
+		}
+	}
+
+	// 上位、自分、下位の順にランクの昇順で並べる
+	slices.Reverse(above)
+	usecaseUserRanks := make([]usecase.UserRankDto, 0, len(above)+1+len(below))
+	for _, userRank := range above {
+		usecaseUserRanks = append(usecaseUserRanks, toUserRankDto(userRank, ranking.ScoreType))
+	}
+	usecaseUserRanks = append(usecaseUserRanks, toUserRankDto(me.UserRank, ranking.ScoreType))
+	for _, userRank := range below {
+		usecaseUserRanks = append(usecaseUserRanks, toUserRankDto(userRank, ranking.ScoreType))
+	}
+
+	// ユースケース層の構造体にマッピング
+	return &usecase.UserRankingAroundDto{
+		RankingID:   ranking.ID,
+		RankingName: ranking.Name.Value,
+		UserID:      me.UserID,
+		Rank:        me.Rank,
+		TotalUsers:  me.TotalUsers,
+		UserRanks:   usecaseUserRanks,
+	}, nil
+}
+
+// ランキングにおけるユーザーの現在のランクを取得する
+func (userRankingQueryService *UserRankingQueryService) FetchUserRank(ctx context.Context, rankingID int, userID int) (*usecase.UserRankDto, error) {
+	// ランキングを取得 (並び順をランク付けに使う)
+	ranking, err := findRanking(ctx, dbFromContext(ctx, userRankingQueryService.db), rankingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ユーザーの順位
+	userStanding, err := userRankingQueryService.fetchUserStanding(ctx, rankingID, ranking.SortOrder, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ユースケース層のユーザーランク構造体にマッピング
+	userRankDto := toUserRankDto(userStanding.UserRank, ranking.ScoreType)
+	return &userRankDto, nil
+}
+
+// ランキングにおけるユーザーのランクと参加人数、パーセンタイルを取得する
+func (userRankingQueryService *UserRankingQueryService) FetchUserStanding(ctx context.Context, rankingID int, userID int) (*usecase.UserStandingDto, error) {
+	// ランキングを取得 (並び順をランク付けに使う)
+	ranking, err := findRanking(ctx, dbFromContext(ctx, userRankingQueryService.db), rankingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ユーザーの順位
+	userStanding, err := userRankingQueryService.fetchUserStanding(ctx, rankingID, ranking.SortOrder, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ユースケース層のユーザー順位構造体にマッピング
+	return &usecase.UserStandingDto{
+		RankingID:  rankingID,
+		UserID:     userStanding.UserID,
+		UserName:   userStanding.UserName,
+		Rank:       userStanding.Rank,
+		Score:      usecase.NewScoreDto(domain.Score(userStanding.Score), ranking.ScoreType),
+		AchievedAt: userStanding.AchievedAt,
+		TotalUsers: userStanding.TotalUsers,
+		Percentile: usecase.CalculatePercentile(userStanding.Rank, userStanding.TotalUsers),
+	}, nil
+}
+
+// ユーザーの順位
+type UserStanding struct {
+	UserRank
+	TotalUsers int `bun:"total_users"`
+}
+
+// ユーザーのランクと参加人数を取得する (論理削除したユーザーは数えず、自分が論理削除されている場合は見つからない)
+// ランキング全体を読み込まず、並び順で自分より上位の件数と全体の件数を数えてランクを求める
+func (userRankingQueryService *UserRankingQueryService) fetchUserStanding(ctx context.Context, rankingID int, sortOrder domain.SortOrder, userID int) (*UserStanding, error) {
+	// ユーザーの順位
+	userStanding := new(UserStanding)
+
+	// クエリ実行
+	err := dbFromContext(ctx, userRankingQueryService.db).NewRaw(`
+		SELECT me.user_id, users.name AS user_name, me.high_score AS score, me.timestamp AS achieved_at,
+			(SELECT COUNT(*) FROM user_high_scores AS other
+				JOIN users AS other_users ON other_users.id = other.user_id
+				WHERE other.ranking_id = me.ranking_id AND other_users.deleted_at IS NULL
+				AND (other.high_score ? me.high_score
+					OR (other.high_score = me.high_score AND other.timestamp < me.timestamp)
+					OR (other.high_score = me.high_score AND other.timestamp = me.timestamp AND other.user_id < me.user_id))
+			) + 1 AS rank,
+			(SELECT COUNT(*) FROM user_high_scores AS other
+				JOIN users AS other_users ON other_users.id = other.user_id
+				WHERE other.ranking_id = me.ranking_id AND other_users.deleted_at IS NULL
+			) AS total_users
+		FROM user_high_scores AS me
+		JOIN users ON users.id = me.user_id
+		WHERE me.ranking_id = ? AND me.user_id = ? AND users.deleted_at IS NULL`, beatsOperator(sortOrder), rankingID, userID).
+		Scan(ctx, userStanding)
+
+	// ハイスコアが未登録の場合はNotFoundエラーを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewNotFoundError("user_high_score_not_found", fmt.Sprintf("ハイスコアが登録されていません。ランキングID: %d, ユーザーID: %d", rankingID, userID))
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	return userStanding, nil
+}
+
+// ランキングを取得する (存在しない場合はNotFoundエラー)
+func findRanking(ctx context.Context, db bun.IDB, rankingID int) (*domain.Ranking, error) {
+	// ランキング
+	ranking := new(Ranking)
+
+	// ランキング取得クエリ実行
+	err := db.NewSelect().Model(ranking).Where("id = ?", rankingID).Scan(ctx)
+
+	// 存在しない場合はNotFoundエラーを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewNotFoundError("ranking_not_found", fmt.Sprintf("ランキングが存在しません。ランキングID: %d", rankingID))
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
+
+	// ドメインのランキングを返す
+	return toDomainRanking(ranking)
+}
+
+// ランキングのハイスコアにランクを付けるサブクエリを生成する (論理削除したユーザーのハイスコアは除外する)
+// 並び順でスコアが上位の順、同点の場合は登録日時が古い順、それも同じ場合はユーザーIDが小さい順にランク付けする
+func rankedUserHighScores(db bun.IDB, rankingID int, sortOrder domain.SortOrder) *bun.SelectQuery {
+	return db.NewSelect().
+		TableExpr("user_high_scores AS uhs").
+		Join("JOIN users ON users.id = uhs.user_id").
+		ColumnExpr("ROW_NUMBER() OVER (ORDER BY uhs.high_score ?, uhs.timestamp ASC, uhs.user_id ASC) AS rank", scoreOrderDirection(sortOrder)).
+		ColumnExpr("uhs.user_id, users.name AS user_name, uhs.high_score AS score, uhs.timestamp AS achieved_at").
+		Where("uhs.ranking_id = ? AND users.deleted_at IS NULL", rankingID)
+}
+
+// ユースケース層のユーザーランク構造体にマッピングする
+func toUserRankDto(userRank UserRank, scoreType domain.ScoreType) usecase.UserRankDto {
+	return usecase.UserRankDto{
+		UserID:     userRank.UserID,
+		UserName:   userRank.UserName,
+		Rank:       userRank.Rank,
+		Score:      usecase.NewScoreDto(domain.Score(userRank.Score), scoreType),
+		AchievedAt: userRank.AchievedAt,
+	}
+}
+
+// 基準のハイスコアより上位に絞り込む (ランク付けと同じ並び順で比較する)
+func whereRankedAbove(q *bun.SelectQuery, sortOrder domain.SortOrder, score int64, timestamp time.Time, userID int) *bun.SelectQuery {
+	return q.Where("(ranked.score ? ? OR (ranked.score = ? AND ranked.achieved_at < ?) OR (ranked.score = ? AND ranked.achieved_at = ? AND ranked.user_id < ?))",
+		beatsOperator(sortOrder), score, score, timestamp, score, timestamp, userID)
+}
+
+// 基準のハイスコアより下位に絞り込む (ランク付けと同じ並び順で比較する)
+func whereRankedBelow(q *bun.SelectQuery, sortOrder domain.SortOrder, score int64, timestamp time.Time, userID int) *bun.SelectQuery {
+	return q.Where("(ranked.score ? ? OR (ranked.score = ? AND ranked.achieved_at > ?) OR (ranked.score = ? AND ranked.achieved_at = ? AND ranked.user_id > ?))",
+		losesOperator(sortOrder), score, score, timestamp, score, timestamp, userID)
+}
+
+// 並び順でスコアが上位であることを表す比較演算子を返す (ホワイトリストの値のみをSQLに埋め込む)
+func beatsOperator(sortOrder domain.SortOrder) bun.Safe {
+	if sortOrder == domain.SortOrderAsc {
+		return bun.Safe("<")
+	}
+	return bun.Safe(">")
+}
+
+// 並び順でスコアが下位であることを表す比較演算子を返す
+func losesOperator(sortOrder domain.SortOrder) bun.Safe {
+	if sortOrder == domain.SortOrderAsc {
+		return bun.Safe(">")
+	}
+	return bun.Safe("<")
+}
+
+// 並び順に応じたスコアの並べ方を返す
+func scoreOrderDirection(sortOrder domain.SortOrder) bun.Safe {
+	if sortOrder == domain.SortOrderAsc {
+		return bun.Safe("ASC")
+	}
+	return bun.Safe("DESC")
+}
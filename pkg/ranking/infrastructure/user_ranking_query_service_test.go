@@ -0,0 +1,227 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+// 並び順とスコアの一覧でランキングを作成し、ランキングIDと登録順のユーザーIDを返す
+func newRankingWithScores(t *testing.T, db *bun.DB, sortOrder domain.SortOrder, scores ...domain.Score) (int, []int) {
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+	gameName, _ := domain.NewGameName(fmt.Sprintf("ranked-%d", suffix))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName(fmt.Sprintf("ranked-%d", suffix))
+	ranking, err := NewRankingRepository(db).Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: sortOrder, ScoreType: domain.ScoreType{Kind: domain.ScoreKindInteger}})
+	require.NoError(t, err)
+
+	userIDs := make([]int, 0, len(scores))
+	for i, score := range scores {
+		userName, _ := domain.NewUserName(fmt.Sprintf("ranked-%d-%d", suffix, i))
+		user, err := NewUserRepository(db).Create(ctx, userName)
+		require.NoError(t, err)
+		_, err = NewUserHighScoreRepository(db).Upsert(ctx, ranking.ID, user.ID, score, sortOrder)
+		require.NoError(t, err)
+		userIDs = append(userIDs, user.ID)
+	}
+	return ranking.ID, userIDs
+}
+
+// ランクを取り出す
+func ranksOf(userRanks []usecase.UserRankDto) []int {
+	ranks := make([]int, 0, len(userRanks))
+	for _, userRank := range userRanks {
+		ranks = append(ranks, userRank.Rank)
+	}
+	return ranks
+}
+
+// カーソルで前後のページを行き来できる
+func TestFetchUserRankingPaging(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	rankingID, userIDs := newRankingWithScores(t, db, domain.SortOrderDesc, 10, 50, 30, 50, 20)
+	queryService := NewUserRankingQueryService(db)
+
+	// 1ページ目 (同点は先に登録したユーザーが上位)
+	query, err := usecase.NewUserRankingQuery(rankingID, "rank_asc", 2, nil)
+	require.NoError(t, err)
+	first, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ranksOf(first.UserRanks))
+	assert.Equal(t, userIDs[1], first.UserRanks[0].UserID)
+	assert.True(t, first.HasNext)
+	assert.False(t, first.HasPrev)
+
+	// 2ページ目
+	last := first.UserRanks[1]
+	query.Cursor = &usecase.UserRankingCursor{Score: last.Score.Value, Timestamp: last.AchievedAt, UserID: last.UserID, Direction: domain.PageNext}
+	second, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, ranksOf(second.UserRanks))
+	assert.True(t, second.HasNext)
+	assert.True(t, second.HasPrev)
+
+	// 2ページ目から前のページに戻る
+	head := second.UserRanks[0]
+	query.Cursor = &usecase.UserRankingCursor{Score: head.Score.Value, Timestamp: head.AchievedAt, UserID: head.UserID, Direction: domain.PagePrev}
+	back, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ranksOf(back.UserRanks))
+	assert.False(t, back.HasPrev)
+
+	// 下位から並べる
+	query, err = usecase.NewUserRankingQuery(rankingID, "rank_desc", 2, nil)
+	require.NoError(t, err)
+	desc, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 4}, ranksOf(desc.UserRanks))
+	assert.True(t, desc.HasNext)
+}
+
+// ユーザーの順位と前後のユーザーランク
+func TestFetchUserStandingAndAround(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	rankingID, userIDs := newRankingWithScores(t, db, domain.SortOrderDesc, 50, 40, 30, 20, 10)
+	queryService := NewUserRankingQueryService(db)
+
+	standing, err := queryService.FetchUserStanding(ctx, rankingID, userIDs[1])
+	require.NoError(t, err)
+	assert.Equal(t, 2, standing.Rank)
+	assert.Equal(t, 5, standing.TotalUsers)
+	assert.Equal(t, 75.0, standing.Percentile)
+
+	// ランキングの端では存在する分だけを返す
+	query, err := usecase.NewUserRankingAroundQuery(rankingID, userIDs[1], nil, nil)
+	require.NoError(t, err)
+	around, err := queryService.FetchUserRankingAround(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ranksOf(around.UserRanks))
+
+	// ハイスコアが未登録の場合はNotFound
+	_, err = queryService.FetchUserStanding(ctx, rankingID, userIDs[4]+1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// スコアが小さいほど上位のランキング
+func TestFetchUserRankingAscending(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	rankingID, userIDs := newRankingWithScores(t, db, domain.SortOrderAsc, 30, 10, 20, 10)
+	queryService := NewUserRankingQueryService(db)
+
+	// 小さい順に並び、同点は先に登録したユーザーが上位
+	query, err := usecase.NewUserRankingQuery(rankingID, "rank_asc", 2, nil)
+	require.NoError(t, err)
+	first, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{userIDs[1], userIDs[3]}, []int{first.UserRanks[0].UserID, first.UserRanks[1].UserID})
+
+	// カーソルの続きも同じ並び順で取得する
+	last := first.UserRanks[1]
+	query.Cursor = &usecase.UserRankingCursor{Score: last.Score.Value, Timestamp: last.AchievedAt, UserID: last.UserID, Direction: domain.PageNext}
+	second, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, ranksOf(second.UserRanks))
+	assert.Equal(t, []int{userIDs[2], userIDs[0]}, []int{second.UserRanks[0].UserID, second.UserRanks[1].UserID})
+
+	standing, err := queryService.FetchUserStanding(ctx, rankingID, userIDs[2])
+	require.NoError(t, err)
+	assert.Equal(t, 3, standing.Rank)
+
+	// 小さいスコアで更新するとランクが上がり、大きいスコアでは更新しない
+	repository := NewUserHighScoreRepository(db)
+	result, err := repository.Upsert(ctx, rankingID, userIDs[0], 5, domain.SortOrderAsc)
+	require.NoError(t, err)
+	assert.Equal(t, domain.HighScoreImproved, result.Outcome)
+	result, err = repository.Upsert(ctx, rankingID, userIDs[0], 50, domain.SortOrderAsc)
+	require.NoError(t, err)
+	assert.Equal(t, domain.HighScoreUnchanged, result.Outcome)
+	assert.Equal(t, domain.Score(5), result.HighScore.Score)
+	rank, err := queryService.FetchUserRank(ctx, rankingID, userIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, 1, rank.Rank)
+}
+
+// 固定小数点数のスコアは32bitを超える値も保存でき、桁数分の表記で返す
+func TestFetchUserRankingDecimal(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// 小数点以下3桁で0以上のランキングを登録
+	suffix := time.Now().UnixNano()
+	gameName, _ := domain.NewGameName(fmt.Sprintf("decimal-%d", suffix))
+	game, err := NewGameRepository(db).Create(ctx, gameName)
+	require.NoError(t, err)
+	rankingName, _ := domain.NewRankingName(fmt.Sprintf("decimal-%d", suffix))
+	scoreType := domain.ScoreType{Kind: domain.ScoreKindDecimal, Scale: 3}
+	scoreRules, err := domain.NewScoreRules(scoreType, "0", "", "0.001")
+	require.NoError(t, err)
+	ranking, err := NewRankingRepository(db).Create(ctx, &domain.Ranking{GameID: game.ID, Name: rankingName, SortOrder: domain.SortOrderDesc, ScoreType: scoreType, ScoreRules: scoreRules})
+	require.NoError(t, err)
+	found, err := NewRankingRepository(db).FindByID(ctx, ranking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, scoreType, found.ScoreType)
+	assert.Equal(t, scoreRules, found.ScoreRules)
+
+	// 32bitを超える値と最小単位だけ小さい値を登録
+	for i, text := range []string{"3000000.123", "3000000.122", "0.001"} {
+		score, err := scoreType.Parse(text)
+		require.NoError(t, err)
+		userName, _ := domain.NewUserName(fmt.Sprintf("decimal-%d-%d", suffix, i))
+		user, err := NewUserRepository(db).Create(ctx, userName)
+		require.NoError(t, err)
+		_, err = NewUserHighScoreRepository(db).Upsert(ctx, ranking.ID, user.ID, score, domain.SortOrderDesc)
+		require.NoError(t, err)
+	}
+
+	query, err := usecase.NewUserRankingQuery(ranking.ID, "rank_asc", 10, nil)
+	require.NoError(t, err)
+	userRanking, err := NewUserRankingQueryService(db).FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	scores := make([]string, 0, len(userRanking.UserRanks))
+	for _, userRank := range userRanking.UserRanks {
+		scores = append(scores, userRank.Score.String())
+	}
+	assert.Equal(t, []string{"3000000.123", "3000000.122", "0.001"}, scores)
+}
+
+// 論理削除したユーザーはランク付けと参加人数から除外される
+func TestFetchUserRankingExcludesDeletedUsers(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	rankingID, userIDs := newRankingWithScores(t, db, domain.SortOrderDesc, 50, 40, 30)
+	queryService := NewUserRankingQueryService(db)
+	require.NoError(t, NewUserRepository(db).Delete(ctx, userIDs[0]))
+
+	query, err := usecase.NewUserRankingQuery(rankingID, "", 0, nil)
+	require.NoError(t, err)
+	userRanking, err := queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ranksOf(userRanking.UserRanks))
+	assert.Equal(t, userIDs[1], userRanking.UserRanks[0].UserID)
+
+	standing, err := queryService.FetchUserStanding(ctx, rankingID, userIDs[2])
+	require.NoError(t, err)
+	assert.Equal(t, 2, standing.Rank)
+	assert.Equal(t, 2, standing.TotalUsers)
+	_, err = queryService.FetchUserStanding(ctx, rankingID, userIDs[0])
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// 復元すると元のハイスコアで戻る
+	_, err = NewUserRepository(db).Restore(ctx, userIDs[0])
+	require.NoError(t, err)
+	standing, err = queryService.FetchUserStanding(ctx, rankingID, userIDs[2])
+	require.NoError(t, err)
+	assert.Equal(t, 3, standing.Rank)
+}
@@ -2,6 +2,9 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"practice-go-game-ranking/pkg/ranking/domain"
 	"time"
@@ -11,19 +14,23 @@ import (
 
 // ユーザー
 type User struct {
-	ID        int       `bun:"id,pk,autoincrement"`
-	Name      string    `bun:"name"`
-	CreatedAt time.Time `bun:"created_at,nullzero,default:CURRENT_TIMESTAMP"`
-	UpdatedAt time.Time `bun:"updated_at,nullzero,default:CURRENT_TIMESTAMP"`
+	ID        int        `bun:"id,pk,autoincrement"`
+	Name      string     `bun:"name"`
+	CreatedAt time.Time  `bun:"created_at,nullzero,default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `bun:"updated_at,nullzero,default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `bun:"deleted_at"`
+
+	// シャドウバンした日時
+	ShadowBannedAt *time.Time `bun:"shadow_banned_at"`
 }
 
 // ユーザーリポジトリ
 type UserRepository struct {
-	db *bun.DB
+	db bun.IDB
 }
 
 // リポジトリを生成する
-func NewUserRepository(bun *bun.DB) *UserRepository {
+func NewUserRepository(bun bun.IDB) *UserRepository {
 	return &UserRepository{
 		db: bun,
 	}
@@ -34,18 +41,14 @@ func (r *UserRepository) FindByID(ctx context.Context, id int) (*domain.User, er
 	// ユーザー
 	user := new(User)
 
-	// クエリ実行
-	err := r.db.NewSelect().Model(&user).Where("id = ?", id).Scan(ctx)
+	// クエリ実行 (論理削除したユーザーは除外する)
+	err := dbFromContext(ctx, r.db).NewSelect().Model(user).Where("id = ? AND deleted_at IS NULL", id).Scan(ctx)
 
-	// エラーハンドリング
-	if err != nil {
-		log.Printf("Error occurred: %v", err)
-		return nil, err
+	// 存在しない場合はNotFoundエラーを返す
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, userNotFoundError(id)
 	}
 
-	// ユーザー名
-	userName, err := domain.NewUserName(user.Name)
-
 	// エラーハンドリング
 	if err != nil {
 		log.Printf("Error occurred: %v", err)
@@ -53,21 +56,17 @@ func (r *UserRepository) FindByID(ctx context.Context, id int) (*domain.User, er
 	}
 
 	// ドメインのユーザーを返す
-	return &domain.User{
-		ID:        user.ID,
-		Name:      userName,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, nil
+	return toDomainUser(user)
 }
 
 // ユーザー一覧を取得する
-func (r *UserRepository) FindAll(ctx context.Context) ([]domain.User, error) {
+func (r *UserRepository) FindAll(ctx context.Context, page domain.IDPageRequest) (*domain.Page[domain.User], error) {
 	// ユーザースライス
 	var users []User
 
-	// クエリ実行
-	err := r.db.NewSelect().Model(&users).Scan(ctx)
+	// クエリ実行 (論理削除したユーザーは除外する)
+	q := dbFromContext(ctx, r.db).NewSelect().Model(&users).Where("deleted_at IS NULL")
+	err := applyIDPage(q, "id", page).Scan(ctx)
 
 	// エラーハンドリング
 	if err != nil {
@@ -76,28 +75,22 @@ func (r *UserRepository) FindAll(ctx context.Context) ([]domain.User, error) {
 	}
 
 	// ドメイン層のユーザー構造体にマッピング
-	var domainUsers []domain.User
-	for _, u := range users {
-		// ユーザー名
-		userName, err := domain.NewUserName(u.Name)
+	domainUsers := make([]domain.User, 0, len(users))
+	for i := range users {
+		// ユーザー
+		user, err := toDomainUser(&users[i])
 
 		// エラーハンドリング
 		if err != nil {
-			log.Printf("Error occurred: %v", err)
 			return nil, err
 		}
 
 		// domainUsersに詰める
-		domainUsers = append(domainUsers, domain.User{
-			ID:        u.ID,
-			Name:      userName,
-			CreatedAt: u.CreatedAt,
-			UpdatedAt: u.UpdatedAt,
-		})
+		domainUsers = append(domainUsers, *user)
 	}
 
-	// ドメインのユーザースライスを返す
-	return domainUsers, nil
+	// ドメインのユーザー一覧をページとして返す
+	return toPage(domainUsers, page.Limit, page.Direction), nil
 }
 
 // ユーザーを登録する
@@ -107,34 +100,133 @@ func (r *UserRepository) Create(ctx context.Context, name domain.UserName) (*dom
 		Name: name.Value,
 	}
 
-	// ユーザー名を生成
-	userName, err := domain.NewUserName(name.Value)
+	// ユーザー登録クエリを実行
+	_, err := dbFromContext(ctx, r.db).NewInsert().Model(user).Exec(ctx)
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return nil, err
+	}
 
-	// エラーハンドリング
+	// 挿入後に ID を基に再取得
+	err = dbFromContext(ctx, r.db).NewSelect().Model(user).Where("id = ?", user.ID).Scan(ctx)
 	if err != nil {
 		log.Printf("Error occurred: %v", err)
 		return nil, err
 	}
 
-	// ユーザー登録クエリを実行
-	_, err = r.db.NewInsert().Model(user).Exec(ctx)
+	// ドメインのユーザーを返す
+	return toDomainUser(user)
+}
+
+// ユーザーを論理削除する
+func (r *UserRepository) Delete(ctx context.Context, id int) error {
+	err := runInTx(ctx, r.db, func(ctx context.Context, db bun.IDB) error {
+		// 削除日時を記録するクエリを実行 (ハイスコアは残す)
+		now := time.Now().UTC()
+		result, err := db.NewUpdate().
+			Model((*User)(nil)).
+			Set("deleted_at = ?", now).
+			Set("updated_at = ?", now).
+			Where("id = ? AND deleted_at IS NULL", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// 削除対象が存在しない場合と論理削除済みの場合はNotFoundエラーを返す
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return userNotFoundError(id)
+		}
+
+		// 残したハイスコアをランキングの分布の集計値から取り除く
+		return updateUserScoreStats(ctx, db, id, removeScoreStats)
+	})
+
+	// エラーハンドリング
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		log.Printf("Error occurred: %v", err)
+	}
+	return err
+}
+
+// 論理削除したユーザーを元に戻す
+func (r *UserRepository) Restore(ctx context.Context, id int) (*domain.User, error) {
+	err := runInTx(ctx, r.db, func(ctx context.Context, db bun.IDB) error {
+		// 削除済みの場合のみ削除日時を消す
+		result, err := db.NewUpdate().
+			Model((*User)(nil)).
+			Set("deleted_at = NULL").
+			Set("updated_at = ?", time.Now().UTC()).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// 残していたハイスコアをランキングの分布の集計値に戻す
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			return updateUserScoreStats(ctx, db, id, addScoreStats)
+		}
+		return nil
+	})
+
+	// エラーハンドリング
 	if err != nil {
 		log.Printf("Error occurred: %v", err)
 		return nil, err
 	}
 
-	// 挿入後に ID を基に再取得
-	err = r.db.NewSelect().Model(user).Where("id = ?", user.ID).Scan(ctx)
+	// 元に戻したユーザーを返す (存在しない場合はNotFoundエラー)
+	return r.FindByID(ctx, id)
+}
+
+// ユーザーをシャドウバンする
+func (r *UserRepository) ShadowBan(ctx context.Context, id int) error {
+	db := dbFromContext(ctx, r.db)
+
+	// シャドウバンしていない場合のみ日時を記録する
+	now := time.Now().UTC()
+	_, err := db.NewUpdate().
+		Model((*User)(nil)).
+		Set("shadow_banned_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ? AND deleted_at IS NULL AND shadow_banned_at IS NULL", id).
+		Exec(ctx)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("Error occurred: %v", err)
+		return err
+	}
+
+	// 存在しない場合と論理削除済みの場合はNotFoundエラーを返す
+	_, err = r.FindByID(ctx, id)
+	return err
+}
+
+// ドメインのユーザーにマッピングする
+func toDomainUser(user *User) (*domain.User, error) {
+	// ユーザー名
+	userName, err := domain.NewUserName(user.Name)
+
+	// エラーハンドリング
 	if err != nil {
 		log.Printf("Error occurred: %v", err)
 		return nil, err
 	}
 
-	// ドメインのユーザーを返す
 	return &domain.User{
 		ID:        user.ID,
 		Name:      userName,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
+		DeletedAt: user.DeletedAt,
+
+		ShadowBannedAt: user.ShadowBannedAt,
 	}, nil
 }
+
+// ユーザーが存在しないエラーを生成する
+func userNotFoundError(id int) error {
+	return domain.NewNotFoundError("user_not_found", fmt.Sprintf("ユーザーが存在しません。ユーザーID: %d", id))
+}
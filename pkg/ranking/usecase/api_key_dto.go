@@ -0,0 +1,20 @@
+package usecase
+
+import "time"
+
+// APIキーDTO (キーの文字列は含まない)
+type APIKeyDto struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	GameID    *int       `json:"game_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// 発行したAPIキーDTO (キーの文字列は発行時にのみ返す)
+type IssuedAPIKeyDto struct {
+	*APIKeyDto
+	Key string `json:"key"`
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+// 発行するAPIキーの接頭辞 (キーの種類を見分けられるようにする)
+const apiKeyPrefix = "grk_"
+
+// 一覧で表示するAPIキーの先頭部分の長さ (接頭辞を含む)
+const apiKeyDisplayLength = 12
+
+// APIキーユースケース
+type APIKeyUseCase struct {
+	apiKeyRepository   domain.APIKeyRepositoryInterface
+	gameRepository     domain.GameRepositoryInterface
+	rankingRepository  domain.RankingRepositoryInterface
+	transactionManager TransactionManagerInterface
+
+	// 最初のAPIキーの発行に使う管理用のキー (全てのスコープを持つ。空文字の場合は使わない)
+	adminKey string
+}
+
+// ユースケースを生成する
+func NewAPIKeyUseCase(apiKeyRepo domain.APIKeyRepositoryInterface, gameRepo domain.GameRepositoryInterface, rankingRepo domain.RankingRepositoryInterface, adminKey string, tm TransactionManagerInterface) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepository:   apiKeyRepo,
+		gameRepository:     gameRepo,
+		rankingRepository:  rankingRepo,
+		transactionManager: tm,
+		adminKey:           adminKey,
+	}
+}
+
+// APIキー一覧を取得する
+func (apiKeyUseCase *APIKeyUseCase) GetAPIKeys(ctx context.Context, page domain.IDPageRequest) (*PageDto[APIKeyDto], error) {
+	// APIキー一覧をリポジトリから取得する
+	apiKeys, err := apiKeyUseCase.apiKeyRepository.FindAll(ctx, page)
+	if err != nil {
+		log.Printf("[APIKeyUseCase.GetAPIKeys] Failed to fetch api keys: %v", err)
+		return nil, err
+	}
+
+	// ユースケース層の構造体にマッピング (スライスの容量を事前に確保)
+	apiKeyDtos := make([]APIKeyDto, 0, len(apiKeys.Items))
+	for i := range apiKeys.Items {
+		apiKeyDtos = append(apiKeyDtos, *toAPIKeyDto(&apiKeys.Items[i]))
+	}
+
+	// ユースケースのAPIキーを返す
+	hasNext, hasPrev := PageLinks(apiKeys.HasMore, page.CursorID > 0, page.Direction)
+	return &PageDto[APIKeyDto]{
+		Items:   apiKeyDtos,
+		HasNext: hasNext,
+		HasPrev: hasPrev,
+	}, nil
+}
+
+// APIキーを発行する (キーの文字列はこの戻り値でのみ返し、保存するのはハッシュ値のみ)
+func (apiKeyUseCase *APIKeyUseCase) IssueAPIKey(ctx context.Context, command IssueAPIKeyCommand) (*IssuedAPIKeyDto, error) {
+	// APIキー名
+	apiKeyName, err := domain.NewAPIKeyName(command.Name)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyUseCase.IssueAPIKey] invalid api_key_name: %v", err)
+		return nil, err
+	}
+
+	// スコープ
+	scopes, err := domain.NewScopes(command.Scopes)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyUseCase.IssueAPIKey] invalid scopes: %v", err)
+		return nil, err
+	}
+
+	// ランダムなキーを生成する
+	key, err := generateAPIKey()
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyUseCase.IssueAPIKey] Failed to generate api key: %v", err)
+		return nil, err
+	}
+
+	// 発行するAPIキー
+	newAPIKey, err := domain.NewAPIKey(apiKeyName, scopes, command.GameID, key[:apiKeyDisplayLength], hashAPIKey(key))
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyUseCase.IssueAPIKey] invalid api key: %v", err)
+		return nil, err
+	}
+
+	// ゲームの存在確認と登録を同一トランザクション内で行う
+	var apiKey *domain.APIKey
+	err = apiKeyUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		// ゲームを限定する場合はゲームの存在確認
+		if command.GameID != nil {
+			if _, err := apiKeyUseCase.gameRepository.FindByID(ctx, *command.GameID); err != nil {
+				log.Printf("[APIKeyUseCase.IssueAPIKey] Failed to fetch game: %v", err)
+				return err
+			}
+		}
+
+		// リポジトリを使ってAPIキーを登録する
+		apiKey, err = apiKeyUseCase.apiKeyRepository.Create(ctx, newAPIKey)
+		return err
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyUseCase.IssueAPIKey] Failed to create api key: %v", err)
+		return nil, err
+	}
+
+	// 発行したキーの文字列とともに返す
+	return &IssuedAPIKeyDto{APIKeyDto: toAPIKeyDto(apiKey), Key: key}, nil
+}
+
+// APIキーを失効させる
+func (apiKeyUseCase *APIKeyUseCase) RevokeAPIKey(ctx context.Context, id int) error {
+	// トランザクション内でリポジトリを使ってAPIキーを失効させる
+	err := apiKeyUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		return apiKeyUseCase.apiKeyRepository.Revoke(ctx, id)
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyUseCase.RevokeAPIKey] Failed to revoke api key: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// キーの文字列からAPIキーを認証する (未指定の場合と、存在しないか失効したキーの場合はErrUnauthorized)
+func (apiKeyUseCase *APIKeyUseCase) Authenticate(ctx context.Context, key string) (*domain.APIKey, error) {
+	// キーが未指定
+	if key == "" {
+		return nil, domain.NewUnauthorizedError("api_key_required", "APIキーが指定されていません。")
+	}
+
+	// 管理用のキーは全てのスコープを持つ
+	if apiKeyUseCase.adminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKeyUseCase.adminKey)) == 1 {
+		return &domain.APIKey{Name: domain.APIKeyName{Value: "admin"}, Scopes: domain.AllScopes()}, nil
+	}
+
+	// ハッシュ値でAPIキーを探す
+	apiKey, err := apiKeyUseCase.apiKeyRepository.FindByHash(ctx, hashAPIKey(key))
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[APIKeyUseCase.Authenticate] Failed to fetch api key: %v", err)
+		return nil, err
+	}
+	if apiKey == nil || apiKey.IsRevoked() {
+		return nil, domain.NewUnauthorizedError("invalid_api_key", "APIキーが存在しないか失効しています。")
+	}
+
+	return apiKey, nil
+}
+
+// APIキーが対象をスコープの範囲で操作できることを確認する (権限がない場合はErrForbidden)
+func (apiKeyUseCase *APIKeyUseCase) Authorize(ctx context.Context, apiKey *domain.APIKey, scope domain.Scope, target AuthorizationTarget) error {
+	// スコープの確認
+	if err := apiKey.AuthorizeScope(scope); err != nil {
+		return err
+	}
+
+	// 全てのゲームに影響する操作は全てのゲームを対象とするキーのみ
+	if target.AllGames {
+		return apiKey.AuthorizeAllGames()
+	}
+
+	// 全てのゲームを対象とするキーはゲームを確認しない
+	if apiKey.GameID == nil {
+		return nil
+	}
+
+	// ランキングを指定した場合はランキングの属するゲームで確認する
+	gameID := target.GameID
+	if target.RankingID != 0 {
+		ranking, err := apiKeyUseCase.rankingRepository.FindByID(ctx, target.RankingID)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[APIKeyUseCase.Authorize] Failed to fetch ranking: %v", err)
+			return err
+		}
+		gameID = ranking.GameID
+	}
+	// ゲームを対象としない操作 (ユーザーの登録など) はゲームを限定したキーでも許可する
+	// 全てのゲームのデータを返す一覧の取得は、ルートで全てのゲームを対象とする操作として宣言する
+	if gameID == 0 {
+		return nil
+	}
+	return apiKey.AuthorizeGame(gameID)
+}
+
+// ランダムなAPIキーの文字列を生成する (256bit)
+func generateAPIKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// APIキーの文字列のハッシュ値を返す (十分に長いランダムな文字列のため、ソルトなしのSHA-256とする)
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIキーDTOにマッピングする
+func toAPIKeyDto(apiKey *domain.APIKey) *APIKeyDto {
+	scopes := make([]string, 0, len(apiKey.Scopes))
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	return &APIKeyDto{
+		ID:        apiKey.ID,
+		Name:      apiKey.Name.Value,
+		Prefix:    apiKey.Prefix,
+		Scopes:    scopes,
+		GameID:    apiKey.GameID,
+		CreatedAt: apiKey.CreatedAt,
+		RevokedAt: apiKey.RevokedAt,
+	}
+}
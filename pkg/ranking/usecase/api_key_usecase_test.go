@@ -0,0 +1,69 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 発行したAPIキーで認証でき、スコープとゲームの範囲で認可され、失効すると使えなくなる
+func TestAPIKeyLifecycle(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	puzzle, err := u.game.CreateGame(ctx, "puzzle")
+	require.NoError(t, err)
+	racing, err := u.game.CreateGame(ctx, "racing")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: racing.ID, Name: "ranking"})
+	require.NoError(t, err)
+
+	// パズルゲームに限定したキーを発行する
+	issued, err := u.apiKey.IssueAPIKey(ctx, usecase.IssueAPIKeyCommand{Name: "puzzle client", Scopes: []string{"read", "scores:write"}, GameID: &puzzle.ID})
+	require.NoError(t, err)
+	assert.Equal(t, issued.Key[:len(issued.Prefix)], issued.Prefix)
+	assert.Equal(t, []string{"read", "scores:write"}, issued.Scopes)
+
+	// キーで認証できる
+	apiKey, err := u.apiKey.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, issued.ID, apiKey.ID)
+
+	// スコープとゲームの範囲で認可される
+	assert.NoError(t, u.apiKey.Authorize(ctx, apiKey, domain.ScopeScoresWrite, usecase.AuthorizationTarget{GameID: puzzle.ID}))
+	assert.NoError(t, u.apiKey.Authorize(ctx, apiKey, domain.ScopeScoresWrite, usecase.AuthorizationTarget{}))
+	assert.ErrorIs(t, u.apiKey.Authorize(ctx, apiKey, domain.ScopeRankingsAdmin, usecase.AuthorizationTarget{GameID: puzzle.ID}), domain.ErrForbidden)
+	assert.ErrorIs(t, u.apiKey.Authorize(ctx, apiKey, domain.ScopeRead, usecase.AuthorizationTarget{RankingID: ranking.ID}), domain.ErrForbidden)
+	assert.ErrorIs(t, u.apiKey.Authorize(ctx, apiKey, domain.ScopeRead, usecase.AuthorizationTarget{AllGames: true}), domain.ErrForbidden)
+
+	// 一覧にはキーの文字列を含めない
+	page, err := usecase.NewIDPageRequest(0, "", 0)
+	require.NoError(t, err)
+	apiKeys, err := u.apiKey.GetAPIKeys(ctx, page)
+	require.NoError(t, err)
+	require.Len(t, apiKeys.Items, 1)
+	assert.Equal(t, issued.Prefix, apiKeys.Items[0].Prefix)
+
+	// 失効したキーと不明なキーは認証できない
+	require.NoError(t, u.apiKey.RevokeAPIKey(ctx, issued.ID))
+	_, err = u.apiKey.Authenticate(ctx, issued.Key)
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	_, err = u.apiKey.Authenticate(ctx, "grk_unknown")
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	_, err = u.apiKey.Authenticate(ctx, "")
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.ErrorIs(t, u.apiKey.RevokeAPIKey(ctx, issued.ID), domain.ErrNotFound)
+
+	// 管理用のキーは全てのスコープを持つ
+	admin, err := u.apiKey.Authenticate(ctx, testAdminAPIKey)
+	require.NoError(t, err)
+	assert.NoError(t, u.apiKey.Authorize(ctx, admin, domain.ScopeKeysAdmin, usecase.AuthorizationTarget{AllGames: true}))
+
+	// 存在しないゲームに限定したキーは発行できない
+	missingGameID := racing.ID + 1
+	_, err = u.apiKey.IssueAPIKey(ctx, usecase.IssueAPIKeyCommand{Name: "missing", Scopes: []string{"read"}, GameID: &missingGameID})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,13 @@
+package usecase
+
+// APIキーで操作する対象 (ゲームを限定したキーの認可に使う)
+type AuthorizationTarget struct {
+	// 操作するゲーム (0の場合は指定なし)
+	GameID int
+
+	// 操作するランキング (0の場合は指定なし。ランキングの属するゲームで認可する)
+	RankingID int
+
+	// 全てのゲームに影響する操作か
+	AllGames bool
+}
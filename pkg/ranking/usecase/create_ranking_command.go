@@ -0,0 +1,23 @@
+package usecase
+
+// ランキングの登録内容
+type CreateRankingCommand struct {
+	GameID int
+	Name   string
+
+	// 並び順 (空文字の場合はスコアが大きいほど上位)
+	SortOrder string
+
+	// スコアの型と小数点以下の桁数 (空文字の場合は整数)
+	ScoreType  string
+	ScoreScale int
+
+	// スコアの制約 (スコアの型に応じた数値の文字列。空文字の場合は制約なし)
+	MinScore  string
+	MaxScore  string
+	ScoreStep string
+
+	// シーズンの周期とタイムゾーン (空文字の場合は周期なし。タイムゾーンの空文字はUTC)
+	Recurrence string
+	Timezone   string
+}
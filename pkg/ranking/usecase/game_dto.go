@@ -0,0 +1,20 @@
+package usecase
+
+import "time"
+
+// ゲームDTO
+type GameDto struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// スコアの送信に署名を求めるか (秘密鍵は含まない)
+	SignedSubmissions bool `json:"signed_submissions"`
+}
+
+// スコアの送信の署名に使う秘密鍵DTO (秘密鍵は生成時にのみ返す)
+type SubmissionSecretDto struct {
+	GameID int    `json:"game_id"`
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+// スコアの送信の署名に使う秘密鍵の接頭辞 (APIキーと見分けられるようにする)
+const submissionSecretPrefix = "gss_"
+
+// ゲームユースケース
+type GameUseCase struct {
+	gameRepository     domain.GameRepositoryInterface
+	transactionManager TransactionManagerInterface
+}
+
+// ユースケースを生成する
+func NewGameUseCase(r domain.GameRepositoryInterface, tm TransactionManagerInterface) *GameUseCase {
+	return &GameUseCase{
+		gameRepository:     r,
+		transactionManager: tm,
+	}
+}
+
+// ゲーム一覧を取得する
+func (gameUseCase *GameUseCase) GetGames(ctx context.Context, page domain.IDPageRequest) (*PageDto[GameDto], error) {
+	// ゲーム一覧をリポジトリから取得する
+	games, err := gameUseCase.gameRepository.FindAll(ctx, page)
+	if err != nil {
+		log.Printf("[GameUseCase.GetGames] Failed to fetch games: %v", err)
+		return nil, err
+	}
+
+	// ユースケース層の構造体にマッピング (スライスの容量を事前に確保)
+	gameDtos := make([]GameDto, 0, len(games.Items))
+	for i := range games.Items {
+		gameDtos = append(gameDtos, *toGameDto(&games.Items[i]))
+	}
+
+	// ユースケースのゲームを返す
+	hasNext, hasPrev := PageLinks(games.HasMore, page.CursorID > 0, page.Direction)
+	return &PageDto[GameDto]{
+		Items:   gameDtos,
+		HasNext: hasNext,
+		HasPrev: hasPrev,
+	}, nil
+}
+
+// ゲームを取得する
+func (gameUseCase *GameUseCase) GetGame(ctx context.Context, id int) (*GameDto, error) {
+	// ゲームをリポジトリから取得する
+	game, err := gameUseCase.gameRepository.FindByID(ctx, id)
+	if err != nil {
+		log.Printf("[GameUseCase.GetGame] Failed to fetch game: %v", err)
+		return nil, err
+	}
+
+	// ユースケースのゲームを返す
+	return toGameDto(game), nil
+}
+
+// ゲームを新規登録する
+func (gameUseCase *GameUseCase) CreateGame(ctx context.Context, name string) (*GameDto, error) {
+	// ゲーム名
+	gameName, err := domain.NewGameName(name)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameUseCase.CreateGame] invalid game_name: %v", err)
+		return nil, err
+	}
+
+	// 名前の重複確認と登録を同一トランザクション内で行う
+	var game *domain.Game
+	err = gameUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		// ゲーム名が既に登録されているか確認
+		if err := gameUseCase.ensureGameNameAvailable(ctx, 0, gameName); err != nil {
+			return err
+		}
+
+		// リポジトリを使ってゲームを登録する
+		game, err = gameUseCase.gameRepository.Create(ctx, gameName)
+		return err
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameUseCase.CreateGame] Failed to create new game: %v", err)
+		return nil, err
+	}
+
+	// ユースケースのゲームを返す
+	return toGameDto(game), nil
+}
+
+// ゲーム名を変更する
+func (gameUseCase *GameUseCase) UpdateGame(ctx context.Context, id int, name string) (*GameDto, error) {
+	// ゲーム名
+	gameName, err := domain.NewGameName(name)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameUseCase.UpdateGame] invalid game_name: %v", err)
+		return nil, err
+	}
+
+	// 名前の重複確認と更新を同一トランザクション内で行う
+	var game *domain.Game
+	err = gameUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		// 他のゲームが同じ名前を使っていないか確認
+		if err := gameUseCase.ensureGameNameAvailable(ctx, id, gameName); err != nil {
+			return err
+		}
+
+		// リポジトリを使ってゲーム名を変更する
+		game, err = gameUseCase.gameRepository.Update(ctx, id, gameName)
+		return err
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameUseCase.UpdateGame] Failed to update game: %v", err)
+		return nil, err
+	}
+
+	// ユースケースのゲームを返す
+	return toGameDto(game), nil
+}
+
+// ゲームを削除する (ゲームに属するランキングとハイスコアも削除される)
+func (gameUseCase *GameUseCase) DeleteGame(ctx context.Context, id int) error {
+	// トランザクション内でリポジトリを使ってゲームを削除する
+	err := gameUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		return gameUseCase.gameRepository.Delete(ctx, id)
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameUseCase.DeleteGame] Failed to delete game: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// スコアの送信の署名に使う秘密鍵を生成し直す (以降、ゲームのランキングへの送信には新しい秘密鍵での署名を求める)
+func (gameUseCase *GameUseCase) RotateSubmissionSecret(ctx context.Context, id int) (*SubmissionSecretDto, error) {
+	// ランダムな秘密鍵を生成する (256bit)
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		log.Printf("[GameUseCase.RotateSubmissionSecret] Failed to generate secret: %v", err)
+		return nil, err
+	}
+	secret := submissionSecretPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	// トランザクション内でリポジトリを使って秘密鍵を設定する
+	err := gameUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		_, err := gameUseCase.gameRepository.UpdateSubmissionSecret(ctx, id, &secret)
+		return err
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameUseCase.RotateSubmissionSecret] Failed to update submission secret: %v", err)
+		return nil, err
+	}
+
+	// 生成した秘密鍵を返す
+	return &SubmissionSecretDto{GameID: id, Secret: secret}, nil
+}
+
+// スコアの送信の署名に使う秘密鍵を削除する (以降、ゲームのランキングへの送信に署名を求めない)
+func (gameUseCase *GameUseCase) DeleteSubmissionSecret(ctx context.Context, id int) error {
+	// トランザクション内でリポジトリを使って秘密鍵を削除する
+	err := gameUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		_, err := gameUseCase.gameRepository.UpdateSubmissionSecret(ctx, id, nil)
+		return err
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameUseCase.DeleteSubmissionSecret] Failed to delete submission secret: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ゲーム名が自身 (selfID) 以外のゲームで使われていないことを確認する
+func (gameUseCase *GameUseCase) ensureGameNameAvailable(ctx context.Context, selfID int, gameName domain.GameName) error {
+	// ゲーム名が既に登録されているか確認
+	registered, err := gameUseCase.gameRepository.FindByName(ctx, gameName)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[GameUseCase] Failed to fetch game: %v", err)
+		return err
+	}
+	if registered != nil && registered.ID != selfID {
+		log.Printf("[GameUseCase] game name %v already used", gameName.Value)
+		return domain.NewConflictError("game_name_conflict", fmt.Sprintf("ゲーム名は既に使用されています。入力された名前: %q", gameName.Value))
+	}
+
+	return nil
+}
+
+// ゲームDTOにマッピングする
+func toGameDto(game *domain.Game) *GameDto {
+	return &GameDto{
+		ID:        game.ID,
+		Name:      game.Name.Value,
+		CreatedAt: game.CreatedAt,
+		UpdatedAt: game.UpdatedAt,
+
+		SignedSubmissions: game.SubmissionSecret != nil,
+	}
+}
@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ゲーム名は他のゲームと重複できない
+func TestUpdateGameNameConflict(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	puzzle, err := u.game.CreateGame(ctx, "puzzle")
+	require.NoError(t, err)
+	_, err = u.game.CreateGame(ctx, "racing")
+	require.NoError(t, err)
+
+	_, err = u.game.CreateGame(ctx, "puzzle")
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	_, err = u.game.UpdateGame(ctx, puzzle.ID, "racing")
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	// 自分と同じ名前への変更は競合しない
+	game, err := u.game.UpdateGame(ctx, puzzle.ID, "puzzle")
+	require.NoError(t, err)
+	assert.Equal(t, "puzzle", game.Name)
+}
+
+// ゲームを削除するとゲームに属するランキングも削除される
+func TestDeleteGame(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "puzzle")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "weekly"})
+	require.NoError(t, err)
+
+	require.NoError(t, u.game.DeleteGame(ctx, game.ID))
+
+	_, err = u.game.GetGame(ctx, game.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	page, err := usecase.NewIDPageRequest(0, "", 0)
+	require.NoError(t, err)
+	rankings, err := u.ranking.GetRankings(ctx, page)
+	require.NoError(t, err)
+	assert.NotContains(t, rankings.Items, *ranking)
+
+	// 存在しないゲームは削除できない
+	assert.ErrorIs(t, u.game.DeleteGame(ctx, game.ID), domain.ErrNotFound)
+}
@@ -0,0 +1,10 @@
+package usecase
+
+// APIキーの発行内容
+type IssueAPIKeyCommand struct {
+	Name   string
+	Scopes []string
+
+	// 操作できるゲーム (nilの場合は全てのゲーム)
+	GameID *int
+}
@@ -0,0 +1,69 @@
+package usecase_test
+
+import (
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/memory"
+	"practice-go-game-ranking/pkg/ranking/infrastructure/playertoken"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"time"
+)
+
+// テストで使う管理用のAPIキー
+const testAdminAPIKey = "test-admin-key"
+
+// テストで使うスコアの送信の署名のタイムスタンプのずれの許容範囲
+const testSignatureWindow = 5 * time.Minute
+
+// インメモリのリポジトリで組み立てたユースケース
+type testUseCases struct {
+	user          *usecase.UserUseCase
+	game          *usecase.GameUseCase
+	ranking       *usecase.RankingUseCase
+	userHighScore *usecase.UserHighScoreUseCase
+	season        *usecase.SeasonUseCase
+	submission    *usecase.ScoreSubmissionUseCase
+	moderation    *usecase.ModerationUseCase
+	apiKey        *usecase.APIKeyUseCase
+	playerToken   *usecase.PlayerTokenUseCase
+	queryService  *memory.UserRankingQueryService
+
+	// 終了したシーズンのユーザーランキング
+	seasonQueryService *memory.SeasonUserRankingQueryService
+
+	// シーズンの期間を直接進めるテストで使うリポジトリ
+	rankingRepository domain.RankingRepositoryInterface
+	seasonRepository  domain.SeasonRepositoryInterface
+}
+
+// データベースなしで動くユースケースを生成する
+func newTestUseCases() *testUseCases {
+	store := memory.NewStore()
+	transactionManager := memory.NewTransactionManager(store)
+	userRepository := memory.NewUserRepository(store)
+	gameRepository := memory.NewGameRepository(store)
+	rankingRepository := memory.NewRankingRepository(store)
+	userHighScoreRepository := memory.NewUserHighScoreRepository(store)
+	seasonRepository := memory.NewSeasonRepository(store)
+	scoreSubmissionRepository := memory.NewScoreSubmissionRepository(store)
+	queryService := memory.NewUserRankingQueryService(store)
+	keyset, err := playertoken.NewEphemeralKeyset()
+	if err != nil {
+		panic(err)
+	}
+	return &testUseCases{
+		user:          usecase.NewUserUseCase(userRepository, rankingRepository, userHighScoreRepository, nil, transactionManager),
+		game:          usecase.NewGameUseCase(gameRepository, transactionManager),
+		ranking:       usecase.NewRankingUseCase(gameRepository, rankingRepository, nil, transactionManager),
+		userHighScore: usecase.NewUserHighScoreUseCase(rankingRepository, userRepository, userHighScoreRepository, scoreSubmissionRepository, seasonRepository, queryService, nil, usecase.NewSubmissionSignatureVerifier(gameRepository, memory.NewSubmissionNonceRepository(store), testSignatureWindow), usecase.NewScorePlausibilityRules(userHighScoreRepository, scoreSubmissionRepository), transactionManager),
+		season:        usecase.NewSeasonUseCase(rankingRepository, seasonRepository, nil, transactionManager),
+		submission:    usecase.NewScoreSubmissionUseCase(rankingRepository, userRepository, scoreSubmissionRepository),
+		moderation:    usecase.NewModerationUseCase(rankingRepository, userRepository, userHighScoreRepository, scoreSubmissionRepository, memory.NewScoreReviewRepository(store), nil, transactionManager),
+		apiKey:        usecase.NewAPIKeyUseCase(memory.NewAPIKeyRepository(store), gameRepository, rankingRepository, testAdminAPIKey, transactionManager),
+		playerToken:   usecase.NewPlayerTokenUseCase(userRepository, playertoken.NewService(keyset, time.Minute)),
+		queryService:  queryService,
+
+		seasonQueryService: memory.NewSeasonUserRankingQueryService(store),
+		rankingRepository:  rankingRepository,
+		seasonRepository:   seasonRepository,
+	}
+}
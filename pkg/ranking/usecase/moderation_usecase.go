@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+// モデレーションユースケース
+// スコアの妥当性のルールで審査待ちにした送信を承認または却下する
+type ModerationUseCase struct {
+	rankingRepository         domain.RankingRepositoryInterface
+	userRepository            domain.UserRepositoryInterface
+	userHighScoreRepository   domain.UserHighScoreRepositoryInterface
+	scoreSubmissionRepository domain.ScoreSubmissionRepositoryInterface
+	scoreReviewRepository     domain.ScoreReviewRepositoryInterface
+	userRankIndex             UserRankIndexInterface
+	transactionManager        TransactionManagerInterface
+}
+
+// ユースケースを生成する (ランク付けのインデックスを使わない場合はuserRankIndexにnilを渡す)
+func NewModerationUseCase(rankingRepo domain.RankingRepositoryInterface, userRepo domain.UserRepositoryInterface, userHighScoreRepo domain.UserHighScoreRepositoryInterface, scoreSubmissionRepo domain.ScoreSubmissionRepositoryInterface, scoreReviewRepo domain.ScoreReviewRepositoryInterface, userRankIndex UserRankIndexInterface, tm TransactionManagerInterface) *ModerationUseCase {
+	return &ModerationUseCase{
+		rankingRepository:         rankingRepo,
+		userRepository:            userRepo,
+		userHighScoreRepository:   userHighScoreRepo,
+		scoreSubmissionRepository: scoreSubmissionRepo,
+		scoreReviewRepository:     scoreReviewRepo,
+		userRankIndex:             userRankIndex,
+		transactionManager:        tm,
+	}
+}
+
+// 審査待ちの送信履歴を送信順に取得する
+func (moderationUseCase *ModerationUseCase) GetQueue(ctx context.Context, page domain.IDPageRequest) (*PageDto[ScoreSubmissionDto], error) {
+	// 審査待ちの送信履歴をリポジトリから取得する
+	submissions, err := moderationUseCase.scoreSubmissionRepository.FindAllPendingReview(ctx, page)
+	if err != nil {
+		log.Printf("[ModerationUseCase.GetQueue] Failed to fetch score submissions: %v", err)
+		return nil, err
+	}
+
+	// ユースケース層の構造体にマッピング (スコアの表記に使うランキングは1回ずつ取得する)
+	scoreTypes := map[int]domain.ScoreType{}
+	submissionDtos := make([]ScoreSubmissionDto, 0, len(submissions.Items))
+	for i := range submissions.Items {
+		submission := &submissions.Items[i]
+		scoreType, ok := scoreTypes[submission.RankingID]
+		if !ok {
+			ranking, err := moderationUseCase.rankingRepository.FindByID(ctx, submission.RankingID)
+			if err != nil {
+				log.Printf("[ModerationUseCase.GetQueue] Failed to fetch ranking: %v", err)
+				return nil, err
+			}
+			scoreType = ranking.ScoreType
+			scoreTypes[submission.RankingID] = scoreType
+		}
+		submissionDtos = append(submissionDtos, toScoreSubmissionDto(submission, scoreType))
+	}
+
+	// ユースケースの送信履歴を返す
+	hasNext, hasPrev := PageLinks(submissions.HasMore, page.CursorID > 0, page.Direction)
+	return &PageDto[ScoreSubmissionDto]{
+		Items:   submissionDtos,
+		HasNext: hasNext,
+		HasPrev: hasPrev,
+	}, nil
+}
+
+// 審査待ちの送信を承認してハイスコアに反映する
+// 同点のユーザーとは審査した日時ではなく送信日時で比べる
+func (moderationUseCase *ModerationUseCase) ApproveScore(ctx context.Context, command ReviewScoreCommand) (*ScoreReviewDto, error) {
+	return moderationUseCase.reviewScore(ctx, command, domain.ReviewApproved)
+}
+
+// 審査待ちの送信を却下する (指定された場合はユーザーをシャドウバンする)
+func (moderationUseCase *ModerationUseCase) RejectScore(ctx context.Context, command ReviewScoreCommand) (*ScoreReviewDto, error) {
+	return moderationUseCase.reviewScore(ctx, command, domain.ReviewRejected)
+}
+
+// トランザクション内での審査の結果
+type scoreReviewResult struct {
+	ranking      *domain.Ranking
+	user         *domain.User
+	submission   *domain.ScoreSubmission
+	review       *domain.ScoreReview
+	upsertResult *domain.UserHighScoreUpsertResult
+}
+
+// 審査待ちの送信を審査する
+func (moderationUseCase *ModerationUseCase) reviewScore(ctx context.Context, command ReviewScoreCommand, decision domain.ReviewDecision) (*ScoreReviewDto, error) {
+	// 送信の状態の更新、ハイスコアの保存と審査の記録を同一トランザクション内で行う
+	var result *scoreReviewResult
+	err := moderationUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = moderationUseCase.reviewScoreInTx(ctx, command, decision)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// コミットしたハイスコアをランク付けのインデックスに反映する
+	upsertResult := result.upsertResult
+	if moderationUseCase.userRankIndex != nil && upsertResult != nil && upsertResult.Outcome != domain.HighScoreUnchanged {
+		moderationUseCase.userRankIndex.Apply(upsertResult.HighScore, result.ranking.SeasonNumber(), result.ranking.SortOrder, result.user.Name.Value)
+	}
+
+	// ユースケースの審査を返す
+	review := result.review
+	reviewDto := &ScoreReviewDto{
+		ID:           review.ID,
+		SubmissionID: review.SubmissionID,
+		RankingID:    result.submission.RankingID,
+		UserID:       result.submission.UserID,
+		Score:        NewScoreDto(result.submission.Score, result.ranking.ScoreType),
+		Decision:     string(review.Decision),
+		Reviewer:     review.Reviewer,
+		Reason:       review.Reason,
+		ShadowBanned: review.ShadowBanned,
+		FlaggedAt:    review.FlaggedAt,
+		ReviewedAt:   review.ReviewedAt,
+	}
+	if upsertResult != nil {
+		outcome := string(upsertResult.Outcome)
+		reviewDto.Outcome = &outcome
+	}
+	return reviewDto, nil
+}
+
+// トランザクション内で送信を審査する
+func (moderationUseCase *ModerationUseCase) reviewScoreInTx(ctx context.Context, command ReviewScoreCommand, decision domain.ReviewDecision) (*scoreReviewResult, error) {
+	// 送信履歴の存在確認
+	submission, err := moderationUseCase.scoreSubmissionRepository.FindByID(ctx, command.SubmissionID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ModerationUseCase.ReviewScore] Failed to fetch score submission: %v", err)
+		return nil, err
+	}
+
+	// 審査 (審査待ちの送信か、理由とシャドウバンの指定を検証する)
+	review, err := domain.NewScoreReview(submission, decision, command.Reviewer, command.Reason, command.ShadowBan)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ModerationUseCase.ReviewScore] invalid review: %v", err)
+		return nil, err
+	}
+
+	// ランキングとユーザーの存在確認 (論理削除したユーザーの送信は審査できない)
+	// 承認したハイスコアを確認したシーズンに保存するよう、ランキングを行ロックして読み取る
+	ranking, err := moderationUseCase.rankingRepository.FindByIDForUpdate(ctx, submission.RankingID)
+	if err != nil {
+		log.Printf("[ModerationUseCase.ReviewScore] Failed to fetch ranking: %v", err)
+		return nil, err
+	}
+	user, err := moderationUseCase.userRepository.FindByID(ctx, submission.UserID)
+	if err != nil {
+		log.Printf("[ModerationUseCase.ReviewScore] Failed to fetch user: %v", err)
+		return nil, err
+	}
+
+	// 送信の状態を更新する (同時に審査された場合は一方のみ成功する)
+	if err := moderationUseCase.scoreSubmissionRepository.Resolve(ctx, submission.ID, decision.SubmissionStatus()); err != nil {
+		log.Printf("[ModerationUseCase.ReviewScore] Failed to resolve score submission: %v", err)
+		return nil, err
+	}
+	result := &scoreReviewResult{ranking: ranking, user: user, submission: submission}
+
+	switch decision {
+	case domain.ReviewApproved:
+		// 終了したシーズンに送信されたスコアは現在のシーズンに反映できない
+		if ranking.Season != nil && submission.SubmittedAt.Before(ranking.Season.StartedAt) {
+			log.Printf("[ModerationUseCase.ReviewScore] submission %d was sent before season %d", submission.ID, ranking.Season.Number)
+			return nil, domain.NewConflictError("submission_season_ended", fmt.Sprintf("送信されたシーズンが終了しているため承認できません。送信履歴ID: %d", submission.ID))
+		}
+
+		// 送信日時を登録日時としてハイスコアを保存する (既存のハイスコアを上回る場合のみ更新する)
+		result.upsertResult, err = moderationUseCase.userHighScoreRepository.UpsertAt(ctx, ranking.ID, user.ID, submission.Score, ranking.SortOrder, submission.SubmittedAt)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[ModerationUseCase.ReviewScore] Failed to upsert user high score: %v", err)
+			return nil, err
+		}
+	case domain.ReviewRejected:
+		// ユーザーをシャドウバンする
+		if command.ShadowBan {
+			if err := moderationUseCase.userRepository.ShadowBan(ctx, user.ID); err != nil {
+				log.Printf("[ModerationUseCase.ReviewScore] Failed to shadow ban user: %v", err)
+				return nil, err
+			}
+		}
+	}
+
+	// 審査を記録する
+	result.review, err = moderationUseCase.scoreReviewRepository.Create(ctx, review)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ModerationUseCase.ReviewScore] Failed to create score review: %v", err)
+		return nil, err
+	}
+
+	return result, nil
+}
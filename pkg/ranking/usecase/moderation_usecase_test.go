@@ -0,0 +1,135 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 審査に使うAPIキー
+func testReviewer(t *testing.T) *domain.APIKey {
+	name, err := domain.NewAPIKeyName("moderator")
+	require.NoError(t, err)
+	return &domain.APIKey{ID: 1, Name: name}
+}
+
+// 上限を超えるスコアを審査待ちにするランキングを作成する
+func newModeratedRanking(t *testing.T, u *testUseCases) *usecase.RankingDto {
+	ctx := context.Background()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	ranking, err = u.ranking.UpdatePlausibilityRules(ctx, ranking.ID, usecase.UpdatePlausibilityRulesCommand{ScoreCap: "1000"})
+	require.NoError(t, err)
+	return ranking
+}
+
+// 承認したスコアは送信日時を登録日時としてハイスコアに反映され、キューから外れる
+func TestApproveScore(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	ranking := newModeratedRanking(t, u)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+
+	// aliceのスコアが審査待ちになった後、ルールを外してbobが同点を登録する
+	held, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "1500"})
+	require.NoError(t, err)
+	require.Equal(t, string(domain.HighScorePendingReview), held.Outcome)
+	page, err := usecase.NewIDPageRequest(0, "", 10)
+	require.NoError(t, err)
+	queue, err := u.moderation.GetQueue(ctx, page)
+	require.NoError(t, err)
+	require.Len(t, queue.Items, 1)
+	assert.Equal(t, held.SubmissionID, queue.Items[0].ID)
+	assert.Equal(t, "1500", queue.Items[0].Score.String())
+
+	_, err = u.ranking.UpdatePlausibilityRules(ctx, ranking.ID, usecase.UpdatePlausibilityRulesCommand{})
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "1500"})
+	require.NoError(t, err)
+
+	// 承認すると、先に送信したaliceが同点のbobより上位になる
+	review, err := u.moderation.ApproveScore(ctx, usecase.ReviewScoreCommand{SubmissionID: held.SubmissionID, Reviewer: testReviewer(t), Reason: "録画を確認"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.ReviewApproved), review.Decision)
+	assert.Equal(t, "moderator", review.Reviewer)
+	assert.Equal(t, "録画を確認", review.Reason)
+	assert.Equal(t, string(domain.HighScoreCreated), *review.Outcome)
+	standing, err := u.queryService.FetchUserStanding(ctx, ranking.ID, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, standing.Rank)
+	assert.True(t, review.FlaggedAt.Equal(standing.AchievedAt))
+	standing, err = u.queryService.FetchUserStanding(ctx, ranking.ID, bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, standing.Rank)
+
+	// 承認した送信はキューから外れ、送信履歴の状態が変わる
+	queue, err = u.moderation.GetQueue(ctx, page)
+	require.NoError(t, err)
+	assert.Empty(t, queue.Items)
+	submissions, err := u.submission.GetScoreSubmissions(ctx, ranking.ID, alice.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.SubmissionApproved), submissions.Items[0].Status)
+
+	// 審査済みの送信と受け付けた送信は審査できない
+	_, err = u.moderation.RejectScore(ctx, usecase.ReviewScoreCommand{SubmissionID: held.SubmissionID, Reviewer: testReviewer(t)})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	bobSubmissions, err := u.submission.GetScoreSubmissions(ctx, ranking.ID, bob.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	_, err = u.moderation.ApproveScore(ctx, usecase.ReviewScoreCommand{SubmissionID: bobSubmissions.Items[0].ID, Reviewer: testReviewer(t)})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	// 存在しない送信はNotFound
+	_, err = u.moderation.ApproveScore(ctx, usecase.ReviewScoreCommand{SubmissionID: held.SubmissionID + 100, Reviewer: testReviewer(t)})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// 却下したスコアはハイスコアに反映されず、シャドウバンしたユーザーの以降の送信は審査待ちと同じ結果で反映されない
+func TestRejectScore(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	ranking := newModeratedRanking(t, u)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+
+	held, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "5000"})
+	require.NoError(t, err)
+
+	// 承認と同時のシャドウバンはバリデーションエラー
+	_, err = u.moderation.ApproveScore(ctx, usecase.ReviewScoreCommand{SubmissionID: held.SubmissionID, Reviewer: testReviewer(t), ShadowBan: true})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	review, err := u.moderation.RejectScore(ctx, usecase.ReviewScoreCommand{SubmissionID: held.SubmissionID, Reviewer: testReviewer(t), Reason: "改造クライアント", ShadowBan: true})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.ReviewRejected), review.Decision)
+	assert.True(t, review.ShadowBanned)
+	assert.Nil(t, review.Outcome)
+	_, err = u.queryService.FetchUserStanding(ctx, ranking.ID, alice.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// シャドウバン後はルールを満たすスコアも審査待ちと同じ結果を返し、キューにも並ばない
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "100"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScorePendingReview), result.Outcome)
+	_, err = u.queryService.FetchUserStanding(ctx, ranking.ID, alice.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	page, err := usecase.NewIDPageRequest(0, "", 10)
+	require.NoError(t, err)
+	queue, err := u.moderation.GetQueue(ctx, page)
+	require.NoError(t, err)
+	assert.Empty(t, queue.Items)
+
+	submissions, err := u.submission.GetScoreSubmissions(ctx, ranking.ID, alice.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	require.Len(t, submissions.Items, 2)
+	assert.Equal(t, string(domain.SubmissionRejected), submissions.Items[0].Status)
+	assert.Equal(t, string(domain.SubmissionShadowBanned), submissions.Items[1].Status)
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+const (
+	// 一覧の取得件数の既定値
+	DefaultPageLimit = 100
+
+	// 一覧の取得件数の上限
+	MaxPageLimit = 1000
+)
+
+// ページングされた一覧DTO
+type PageDto[T any] struct {
+	Items   []T
+	HasNext bool
+	HasPrev bool
+}
+
+// IDをキーとしたページ指定を生成する
+func NewIDPageRequest(cursorID int, direction domain.PageDirection, limit int) (domain.IDPageRequest, error) {
+	// 取得件数
+	limit, err := newPageLimit(limit)
+	if err != nil {
+		return domain.IDPageRequest{}, err
+	}
+
+	// 方向 (未指定の場合は後ろのページ)
+	if direction == "" {
+		direction = domain.PageNext
+	}
+
+	return domain.IDPageRequest{
+		CursorID:  cursorID,
+		Direction: direction,
+		Limit:     limit,
+	}, nil
+}
+
+// 取得件数を検証する (未指定の場合は既定値)
+func newPageLimit(limit int) (int, error) {
+	if limit == 0 {
+		return DefaultPageLimit, nil
+	}
+	if limit < 1 || limit > MaxPageLimit {
+		return 0, domain.NewValidationError("invalid_limit", fmt.Sprintf("取得件数は1から%dの範囲で指定してください。入力された件数: %d", MaxPageLimit, limit))
+	}
+	return limit, nil
+}
+
+// 取得した方向の続きの有無から前後のページの有無を判定する
+// カーソルを指定した場合、取得した方向と逆側には基準となった要素があるため常にページがある
+func PageLinks(hasMore bool, hasCursor bool, direction domain.PageDirection) (hasNext bool, hasPrev bool) {
+	if direction == domain.PagePrev {
+		return true, hasMore
+	}
+	return hasMore, hasCursor
+}
@@ -0,0 +1,9 @@
+package usecase
+
+import "time"
+
+// プレイヤーのトークンDTO
+type PlayerTokenDto struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import "time"
+
+// プレイヤーのトークンの発行と検証 (インターフェース)
+type PlayerTokenServiceInterface interface {
+	// ユーザーを主体とするトークンを発行し、有効期限とともに返す
+	Issue(userID int, now time.Time) (string, time.Time, error)
+
+	// トークンを検証し、主体のユーザーIDを返す (不正なトークンと有効期限切れのトークンはErrUnauthorized)
+	Verify(token string, now time.Time) (int, error)
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// プレイヤーのトークンユースケース
+type PlayerTokenUseCase struct {
+	userRepository     domain.UserRepositoryInterface
+	playerTokenService PlayerTokenServiceInterface
+}
+
+// ユースケースを生成する
+func NewPlayerTokenUseCase(userRepo domain.UserRepositoryInterface, playerTokenService PlayerTokenServiceInterface) *PlayerTokenUseCase {
+	return &PlayerTokenUseCase{
+		userRepository:     userRepo,
+		playerTokenService: playerTokenService,
+	}
+}
+
+// ユーザーのトークンを発行する (論理削除したユーザーには発行しない)
+func (playerTokenUseCase *PlayerTokenUseCase) IssuePlayerToken(ctx context.Context, userID int) (*PlayerTokenDto, error) {
+	// ユーザーの存在確認
+	if _, err := playerTokenUseCase.userRepository.FindByID(ctx, userID); err != nil {
+		log.Printf("[PlayerTokenUseCase.IssuePlayerToken] Failed to fetch user: %v", err)
+		return nil, err
+	}
+
+	// トークンを発行する
+	token, expiresAt, err := playerTokenUseCase.playerTokenService.Issue(userID, time.Now())
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[PlayerTokenUseCase.IssuePlayerToken] Failed to issue player token: %v", err)
+		return nil, err
+	}
+
+	// ユースケースのトークンを返す
+	return &PlayerTokenDto{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// トークンの主体がユーザーであることを確認する
+// 未指定の場合、不正なトークンの場合と主体のユーザーを論理削除した場合はErrUnauthorized、主体が異なる場合はErrForbidden
+func (playerTokenUseCase *PlayerTokenUseCase) AuthorizePlayer(ctx context.Context, token string, userID int) error {
+	// トークンが未指定
+	if token == "" {
+		return domain.NewUnauthorizedError("player_token_required", "プレイヤーのトークンが指定されていません。")
+	}
+
+	// トークンを検証する
+	subject, err := playerTokenUseCase.playerTokenService.Verify(token, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// 主体とユーザーが一致することを確認する
+	if subject != userID {
+		return domain.NewForbiddenError("player_token_subject_mismatch", fmt.Sprintf("プレイヤーのトークンでは他のユーザーを操作できません。ユーザーID: %d", userID))
+	}
+
+	// 発行後に論理削除したユーザーのトークンは有効期限内でも拒否する
+	_, err = playerTokenUseCase.userRepository.FindByID(ctx, subject)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewUnauthorizedError("player_token_subject_deleted", fmt.Sprintf("プレイヤーのトークンの主体のユーザーは削除されています。ユーザーID: %d", subject))
+	}
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[PlayerTokenUseCase.AuthorizePlayer] Failed to fetch user: %v", err)
+		return err
+	}
+	return nil
+}
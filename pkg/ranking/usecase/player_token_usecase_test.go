@@ -0,0 +1,43 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 発行したトークンでは主体のユーザーのみ操作でき、存在しないユーザーと削除したユーザーには発行しない
+// 発行後に削除したユーザーのトークンは拒否する
+func TestPlayerToken(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+
+	token, err := u.playerToken.IssuePlayerToken(ctx, alice.ID)
+	require.NoError(t, err)
+
+	// 主体のユーザーのみ操作できる
+	assert.NoError(t, u.playerToken.AuthorizePlayer(ctx, token.Token, alice.ID))
+	assert.ErrorIs(t, u.playerToken.AuthorizePlayer(ctx, token.Token, bob.ID), domain.ErrForbidden)
+
+	// トークンが未指定または不正
+	assert.ErrorIs(t, u.playerToken.AuthorizePlayer(ctx, "", alice.ID), domain.ErrUnauthorized)
+	assert.ErrorIs(t, u.playerToken.AuthorizePlayer(ctx, token.Token+"x", alice.ID), domain.ErrUnauthorized)
+
+	// 存在しないユーザーと削除したユーザーには発行しない
+	_, err = u.playerToken.IssuePlayerToken(ctx, 9999)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	require.NoError(t, u.user.DeleteUser(ctx, bob.ID))
+	_, err = u.playerToken.IssuePlayerToken(ctx, bob.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// 発行後に削除したユーザーのトークンは拒否する
+	require.NoError(t, u.user.DeleteUser(ctx, alice.ID))
+	assert.ErrorIs(t, u.playerToken.AuthorizePlayer(ctx, token.Token, alice.ID), domain.ErrUnauthorized)
+}
@@ -4,8 +4,39 @@ import "time"
 
 // ランキングDTO
 type RankingDto struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
+	ID         int       `json:"id"`
+	GameID     int       `json:"game_id"`
+	Name       string    `json:"name"`
+	SortOrder  string    `json:"sort_order"`
+	ScoreType  string    `json:"score_type"`
+	ScoreScale int       `json:"score_scale"`
+	MinScore   *ScoreDto `json:"min_score"`
+	MaxScore   *ScoreDto `json:"max_score"`
+	ScoreStep  *ScoreDto `json:"score_step"`
+
+	// シーズンの周期とタイムゾーン、進行中のシーズン (周期なしの場合はnull)
+	Recurrence    *string          `json:"recurrence"`
+	Timezone      *string          `json:"timezone"`
+	CurrentSeason *SeasonPeriodDto `json:"current_season"`
+
+	// スコアの妥当性のルール
+	PlausibilityRules PlausibilityRulesDto `json:"plausibility_rules"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// 進行中のシーズンの期間DTO
+type SeasonPeriodDto struct {
+	Season    int       `json:"season"`
+	StartedAt time.Time `json:"started_at"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+// スコアの妥当性のルールDTO (無効なルールはnull)
+type PlausibilityRulesDto struct {
+	MaxImprovementRatio     *float64  `json:"max_improvement_ratio"`
+	MinSubmissionIntervalMs *int64    `json:"min_submission_interval_ms"`
+	ScoreCap                *ScoreDto `json:"score_cap"`
+	MaxZScore               *float64  `json:"max_z_score"`
+}
@@ -2,51 +2,66 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
 )
 
 // ランキングユースケース
 type RankingUseCase struct {
-	rankingRepository domain.RankingRepositoryInterface
+	gameRepository     domain.GameRepositoryInterface
+	rankingRepository  domain.RankingRepositoryInterface
+	userRankIndex      UserRankIndexInterface
+	transactionManager TransactionManagerInterface
 }
 
-// ユースケースを生成する
-func NewRankingUseCase(r domain.RankingRepositoryInterface) *RankingUseCase {
+// ユースケースを生成する (ランク付けのインデックスを使わない場合はuserRankIndexにnilを渡す)
+func NewRankingUseCase(g domain.GameRepositoryInterface, r domain.RankingRepositoryInterface, userRankIndex UserRankIndexInterface, tm TransactionManagerInterface) *RankingUseCase {
 	return &RankingUseCase{
-		rankingRepository: r,
+		gameRepository:     g,
+		rankingRepository:  r,
+		userRankIndex:      userRankIndex,
+		transactionManager: tm,
 	}
 }
 
 // ランキング一覧を取得する
-func (rankingUseCase *RankingUseCase) GetRankings(ctx context.Context) ([]RankingDto, error) {
+func (rankingUseCase *RankingUseCase) GetRankings(ctx context.Context, page domain.IDPageRequest) (*PageDto[RankingDto], error) {
 	// ランキング一覧をリポジトリから取得する
-	rankings, err := rankingUseCase.rankingRepository.FindAll(ctx)
+	rankings, err := rankingUseCase.rankingRepository.FindAll(ctx, page)
 	if err != nil {
 		log.Printf("[RankingUseCase.GetRankings] Failed to fetch rankings: %v", err)
 		return nil, err
 	}
 
-	// ユースケース層の構造体にマッピング (スライスの容量を事前に確保)
-	rankingDtos := make([]RankingDto, 0, len(rankings))
-	for _, r := range rankings {
-		// ユーザーDTOにマッピング
-		rankingDtos = append(rankingDtos, RankingDto{
-			ID:        r.ID,
-			Name:      r.Name.Value,
-			CreatedAt: r.CreatedAt,
-			UpdatedAt: r.UpdatedAt,
-		})
+	// ユースケースのランキングを返す
+	return toRankingPageDto(rankings, page), nil
+}
+
+// ゲームに属するランキング一覧を取得する
+func (rankingUseCase *RankingUseCase) GetRankingsByGame(ctx context.Context, gameID int, page domain.IDPageRequest) (*PageDto[RankingDto], error) {
+	// ゲームの存在確認
+	if _, err := rankingUseCase.gameRepository.FindByID(ctx, gameID); err != nil {
+		log.Printf("[RankingUseCase.GetRankingsByGame] Failed to fetch game: %v", err)
+		return nil, err
+	}
+
+	// ランキング一覧をリポジトリから取得する
+	rankings, err := rankingUseCase.rankingRepository.FindAllByGameID(ctx, gameID, page)
+	if err != nil {
+		log.Printf("[RankingUseCase.GetRankingsByGame] Failed to fetch rankings: %v", err)
+		return nil, err
 	}
 
 	// ユースケースのランキングを返す
-	return rankingDtos, nil
+	return toRankingPageDto(rankings, page), nil
 }
 
 // ランキングを新規登録する
-func (rankingUseCase *RankingUseCase) CreateRanking(ctx context.Context, name string) (*RankingDto, error) {
+func (rankingUseCase *RankingUseCase) CreateRanking(ctx context.Context, command CreateRankingCommand) (*RankingDto, error) {
 	// ランキング名
-	rankingName, err := domain.NewRankingName(name)
+	rankingName, err := domain.NewRankingName(command.Name)
 
 	// エラーハンドリング
 	if err != nil {
@@ -54,33 +69,227 @@ func (rankingUseCase *RankingUseCase) CreateRanking(ctx context.Context, name st
 		return nil, err
 	}
 
-	// ランキング名が既に登録されているか確認
-	ranking, err := rankingUseCase.rankingRepository.FindByName(ctx, rankingName)
+	// 並び順 (未指定の場合はスコアが大きいほど上位)
+	rankingSortOrder, err := domain.NewSortOrder(command.SortOrder)
 
 	// エラーハンドリング
 	if err != nil {
-		log.Printf("[RankingUseCase.CreateRanking] Failed to fetch ranking: %v", err)
+		log.Printf("[RankingUseCase.CreateRanking] invalid sort_order: %v", err)
 		return nil, err
 	}
-	if ranking != nil {
-		log.Printf("[RankingUseCase.CreateRanking] ranking name %v already used", err)
+
+	// スコアの型 (未指定の場合は整数)
+	rankingScoreType, err := domain.NewScoreType(command.ScoreType, command.ScoreScale)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[RankingUseCase.CreateRanking] invalid score_type: %v", err)
+		return nil, err
+	}
+
+	// スコアの制約 (未指定の場合は制約なし)
+	rankingScoreRules, err := domain.NewScoreRules(rankingScoreType, command.MinScore, command.MaxScore, command.ScoreStep)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[RankingUseCase.CreateRanking] invalid score_rules: %v", err)
 		return nil, err
 	}
 
-	// リポジトリを使ってランキングを登録する
-	user, err := rankingUseCase.rankingRepository.Create(ctx, rankingName)
+	// シーズンの繰り返し (未指定の場合は周期なし)
+	recurrence, err := domain.NewRecurrence(command.Recurrence, command.Timezone)
 
 	// エラーハンドリング
 	if err != nil {
-		log.Printf("[RankingUseCase.CreateRanking] Failed to create new ranking: %v", err)
+		log.Printf("[RankingUseCase.CreateRanking] invalid recurrence: %v", err)
+		return nil, err
+	}
+
+	// 登録するランキング (周期がある場合は現在時刻を含む期間を最初のシーズンとする)
+	newRanking := &domain.Ranking{
+		GameID:     command.GameID,
+		Name:       rankingName,
+		SortOrder:  rankingSortOrder,
+		ScoreType:  rankingScoreType,
+		ScoreRules: rankingScoreRules,
+		Recurrence: recurrence,
+	}
+	if recurrence.IsRecurring() {
+		season := recurrence.SeasonAt(1, time.Now())
+		newRanking.Season = &season
+	}
+
+	// ゲームの存在確認、名前の重複確認と登録を同一トランザクション内で行う
+	gameID := command.GameID
+	var ranking *domain.Ranking
+	err = rankingUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		// ゲームの存在確認
+		if _, err := rankingUseCase.gameRepository.FindByID(ctx, gameID); err != nil {
+			log.Printf("[RankingUseCase.CreateRanking] Failed to fetch game: %v", err)
+			return err
+		}
+
+		// ランキング名がゲーム内で既に登録されているか確認
+		registered, err := rankingUseCase.rankingRepository.FindByName(ctx, gameID, rankingName)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[RankingUseCase.CreateRanking] Failed to fetch ranking: %v", err)
+			return err
+		}
+		if registered != nil {
+			log.Printf("[RankingUseCase.CreateRanking] ranking name %v already used in game %d", rankingName.Value, gameID)
+			return domain.NewConflictError("ranking_name_conflict", fmt.Sprintf("ランキング名はゲーム内で既に使用されています。入力された名前: %q", rankingName.Value))
+		}
+
+		// リポジトリを使ってランキングを登録する
+		ranking, err = rankingUseCase.rankingRepository.Create(ctx, newRanking)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[RankingUseCase.CreateRanking] Failed to create new ranking: %v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// ユースケースのランキングを返す
+	return toRankingDto(ranking), nil
+}
+
+// ランキングのスコアの妥当性のルールを更新する
+func (rankingUseCase *RankingUseCase) UpdatePlausibilityRules(ctx context.Context, id int, command UpdatePlausibilityRulesCommand) (*RankingDto, error) {
+	// 送信間隔の下限
+	var minSubmissionInterval *time.Duration
+	if command.MinSubmissionIntervalMs != nil {
+		interval := time.Duration(*command.MinSubmissionIntervalMs) * time.Millisecond
+		minSubmissionInterval = &interval
+	}
+
+	// スコアの型を参照するため、ルールの検証と更新を同一トランザクション内で行う
+	var ranking *domain.Ranking
+	err := rankingUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		// ランキングの存在確認
+		registered, err := rankingUseCase.rankingRepository.FindByID(ctx, id)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[RankingUseCase.UpdatePlausibilityRules] Failed to fetch ranking: %v", err)
+			return err
+		}
+
+		// スコアの妥当性のルール (スコアの上限はランキングのスコアの型で解釈する)
+		rules, err := domain.NewPlausibilityRules(registered.ScoreType, command.MaxImprovementRatio, minSubmissionInterval, command.ScoreCap, command.MaxZScore)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[RankingUseCase.UpdatePlausibilityRules] invalid plausibility_rules: %v", err)
+			return err
+		}
+
+		// リポジトリを使ってルールを更新する
+		ranking, err = rankingUseCase.rankingRepository.UpdatePlausibilityRules(ctx, id, rules)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[RankingUseCase.UpdatePlausibilityRules] Failed to update plausibility rules: %v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// ユースケースのユーザーを返す
-	return &RankingDto{
-		ID:        user.ID,
-		Name:      user.Name.Value,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, nil
+	// ユースケースのランキングを返す
+	return toRankingDto(ranking), nil
+}
+
+// ランキングを削除する (ユーザーハイスコアやシーズンなどランキングに属するデータも削除する)
+func (rankingUseCase *RankingUseCase) DeleteRanking(ctx context.Context, id int) error {
+	// トランザクション内でリポジトリを使ってランキングを削除する
+	err := rankingUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		return rankingUseCase.rankingRepository.Delete(ctx, id)
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[RankingUseCase.DeleteRanking] Failed to delete ranking: %v", err)
+		return err
+	}
+
+	// ランク付けのインデックスからもランキングを取り除く
+	if rankingUseCase.userRankIndex != nil {
+		rankingUseCase.userRankIndex.Reset(id)
+	}
+	return nil
+}
+
+// ランキングDTOにマッピングする
+func toRankingDto(ranking *domain.Ranking) *RankingDto {
+	rankingDto := &RankingDto{
+		ID:         ranking.ID,
+		GameID:     ranking.GameID,
+		Name:       ranking.Name.Value,
+		SortOrder:  string(ranking.SortOrder),
+		ScoreType:  string(ranking.ScoreType.Kind),
+		ScoreScale: ranking.ScoreType.Scale,
+		MinScore:   newOptionalScoreDto(ranking.ScoreRules.Min, ranking.ScoreType),
+		MaxScore:   newOptionalScoreDto(ranking.ScoreRules.Max, ranking.ScoreType),
+		ScoreStep:  newOptionalScoreDto(ranking.ScoreRules.Step, ranking.ScoreType),
+		CreatedAt:  ranking.CreatedAt,
+		UpdatedAt:  ranking.UpdatedAt,
+	}
+
+	// 周期がある場合はシーズン
+	if ranking.Recurrence.IsRecurring() {
+		recurrence := string(ranking.Recurrence.Period)
+		timezone := ranking.Recurrence.Timezone()
+		rankingDto.Recurrence = &recurrence
+		rankingDto.Timezone = &timezone
+	}
+	if ranking.Season != nil {
+		rankingDto.CurrentSeason = &SeasonPeriodDto{
+			Season:    ranking.Season.Number,
+			StartedAt: ranking.Season.StartedAt,
+			EndsAt:    ranking.Season.EndsAt,
+		}
+	}
+	rankingDto.PlausibilityRules = toPlausibilityRulesDto(ranking.PlausibilityRules, ranking.ScoreType)
+	return rankingDto
+}
+
+// スコアの妥当性のルールDTOにマッピングする
+func toPlausibilityRulesDto(rules domain.PlausibilityRules, scoreType domain.ScoreType) PlausibilityRulesDto {
+	rulesDto := PlausibilityRulesDto{
+		MaxImprovementRatio: rules.MaxImprovementRatio,
+		ScoreCap:            newOptionalScoreDto(rules.ScoreCap, scoreType),
+		MaxZScore:           rules.MaxZScore,
+	}
+	if rules.MinSubmissionInterval != nil {
+		intervalMs := rules.MinSubmissionInterval.Milliseconds()
+		rulesDto.MinSubmissionIntervalMs = &intervalMs
+	}
+	return rulesDto
+}
+
+// ランキングのページをDTOにマッピングする
+func toRankingPageDto(rankings *domain.Page[domain.Ranking], page domain.IDPageRequest) *PageDto[RankingDto] {
+	// ユースケース層の構造体にマッピング (スライスの容量を事前に確保)
+	rankingDtos := make([]RankingDto, 0, len(rankings.Items))
+	for i := range rankings.Items {
+		rankingDtos = append(rankingDtos, *toRankingDto(&rankings.Items[i]))
+	}
+
+	hasNext, hasPrev := PageLinks(rankings.HasMore, page.CursorID > 0, page.Direction)
+	return &PageDto[RankingDto]{
+		Items:   rankingDtos,
+		HasNext: hasNext,
+		HasPrev: hasPrev,
+	}
 }
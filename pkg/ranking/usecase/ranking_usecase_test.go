@@ -0,0 +1,136 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ランキング名はゲーム内でのみユニーク
+func TestCreateRankingNameIsUniquePerGame(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	puzzle, err := u.game.CreateGame(ctx, "puzzle")
+	require.NoError(t, err)
+	racing, err := u.game.CreateGame(ctx, "racing")
+	require.NoError(t, err)
+
+	_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: puzzle.ID, Name: "weekly"})
+	require.NoError(t, err)
+
+	// 同じゲームでは重複できない
+	_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: puzzle.ID, Name: "weekly"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	// 別のゲームであれば同じ名前を使える
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: racing.ID, Name: "weekly"})
+	require.NoError(t, err)
+	assert.Equal(t, racing.ID, ranking.GameID)
+
+	// 並び順は未指定の場合はスコアが大きいほど上位、不正な値は指定できない
+	assert.Equal(t, "desc", ranking.SortOrder)
+	_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: racing.ID, Name: "daily", SortOrder: "lowest"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	// 存在しないゲームにはランキングを登録できない
+	_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: racing.ID + 1, Name: "weekly"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// ゲーム別のランキング一覧はそのゲームのランキングだけをページングして返す
+func TestGetRankingsByGame(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	puzzle, err := u.game.CreateGame(ctx, "puzzle")
+	require.NoError(t, err)
+	racing, err := u.game.CreateGame(ctx, "racing")
+	require.NoError(t, err)
+	for _, name := range []string{"daily", "weekly", "monthly"} {
+		_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: puzzle.ID, Name: name})
+		require.NoError(t, err)
+	}
+	_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: racing.ID, Name: "daily"})
+	require.NoError(t, err)
+
+	// 1ページ目
+	page, err := usecase.NewIDPageRequest(0, "", 2)
+	require.NoError(t, err)
+	rankings, err := u.ranking.GetRankingsByGame(ctx, puzzle.ID, page)
+	require.NoError(t, err)
+	require.Len(t, rankings.Items, 2)
+	assert.Equal(t, "daily", rankings.Items[0].Name)
+	assert.True(t, rankings.HasNext)
+	assert.False(t, rankings.HasPrev)
+
+	// 2ページ目
+	page, err = usecase.NewIDPageRequest(rankings.Items[1].ID, domain.PageNext, 2)
+	require.NoError(t, err)
+	rankings, err = u.ranking.GetRankingsByGame(ctx, puzzle.ID, page)
+	require.NoError(t, err)
+	require.Len(t, rankings.Items, 1)
+	assert.Equal(t, "monthly", rankings.Items[0].Name)
+	assert.False(t, rankings.HasNext)
+	assert.True(t, rankings.HasPrev)
+}
+
+// ランキングを削除すると属するハイスコアも削除される
+func TestDeleteRanking(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	user, err := u.user.CreateUser(ctx, "user")
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID, Score: "100"})
+	require.NoError(t, err)
+
+	require.NoError(t, u.ranking.DeleteRanking(ctx, ranking.ID))
+	_, err = u.queryService.FetchUserStanding(ctx, ranking.ID, user.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// 削除済みのランキングはNotFound
+	assert.ErrorIs(t, u.ranking.DeleteRanking(ctx, ranking.ID), domain.ErrNotFound)
+}
+
+// スコアの妥当性のルールはランキングのスコアの型で検証して更新し、未指定のルールは無効にする
+func TestUpdatePlausibilityRules(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking", ScoreType: "decimal", ScoreScale: 2})
+	require.NoError(t, err)
+	assert.Nil(t, ranking.PlausibilityRules.ScoreCap)
+
+	ratio := 1.5
+	interval := int64(30000)
+	updated, err := u.ranking.UpdatePlausibilityRules(ctx, ranking.ID, usecase.UpdatePlausibilityRulesCommand{MaxImprovementRatio: &ratio, MinSubmissionIntervalMs: &interval, ScoreCap: "99.5"})
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, *updated.PlausibilityRules.MaxImprovementRatio)
+	assert.Equal(t, int64(30000), *updated.PlausibilityRules.MinSubmissionIntervalMs)
+	assert.Equal(t, "99.50", updated.PlausibilityRules.ScoreCap.String())
+	assert.Nil(t, updated.PlausibilityRules.MaxZScore)
+
+	// 未指定のルールは無効になる
+	updated, err = u.ranking.UpdatePlausibilityRules(ctx, ranking.ID, usecase.UpdatePlausibilityRulesCommand{})
+	require.NoError(t, err)
+	assert.Nil(t, updated.PlausibilityRules.MaxImprovementRatio)
+	assert.Nil(t, updated.PlausibilityRules.ScoreCap)
+
+	// 1以下の倍率や桁数を超える上限はバリデーションエラー
+	invalidRatio := 1.0
+	_, err = u.ranking.UpdatePlausibilityRules(ctx, ranking.ID, usecase.UpdatePlausibilityRulesCommand{MaxImprovementRatio: &invalidRatio})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	_, err = u.ranking.UpdatePlausibilityRules(ctx, ranking.ID, usecase.UpdatePlausibilityRulesCommand{ScoreCap: "1.234"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	// 存在しないランキングはNotFound
+	_, err = u.ranking.UpdatePlausibilityRules(ctx, ranking.ID+1, usecase.UpdatePlausibilityRulesCommand{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import "practice-go-game-ranking/pkg/ranking/domain"
+
+// スコアの審査内容
+type ReviewScoreCommand struct {
+	// 審査する送信履歴
+	SubmissionID int
+
+	// 審査したAPIキー
+	Reviewer *domain.APIKey
+
+	// 審査の理由 (空文字の場合は理由なし)
+	Reason string
+
+	// 却下と同時にユーザーをシャドウバンするか (却下する場合のみ指定できる)
+	ShadowBan bool
+}
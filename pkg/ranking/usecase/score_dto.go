@@ -0,0 +1,37 @@
+package usecase
+
+import "practice-go-game-ranking/pkg/ranking/domain"
+
+// スコアDTO
+// ランキングのスコアの型に応じた表記の数値としてJSONに書き出す (固定小数点数は小数点以下を桁数分だけ表示する)
+type ScoreDto struct {
+	Value domain.Score
+	Type  domain.ScoreType
+}
+
+// スコアDTOを生成する
+func NewScoreDto(value domain.Score, scoreType domain.ScoreType) ScoreDto {
+	return ScoreDto{
+		Value: value,
+		Type:  scoreType,
+	}
+}
+
+// 省略可能なスコアのDTOを生成する (nilの場合はnil)
+func newOptionalScoreDto(value *domain.Score, scoreType domain.ScoreType) *ScoreDto {
+	if value == nil {
+		return nil
+	}
+	score := NewScoreDto(*value, scoreType)
+	return &score
+}
+
+// スコアの型に応じた表記の文字列を返す
+func (score ScoreDto) String() string {
+	return score.Type.Format(score.Value)
+}
+
+// スコアの型に応じた表記の数値としてJSONに書き出す (浮動小数点数を経由しない)
+func (score ScoreDto) MarshalJSON() ([]byte, error) {
+	return []byte(score.String()), nil
+}
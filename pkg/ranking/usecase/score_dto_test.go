@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"encoding/json"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スコアはスコアの型に応じた表記の数値としてJSONに書き出される
+func TestScoreDtoMarshalJSON(t *testing.T) {
+	decimal := domain.ScoreType{Kind: domain.ScoreKindDecimal, Scale: 2}
+	body, err := json.Marshal(map[string]any{
+		"decimal":  NewScoreDto(1050, decimal),
+		"integer":  NewScoreDto(-3, domain.ScoreType{Kind: domain.ScoreKindInteger}),
+		"previous": (*ScoreDto)(nil),
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"decimal": 10.50, "integer": -3, "previous": null}`, string(body))
+	assert.Contains(t, string(body), `"decimal":10.50`)
+}
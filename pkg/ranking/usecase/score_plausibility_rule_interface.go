@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// スコアの妥当性のルール (インターフェース)
+// 送信されたスコアを評価し、疑わしいスコアはハイスコアに反映せずに審査待ちにする。閾値はランキングのルールの設定から読む
+type ScorePlausibilityRuleInterface interface {
+	// ルールの名前 (審査待ちにした理由として送信履歴に記録する)
+	Name() string
+
+	// 送信されたスコアが疑わしいかを判定する
+	IsSuspicious(ctx context.Context, candidate ScoreCandidate) (bool, error)
+}
+
+// 評価する送信されたスコア
+type ScoreCandidate struct {
+	Ranking *domain.Ranking
+	UserID  int
+	Score   domain.Score
+
+	// 現在のハイスコア (未登録の場合はnil)
+	CurrentHighScore *domain.UserHighScore
+
+	// 送信日時
+	SubmittedAt time.Time
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+// 標準で評価するスコアの妥当性のルールを生成する
+func NewScorePlausibilityRules(userHighScoreRepo domain.UserHighScoreRepositoryInterface, scoreSubmissionRepo domain.ScoreSubmissionRepositoryInterface) []ScorePlausibilityRuleInterface {
+	return []ScorePlausibilityRuleInterface{
+		&improvementRatioRule{},
+		&submissionIntervalRule{scoreSubmissionRepository: scoreSubmissionRepo},
+		&scoreCapRule{},
+		&zScoreRule{userHighScoreRepository: userHighScoreRepo},
+	}
+}
+
+// 前回のハイスコアからの改善の倍率のルール
+type improvementRatioRule struct{}
+
+// ルールの名前
+func (rule *improvementRatioRule) Name() string {
+	return domain.PlausibilityRuleImprovementRatio
+}
+
+// 現在のハイスコアから倍率の上限を超えて改善したスコアを疑わしいと判定する
+func (rule *improvementRatioRule) IsSuspicious(ctx context.Context, candidate ScoreCandidate) (bool, error) {
+	if candidate.CurrentHighScore == nil {
+		return false, nil
+	}
+	return candidate.Ranking.PlausibilityRules.ExceedsImprovementRatio(&candidate.CurrentHighScore.Score, candidate.Score, candidate.Ranking.SortOrder), nil
+}
+
+// 送信の最短間隔のルール
+type submissionIntervalRule struct {
+	scoreSubmissionRepository domain.ScoreSubmissionRepositoryInterface
+}
+
+// ルールの名前
+func (rule *submissionIntervalRule) Name() string {
+	return domain.PlausibilityRuleSubmissionInterval
+}
+
+// 同じユーザーの前回の送信から最短間隔が経っていないスコアを疑わしいと判定する
+func (rule *submissionIntervalRule) IsSuspicious(ctx context.Context, candidate ScoreCandidate) (bool, error) {
+	rules := candidate.Ranking.PlausibilityRules
+	if rules.MinSubmissionInterval == nil {
+		return false, nil
+	}
+
+	// 前回の送信
+	latest, err := rule.scoreSubmissionRepository.FindLatest(ctx, candidate.Ranking.ID, candidate.UserID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ScorePlausibilityRule.submissionInterval] Failed to fetch latest submission: %v", err)
+		return false, err
+	}
+	if latest == nil {
+		return false, nil
+	}
+	return rules.IsTooSoon(&latest.SubmittedAt, candidate.SubmittedAt), nil
+}
+
+// スコアの上限のルール
+type scoreCapRule struct{}
+
+// ルールの名前
+func (rule *scoreCapRule) Name() string {
+	return domain.PlausibilityRuleScoreCap
+}
+
+// 上限を超えて上位のスコアを疑わしいと判定する
+func (rule *scoreCapRule) IsSuspicious(ctx context.Context, candidate ScoreCandidate) (bool, error) {
+	return candidate.Ranking.PlausibilityRules.ExceedsScoreCap(candidate.Score, candidate.Ranking.SortOrder), nil
+}
+
+// ハイスコアの分布に対する標準得点のルール
+type zScoreRule struct {
+	userHighScoreRepository domain.UserHighScoreRepositoryInterface
+}
+
+// ルールの名前
+func (rule *zScoreRule) Name() string {
+	return domain.PlausibilityRuleZScore
+}
+
+// 現在のハイスコアの分布から上位側に外れたスコアを疑わしいと判定する
+func (rule *zScoreRule) IsSuspicious(ctx context.Context, candidate ScoreCandidate) (bool, error) {
+	rules := candidate.Ranking.PlausibilityRules
+	if rules.MaxZScore == nil {
+		return false, nil
+	}
+
+	// ランキングのハイスコアの分布
+	stats, err := rule.userHighScoreRepository.FetchScoreStats(ctx, candidate.Ranking.ID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[ScorePlausibilityRule.zScore] Failed to fetch score stats: %v", err)
+		return false, err
+	}
+	return rules.ExceedsZScore(stats, candidate.Score, candidate.Ranking.SortOrder), nil
+}
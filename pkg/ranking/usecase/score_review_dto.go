@@ -0,0 +1,21 @@
+package usecase
+
+import "time"
+
+// スコアの審査DTO
+type ScoreReviewDto struct {
+	ID           int       `json:"id"`
+	SubmissionID int       `json:"submission_id"`
+	RankingID    int       `json:"ranking_id"`
+	UserID       int       `json:"user_id"`
+	Score        ScoreDto  `json:"score"`
+	Decision     string    `json:"decision"`
+	Reviewer     string    `json:"reviewer"`
+	Reason       string    `json:"reason"`
+	ShadowBanned bool      `json:"shadow_banned"`
+	FlaggedAt    time.Time `json:"flagged_at"`
+	ReviewedAt   time.Time `json:"reviewed_at"`
+
+	// 承認したスコアのハイスコアへの登録結果 (却下した場合はnull)
+	Outcome *string `json:"outcome"`
+}
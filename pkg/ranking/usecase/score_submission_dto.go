@@ -0,0 +1,24 @@
+package usecase
+
+import "time"
+
+// スコアの送信履歴DTO
+type ScoreSubmissionDto struct {
+	ID          int               `json:"id"`
+	RankingID   int               `json:"ranking_id"`
+	UserID      int               `json:"user_id"`
+	Score       ScoreDto          `json:"score"`
+	Client      ClientMetadataDto `json:"client"`
+	SubmittedAt time.Time         `json:"submitted_at"`
+
+	// 受け付けた状態と、審査待ちにした理由のルールの名前
+	Status       string   `json:"status"`
+	FlaggedRules []string `json:"flagged_rules"`
+}
+
+// スコアを送信したクライアントの情報DTO
+type ClientMetadataDto struct {
+	IPAddress     string `json:"ip_address"`
+	UserAgent     string `json:"user_agent"`
+	ClientVersion string `json:"client_version"`
+}
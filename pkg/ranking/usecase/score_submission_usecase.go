@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+// スコアの送信履歴ユースケース
+type ScoreSubmissionUseCase struct {
+	rankingRepository         domain.RankingRepositoryInterface
+	userRepository            domain.UserRepositoryInterface
+	scoreSubmissionRepository domain.ScoreSubmissionRepositoryInterface
+}
+
+// ユースケースを生成する
+func NewScoreSubmissionUseCase(rankingRepo domain.RankingRepositoryInterface, userRepo domain.UserRepositoryInterface, scoreSubmissionRepo domain.ScoreSubmissionRepositoryInterface) *ScoreSubmissionUseCase {
+	return &ScoreSubmissionUseCase{
+		rankingRepository:         rankingRepo,
+		userRepository:            userRepo,
+		scoreSubmissionRepository: scoreSubmissionRepo,
+	}
+}
+
+// ランキングとユーザーを指定して、期間内のスコアの送信履歴を送信順に取得する
+func (scoreSubmissionUseCase *ScoreSubmissionUseCase) GetScoreSubmissions(ctx context.Context, rankingID int, userID int, timeRange domain.TimeRange, page domain.IDPageRequest) (*PageDto[ScoreSubmissionDto], error) {
+	// ランキングの存在確認 (スコアの型も表記に使う)
+	ranking, err := scoreSubmissionUseCase.rankingRepository.FindByID(ctx, rankingID)
+	if err != nil {
+		log.Printf("[ScoreSubmissionUseCase.GetScoreSubmissions] Failed to fetch ranking: %v", err)
+		return nil, err
+	}
+
+	// ユーザーの存在確認
+	if _, err := scoreSubmissionUseCase.userRepository.FindByID(ctx, userID); err != nil {
+		log.Printf("[ScoreSubmissionUseCase.GetScoreSubmissions] Failed to fetch user: %v", err)
+		return nil, err
+	}
+
+	// 送信履歴をリポジトリから取得する
+	submissions, err := scoreSubmissionUseCase.scoreSubmissionRepository.FindAllByRankingIDAndUserID(ctx, rankingID, userID, timeRange, page)
+	if err != nil {
+		log.Printf("[ScoreSubmissionUseCase.GetScoreSubmissions] Failed to fetch score submissions: %v", err)
+		return nil, err
+	}
+
+	// ユースケース層の構造体にマッピング (スライスの容量を事前に確保)
+	submissionDtos := make([]ScoreSubmissionDto, 0, len(submissions.Items))
+	for i := range submissions.Items {
+		submissionDtos = append(submissionDtos, toScoreSubmissionDto(&submissions.Items[i], ranking.ScoreType))
+	}
+
+	// ユースケースの送信履歴を返す
+	hasNext, hasPrev := PageLinks(submissions.HasMore, page.CursorID > 0, page.Direction)
+	return &PageDto[ScoreSubmissionDto]{
+		Items:   submissionDtos,
+		HasNext: hasNext,
+		HasPrev: hasPrev,
+	}, nil
+}
+
+// 送信履歴DTOにマッピングする
+func toScoreSubmissionDto(submission *domain.ScoreSubmission, scoreType domain.ScoreType) ScoreSubmissionDto {
+	// 受け付けた送信でも空の配列として書き出す
+	flaggedRules := submission.FlaggedRules
+	if flaggedRules == nil {
+		flaggedRules = []string{}
+	}
+	return ScoreSubmissionDto{
+		ID:        submission.ID,
+		RankingID: submission.RankingID,
+		UserID:    submission.UserID,
+		Score:     NewScoreDto(submission.Score, scoreType),
+		Client: ClientMetadataDto{
+			IPAddress:     submission.Client.IPAddress,
+			UserAgent:     submission.Client.UserAgent,
+			ClientVersion: submission.Client.ClientVersion,
+		},
+		SubmittedAt:  submission.SubmittedAt,
+		Status:       string(submission.Status),
+		FlaggedRules: flaggedRules,
+	}
+}
@@ -0,0 +1,72 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ハイスコアを更新したかどうかに関わらず、送信されたスコアはすべて送信順に記録される
+func TestGetScoreSubmissions(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+
+	// 更新、据え置き、更新の順に送信する (他のユーザーの送信は含まれない)
+	client := domain.ClientMetadata{IPAddress: "192.0.2.1", UserAgent: "game-client", ClientVersion: "1.2.3"}
+	for _, score := range []string{"100", "50", "150"} {
+		_, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: score, Client: client})
+		require.NoError(t, err)
+	}
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "200"})
+	require.NoError(t, err)
+
+	// 先頭のページ
+	page, err := usecase.NewIDPageRequest(0, "", 2)
+	require.NoError(t, err)
+	submissions, err := u.submission.GetScoreSubmissions(ctx, ranking.ID, alice.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	require.Len(t, submissions.Items, 2)
+	assert.Equal(t, "100", submissions.Items[0].Score.String())
+	assert.Equal(t, "50", submissions.Items[1].Score.String())
+	assert.Equal(t, "192.0.2.1", submissions.Items[0].Client.IPAddress)
+	assert.Equal(t, "1.2.3", submissions.Items[0].Client.ClientVersion)
+	assert.True(t, submissions.HasNext)
+	assert.False(t, submissions.HasPrev)
+
+	// 続きのページ
+	page, err = usecase.NewIDPageRequest(submissions.Items[1].ID, domain.PageNext, 2)
+	require.NoError(t, err)
+	submissions, err = u.submission.GetScoreSubmissions(ctx, ranking.ID, alice.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	require.Len(t, submissions.Items, 1)
+	assert.Equal(t, "150", submissions.Items[0].Score.String())
+	assert.False(t, submissions.HasNext)
+	assert.True(t, submissions.HasPrev)
+
+	// 期間外の送信は含まれない
+	from := time.Now().Add(time.Hour)
+	timeRange, err := domain.NewTimeRange(&from, nil)
+	require.NoError(t, err)
+	page, err = usecase.NewIDPageRequest(0, "", 0)
+	require.NoError(t, err)
+	submissions, err = u.submission.GetScoreSubmissions(ctx, ranking.ID, alice.ID, timeRange, page)
+	require.NoError(t, err)
+	assert.Empty(t, submissions.Items)
+
+	// 存在しないユーザー
+	_, err = u.submission.GetScoreSubmissions(ctx, ranking.ID, bob.ID+1, domain.TimeRange{}, page)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import "time"
+
+// 終了したシーズンDTO
+type SeasonDto struct {
+	RankingID  int       `json:"ranking_id"`
+	Season     int       `json:"season"`
+	StartedAt  time.Time `json:"started_at"`
+	EndedAt    time.Time `json:"ended_at"`
+	TotalUsers int       `json:"total_users"`
+}
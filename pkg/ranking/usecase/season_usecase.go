@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// シーズンユースケース
+type SeasonUseCase struct {
+	rankingRepository  domain.RankingRepositoryInterface
+	seasonRepository   domain.SeasonRepositoryInterface
+	userRankIndex      UserRankIndexInterface
+	transactionManager TransactionManagerInterface
+}
+
+// ユースケースを生成する (ランク付けのインデックスを使わない場合はuserRankIndexにnilを渡す)
+func NewSeasonUseCase(rankingRepo domain.RankingRepositoryInterface, seasonRepo domain.SeasonRepositoryInterface, userRankIndex UserRankIndexInterface, tm TransactionManagerInterface) *SeasonUseCase {
+	return &SeasonUseCase{
+		rankingRepository:  rankingRepo,
+		seasonRepository:   seasonRepo,
+		userRankIndex:      userRankIndex,
+		transactionManager: tm,
+	}
+}
+
+// ランキングの終了したシーズン一覧を取得する
+func (seasonUseCase *SeasonUseCase) GetSeasons(ctx context.Context, rankingID int, page domain.IDPageRequest) (*PageDto[SeasonDto], error) {
+	// ランキングの存在確認
+	if _, err := seasonUseCase.rankingRepository.FindByID(ctx, rankingID); err != nil {
+		log.Printf("[SeasonUseCase.GetSeasons] Failed to fetch ranking: %v", err)
+		return nil, err
+	}
+
+	// シーズン一覧をリポジトリから取得する
+	seasons, err := seasonUseCase.seasonRepository.FindAllByRankingID(ctx, rankingID, page)
+	if err != nil {
+		log.Printf("[SeasonUseCase.GetSeasons] Failed to fetch seasons: %v", err)
+		return nil, err
+	}
+
+	// ユースケース層の構造体にマッピング (スライスの容量を事前に確保)
+	seasonDtos := make([]SeasonDto, 0, len(seasons.Items))
+	for i := range seasons.Items {
+		seasonDtos = append(seasonDtos, toSeasonDto(&seasons.Items[i]))
+	}
+
+	// ユースケースのシーズンを返す
+	hasNext, hasPrev := PageLinks(seasons.HasMore, page.CursorID > 0, page.Direction)
+	return &PageDto[SeasonDto]{
+		Items:   seasonDtos,
+		HasNext: hasNext,
+		HasPrev: hasPrev,
+	}, nil
+}
+
+// 進行中のシーズンが終了したランキングのシーズンを進める
+// 終了時点のユーザーハイスコアをアーカイブし、次のシーズンは空のランキングから始める
+// 1つのランキングで失敗しても他のランキングは処理し、終了したシーズンと発生したエラーをまとめて返す
+func (seasonUseCase *SeasonUseCase) RotateSeasons(ctx context.Context, now time.Time) ([]SeasonDto, error) {
+	// シーズンが終了したランキング一覧
+	rankings, err := seasonUseCase.rankingRepository.FindAllSeasonEnded(ctx, now)
+	if err != nil {
+		log.Printf("[SeasonUseCase.RotateSeasons] Failed to fetch rankings: %v", err)
+		return nil, err
+	}
+
+	var seasons []SeasonDto
+	var errs []error
+	for _, ranking := range rankings {
+		season, err := seasonUseCase.rotateSeason(ctx, ranking.ID, now)
+		if err != nil {
+			log.Printf("[SeasonUseCase.RotateSeasons] Failed to rotate season of ranking %d: %v", ranking.ID, err)
+			errs = append(errs, err)
+			continue
+		}
+		if season != nil {
+			seasons = append(seasons, toSeasonDto(season))
+		}
+	}
+	return seasons, errors.Join(errs...)
+}
+
+// ランキングのシーズンを進める (他の処理が先に進めていた場合はnilを返す)
+func (seasonUseCase *SeasonUseCase) rotateSeason(ctx context.Context, rankingID int, now time.Time) (*domain.Season, error) {
+	// シーズンの終了確認とアーカイブを同一トランザクション内で行う
+	var ranking *domain.Ranking
+	var next domain.SeasonPeriod
+	var season *domain.Season
+	err := seasonUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		// 最新のランキングで終了を確認する
+		var err error
+		ranking, err = seasonUseCase.rankingRepository.FindByID(ctx, rankingID)
+		if err != nil {
+			return err
+		}
+		if !ranking.SeasonIsOver(now) {
+			return nil
+		}
+
+		// 現在のユーザーハイスコアをアーカイブして次のシーズンを始める
+		next = ranking.NextSeason(now)
+		season, err = seasonUseCase.seasonRepository.Archive(ctx, ranking, next)
+		return err
+	})
+
+	// 他のプロセスが先にシーズンを進めた場合は何もしない
+	if errors.Is(err, domain.ErrConflict) {
+		return nil, nil
+	}
+	if err != nil || season == nil {
+		return nil, err
+	}
+
+	// ランク付けのインデックスも次のシーズンに進める (後から届いた前のシーズンのハイスコアはインデックスが無視する)
+	if seasonUseCase.userRankIndex != nil {
+		seasonUseCase.userRankIndex.StartSeason(rankingID, next.Number, ranking.SortOrder)
+	}
+	return season, nil
+}
+
+// シーズンDTOにマッピングする
+func toSeasonDto(season *domain.Season) SeasonDto {
+	return SeasonDto{
+		RankingID:  season.RankingID,
+		Season:     season.Number,
+		StartedAt:  season.StartedAt,
+		EndedAt:    season.EndedAt,
+		TotalUsers: season.TotalUsers,
+	}
+}
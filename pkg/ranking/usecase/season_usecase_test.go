@@ -0,0 +1,135 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// シーズンが終了するとハイスコアがアーカイブされ、次のシーズンは空のランキングから始まる
+func TestRotateSeasons(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "daily", Recurrence: "daily", Timezone: "Asia/Tokyo"})
+	require.NoError(t, err)
+	require.NotNil(t, ranking.CurrentSeason)
+	assert.Equal(t, 1, ranking.CurrentSeason.Season)
+	_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "all-time"})
+	require.NoError(t, err)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "100"})
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "200"})
+	require.NoError(t, err)
+
+	// 終了前は何もしない
+	seasons, err := u.season.RotateSeasons(ctx, ranking.CurrentSeason.EndsAt.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, seasons)
+
+	// 終了後は繰り返しのあるランキングのみシーズンが進む
+	now := ranking.CurrentSeason.EndsAt.Add(time.Minute)
+	seasons, err = u.season.RotateSeasons(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, seasons, 1)
+	assert.Equal(t, ranking.ID, seasons[0].RankingID)
+	assert.Equal(t, 1, seasons[0].Season)
+	assert.Equal(t, 2, seasons[0].TotalUsers)
+
+	// 終了時点の順位がアーカイブされる
+	page, err := usecase.NewIDPageRequest(0, "", 0)
+	require.NoError(t, err)
+	archived, err := u.seasonQueryService.FetchSeasonUserRanking(ctx, ranking.ID, 1, page)
+	require.NoError(t, err)
+	require.Len(t, archived.UserRanks, 2)
+	assert.Equal(t, bob.ID, archived.UserRanks[0].UserID)
+	assert.Equal(t, 1, archived.UserRanks[0].Rank)
+	assert.Equal(t, alice.ID, archived.UserRanks[1].UserID)
+
+	// 次のシーズンは空のランキングから始まる
+	rankings, err := u.ranking.GetRankingsByGame(ctx, game.ID, page)
+	require.NoError(t, err)
+	require.NotNil(t, rankings.Items[0].CurrentSeason)
+	assert.Equal(t, 2, rankings.Items[0].CurrentSeason.Season)
+	assert.Equal(t, ranking.CurrentSeason.EndsAt, rankings.Items[0].CurrentSeason.StartedAt)
+	query, err := usecase.NewUserRankingQuery(ranking.ID, "", 0, nil)
+	require.NoError(t, err)
+	live, err := u.queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	assert.Empty(t, live.UserRanks)
+
+	// 同じ時刻で再度実行しても進まない
+	seasons, err = u.season.RotateSeasons(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, seasons)
+
+	// 終了したシーズン一覧を取得できる
+	seasonPage, err := u.season.GetSeasons(ctx, ranking.ID, page)
+	require.NoError(t, err)
+	require.Len(t, seasonPage.Items, 1)
+	assert.Equal(t, 1, seasonPage.Items[0].Season)
+
+	// 存在しないシーズン
+	_, err = u.seasonQueryService.FetchSeasonUserRanking(ctx, ranking.ID, 2, page)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// 終了したシーズンへの送信は、定期実行より先に届いても次のシーズンのハイスコアとして受け付ける
+func TestUpdateUserHighScoreAfterSeasonEnds(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "daily", Recurrence: "daily", Timezone: "Asia/Tokyo"})
+	require.NoError(t, err)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "100"})
+	require.NoError(t, err)
+
+	// 次のシーズンを既に終了した期間で始める (定期実行はまだシーズンを進めていない)
+	stored, err := u.rankingRepository.FindByID(ctx, ranking.ID)
+	require.NoError(t, err)
+	endedAt := time.Now().Add(-time.Hour)
+	_, err = u.seasonRepository.Archive(ctx, stored, domain.SeasonPeriod{Number: 2, StartedAt: endedAt.Add(-24 * time.Hour), EndsAt: endedAt})
+	require.NoError(t, err)
+
+	// 終了後の送信は送信時に次のシーズンに進めてから反映する
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "200"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScoreCreated), result.Outcome)
+	assert.Equal(t, 1, result.Rank)
+
+	// 終了したシーズンには終了後の送信をアーカイブしない
+	page, err := usecase.NewIDPageRequest(0, "", 0)
+	require.NoError(t, err)
+	archived, err := u.seasonQueryService.FetchSeasonUserRanking(ctx, ranking.ID, 2, page)
+	require.NoError(t, err)
+	assert.Empty(t, archived.UserRanks)
+
+	// 定期実行が後から動いても送信したハイスコアはアーカイブされずに残る
+	seasons, err := u.season.RotateSeasons(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, seasons)
+	query, err := usecase.NewUserRankingQuery(ranking.ID, "", 0, nil)
+	require.NoError(t, err)
+	live, err := u.queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, live.UserRanks, 1)
+	assert.Equal(t, bob.ID, live.UserRanks[0].UserID)
+	stored, err = u.rankingRepository.FindByID(ctx, ranking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.SeasonNumber())
+}
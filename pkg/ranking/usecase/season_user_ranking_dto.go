@@ -0,0 +1,16 @@
+package usecase
+
+import "time"
+
+// 終了したシーズンのユーザーランキング
+type SeasonUserRankingDto struct {
+	RankingID   int           `json:"ranking_id"`
+	RankingName string        `json:"ranking_name"`
+	Season      int           `json:"season"`
+	StartedAt   time.Time     `json:"started_at"`
+	EndedAt     time.Time     `json:"ended_at"`
+	TotalUsers  int           `json:"total_users"`
+	UserRanks   []UserRankDto `json:"user_ranks"`
+	HasNext     bool          `json:"-"`
+	HasPrev     bool          `json:"-"`
+}
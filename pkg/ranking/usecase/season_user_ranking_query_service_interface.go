@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+// 終了したシーズンのユーザーランキングのクエリサービス
+type SeasonUserRankingQueryServiceInterface interface {
+	// 終了したシーズンのユーザーランキングをランクの昇順でページングして取得する (ページのカーソルはランク)
+	// ランキングまたはシーズンが存在しない場合はErrNotFound
+	FetchSeasonUserRanking(ctx context.Context, rankingID int, season int, page domain.IDPageRequest) (*SeasonUserRankingDto, error)
+}
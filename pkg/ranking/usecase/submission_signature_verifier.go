@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
+// スコアの送信の署名の検証
+// 秘密鍵を設定したゲームのランキングへの送信のみ署名を求める
+type SubmissionSignatureVerifier struct {
+	gameRepository  domain.GameRepositoryInterface
+	nonceRepository domain.SubmissionNonceRepositoryInterface
+
+	// タイムスタンプと現在時刻のずれの許容範囲
+	window time.Duration
+}
+
+// 署名の検証を生成する
+func NewSubmissionSignatureVerifier(gameRepo domain.GameRepositoryInterface, nonceRepo domain.SubmissionNonceRepositoryInterface, window time.Duration) *SubmissionSignatureVerifier {
+	return &SubmissionSignatureVerifier{
+		gameRepository:  gameRepo,
+		nonceRepository: nonceRepo,
+		window:          window,
+	}
+}
+
+// ランキングへの送信の署名を検証する
+// 署名、タイムスタンプ、ナンスの順に確認し、失敗の理由ごとに異なるエラーコードを返す
+func (verifier *SubmissionSignatureVerifier) Verify(ctx context.Context, ranking *domain.Ranking, command UpdateUserHighScoreCommand) error {
+	// ランキングの属するゲームの秘密鍵を取得する
+	game, err := verifier.gameRepository.FindByID(ctx, ranking.GameID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[SubmissionSignatureVerifier.Verify] Failed to fetch game: %v", err)
+		return err
+	}
+
+	// 秘密鍵を設定していないゲームは署名を求めない
+	if game.SubmissionSecret == nil {
+		return nil
+	}
+	signature := command.Signature
+	if signature == nil {
+		return domain.NewUnauthorizedError("submission_signature_required", fmt.Sprintf("このゲームのスコアの送信には署名が必要です。ゲームID: %d", game.ID))
+	}
+
+	// 署名とタイムスタンプを検証する
+	if err := signature.Verify(*game.SubmissionSecret, command.RankingID, command.UserID, command.Score, time.Now(), verifier.window); err != nil {
+		log.Printf("[SubmissionSignatureVerifier.Verify] Rejected signature: %v", err)
+		return err
+	}
+
+	// 同じナンスの再送を拒否する (タイムスタンプで拒否されるまでナンスを保持する。送信と同一トランザクション内で記録する)
+	if err := verifier.nonceRepository.Use(ctx, game.ID, signature.Nonce, signature.ExpiresAt(verifier.window)); err != nil {
+		log.Printf("[SubmissionSignatureVerifier.Verify] Failed to use nonce %s: %v", signature.Nonce, err)
+		return err
+	}
+	return nil
+}
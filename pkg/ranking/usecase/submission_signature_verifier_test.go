@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 秘密鍵を設定したゲームでは署名を検証できた送信のみ受け付け、失敗の理由ごとに異なるエラーコードを返す
+func TestUpdateUserHighScoreSignature(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	user, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+
+	// 秘密鍵を設定するまでは署名を求めない
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID, Score: "10"})
+	require.NoError(t, err)
+	secret, err := u.game.RotateSubmissionSecret(ctx, game.ID)
+	require.NoError(t, err)
+	gameDto, err := u.game.GetGame(ctx, game.ID)
+	require.NoError(t, err)
+	assert.True(t, gameDto.SignedSubmissions)
+
+	// 秘密鍵で署名した送信のコマンドを生成する
+	command := func(score string, nonce string, signedAt time.Time, key string) usecase.UpdateUserHighScoreCommand {
+		signature, err := domain.NewSubmissionSignature(nonce, strconv.FormatInt(signedAt.Unix(), 10), domain.SignSubmission(key, ranking.ID, user.ID, score, nonce, signedAt.Unix()))
+		require.NoError(t, err)
+		return usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID, Score: score, Signature: signature}
+	}
+	assertCode := func(err error, code string) {
+		t.Helper()
+		var domainError *domain.Error
+		require.ErrorAs(t, err, &domainError)
+		assert.Equal(t, code, domainError.Code)
+	}
+	now := time.Now()
+
+	// 署名がない
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID, Score: "100"})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assertCode(err, "submission_signature_required")
+
+	// 正しい署名
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, command("100", "nonce-1", now, secret.Secret))
+	require.NoError(t, err)
+	assert.Equal(t, "100", result.NewScore.String())
+
+	// 同じナンスの再送
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, command("100", "nonce-1", now, secret.Secret))
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assertCode(err, "submission_nonce_replayed")
+
+	// 送信が失敗した場合はナンスを消費しない
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, command("abc", "nonce-5", now, secret.Secret))
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, command("150", "nonce-5", now, secret.Secret))
+	require.NoError(t, err)
+
+	// スコアの改ざん
+	tampered := command("100", "nonce-2", now, secret.Secret)
+	tampered.Score = "1000"
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, tampered)
+	assertCode(err, "invalid_submission_signature")
+
+	// 古いタイムスタンプ
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, command("100", "nonce-3", now.Add(-testSignatureWindow-time.Minute), secret.Secret))
+	assertCode(err, "submission_timestamp_skewed")
+
+	// 秘密鍵を生成し直すと古い秘密鍵の署名は受け付けない
+	_, err = u.game.RotateSubmissionSecret(ctx, game.ID)
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, command("200", "nonce-4", now, secret.Secret))
+	assertCode(err, "invalid_submission_signature")
+
+	// 秘密鍵を削除すると署名を求めない
+	require.NoError(t, u.game.DeleteSubmissionSecret(ctx, game.ID))
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID, Score: "200"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, u.game.DeleteSubmissionSecret(ctx, game.ID+1), domain.ErrNotFound)
+}
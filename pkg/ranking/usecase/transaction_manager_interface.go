@@ -0,0 +1,11 @@
+package usecase
+
+import "context"
+
+// トランザクションマネージャー (インターフェース)
+type TransactionManagerInterface interface {
+	// 関数をトランザクション内で実行する
+	// 関数がエラーを返した場合はロールバックし、そうでなければコミットする
+	// 既にトランザクション内で呼ばれた場合はそのトランザクションに参加する
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,16 @@
+package usecase
+
+// スコアの妥当性のルールの更新内容 (nilまたは空文字のルールは無効にする)
+type UpdatePlausibilityRulesCommand struct {
+	// 現在のハイスコアからの改善率の上限 (1より大きい倍率)
+	MaxImprovementRatio *float64
+
+	// 同じユーザーの送信間隔の下限 (ミリ秒)
+	MinSubmissionIntervalMs *int64
+
+	// スコアの上限 (スコアの型に応じた数値の文字列。昇順のランキングでは下限)
+	ScoreCap string
+
+	// ランキング全体の分布に対するzスコアの上限
+	MaxZScore *float64
+}
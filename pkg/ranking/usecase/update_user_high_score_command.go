@@ -0,0 +1,18 @@
+package usecase
+
+import "practice-go-game-ranking/pkg/ranking/domain"
+
+// ユーザーハイスコアの登録内容
+type UpdateUserHighScoreCommand struct {
+	RankingID int
+	UserID    int
+
+	// スコア (ランキングのスコアの型に応じた数値の文字列)
+	Score string
+
+	// スコアを送信したクライアントの情報 (送信履歴に記録する)
+	Client domain.ClientMetadata
+
+	// スコアの送信の署名 (署名を指定しない場合はnil)
+	Signature *domain.SubmissionSignature
+}
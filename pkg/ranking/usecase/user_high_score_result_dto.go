@@ -0,0 +1,14 @@
+package usecase
+
+// ユーザーハイスコア登録結果DTO
+type UserHighScoreResultDto struct {
+	RankingID     int       `json:"ranking_id"`
+	UserID        int       `json:"user_id"`
+	SubmissionID  int       `json:"submission_id"`
+	Outcome       string    `json:"outcome"`
+	PreviousScore *ScoreDto `json:"previous_score"`
+	NewScore      ScoreDto  `json:"new_score"`
+
+	// 登録後のランク (審査待ちでハイスコアが未登録の場合は省略する)
+	Rank int `json:"rank,omitempty"`
+}
@@ -2,58 +2,308 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"log"
 	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
 )
 
 // ユーザーハイスコアユースケース
 type UserHighScoreUseCase struct {
-	rankingRepository       domain.RankingRepositoryInterface
-	userRepository          domain.UserRepositoryInterface
-	userHighScoreRepository domain.UserHighScoreRepositoryInterface
+	rankingRepository         domain.RankingRepositoryInterface
+	userRepository            domain.UserRepositoryInterface
+	userHighScoreRepository   domain.UserHighScoreRepositoryInterface
+	scoreSubmissionRepository domain.ScoreSubmissionRepositoryInterface
+	seasonRepository          domain.SeasonRepositoryInterface
+	userRankingQueryService   UserRankingQueryServiceInterface
+	userRankIndex             UserRankIndexInterface
+	signatureVerifier         *SubmissionSignatureVerifier
+	plausibilityRules         []ScorePlausibilityRuleInterface
+	transactionManager        TransactionManagerInterface
 }
 
-// ユースケースを生成する
-func NewUserHighScoreUseCase(rankingRepo domain.RankingRepositoryInterface, userRepo domain.UserRepositoryInterface, userHighScoreRepo domain.UserHighScoreRepositoryInterface) *UserHighScoreUseCase {
+// ユースケースを生成する (ランク付けのインデックスを使わない場合はuserRankIndexにnilを渡す)
+func NewUserHighScoreUseCase(rankingRepo domain.RankingRepositoryInterface, userRepo domain.UserRepositoryInterface, userHighScoreRepo domain.UserHighScoreRepositoryInterface, scoreSubmissionRepo domain.ScoreSubmissionRepositoryInterface, seasonRepo domain.SeasonRepositoryInterface, userRankingQueryService UserRankingQueryServiceInterface, userRankIndex UserRankIndexInterface, signatureVerifier *SubmissionSignatureVerifier, plausibilityRules []ScorePlausibilityRuleInterface, tm TransactionManagerInterface) *UserHighScoreUseCase {
 	return &UserHighScoreUseCase{
-		rankingRepository:       rankingRepo,
-		userRepository:          userRepo,
-		userHighScoreRepository: userHighScoreRepo,
+		rankingRepository:         rankingRepo,
+		userRepository:            userRepo,
+		userHighScoreRepository:   userHighScoreRepo,
+		scoreSubmissionRepository: scoreSubmissionRepo,
+		seasonRepository:          seasonRepo,
+		userRankingQueryService:   userRankingQueryService,
+		userRankIndex:             userRankIndex,
+		signatureVerifier:         signatureVerifier,
+		plausibilityRules:         plausibilityRules,
+		transactionManager:        tm,
 	}
 }
 
-// ユーザーのハイスコアを更新する
-func (userHighScoreUseCase *UserHighScoreUseCase) UpdateUserHighScore(ctx context.Context, rankingID int, userID int, newScore int) error {
-	// ランキングの存在チェック
-	_, err := userHighScoreUseCase.rankingRepository.FindByID(ctx, rankingID)
+// ユーザーのハイスコアを更新する (スコアはランキングのスコアの型に応じた数値の文字列で受け取る)
+// ハイスコアを更新したかどうかに関わらず、送信されたスコアを送信履歴に記録する
+// 秘密鍵を設定したゲームのランキングでは、署名を検証できた送信のみ受け付ける
+// スコアの妥当性のルールで疑わしいと判定したスコアは、ハイスコアに反映せずに審査待ちにする
+// シャドウバンしたユーザーの送信はハイスコアに反映せず、審査待ちと同じ結果を返す
+// シーズンが終了したランキングへの送信は、定期実行を待たずに次のシーズンに進めてから受け付ける
+func (userHighScoreUseCase *UserHighScoreUseCase) UpdateUserHighScore(ctx context.Context, command UpdateUserHighScoreCommand) (*UserHighScoreResultDto, error) {
+	rankingID := command.RankingID
+	userID := command.UserID
+
+	// 存在チェックから保存までを同一トランザクション内で行う
+	var update *userHighScoreUpdate
+	err := userHighScoreUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		var err error
+		update, err = userHighScoreUseCase.updateUserHighScore(ctx, command)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 送信時にシーズンを進めた場合はランク付けのインデックスも次のシーズンに進める
+	if userHighScoreUseCase.userRankIndex != nil && update.seasonStarted {
+		userHighScoreUseCase.userRankIndex.StartSeason(rankingID, update.ranking.SeasonNumber(), update.ranking.SortOrder)
+	}
+
+	// 審査待ちにした場合はハイスコアを変えずに返す (シャドウバンしたユーザーにも審査待ちと同じ結果を返す)
+	if update.submission.IsWithheld() {
+		return userHighScoreUseCase.pendingReviewResult(ctx, update)
+	}
+	upsertResult := update.upsertResult
+
+	// コミットしたハイスコアをランク付けのインデックスに反映する
+	if userHighScoreUseCase.userRankIndex != nil && upsertResult.Outcome != domain.HighScoreUnchanged {
+		userHighScoreUseCase.userRankIndex.Apply(upsertResult.HighScore, update.ranking.SeasonNumber(), update.ranking.SortOrder, update.user.Name.Value)
+	}
+
+	// 登録結果の構造体を生成
+	scoreType := update.ranking.ScoreType
+	result := &UserHighScoreResultDto{
+		RankingID:    rankingID,
+		UserID:       userID,
+		SubmissionID: update.submission.ID,
+		Outcome:      string(upsertResult.Outcome),
+		NewScore:     NewScoreDto(upsertResult.HighScore.Score, scoreType),
+	}
+	if upsertResult.PreviousScore != nil {
+		previousScore := NewScoreDto(*upsertResult.PreviousScore, scoreType)
+		result.PreviousScore = &previousScore
+	}
+
+	// 登録後のランクを取得
+	userRank, err := userHighScoreUseCase.userRankingQueryService.FetchUserRank(ctx, rankingID, userID)
 
 	// エラーハンドリング
 	if err != nil {
-		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to fetch ranking: %v", err)
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to fetch user rank: %v", err)
+		return nil, err
+	}
+	result.Rank = userRank.Rank
+
+	// 登録結果を返す
+	return result, nil
+}
+
+// 審査待ちにした送信の登録結果を生成する (ハイスコアが登録済みの場合は現在のランクを返す)
+func (userHighScoreUseCase *UserHighScoreUseCase) pendingReviewResult(ctx context.Context, update *userHighScoreUpdate) (*UserHighScoreResultDto, error) {
+	scoreType := update.ranking.ScoreType
+	result := &UserHighScoreResultDto{
+		RankingID:    update.ranking.ID,
+		UserID:       update.user.ID,
+		SubmissionID: update.submission.ID,
+		Outcome:      string(domain.HighScorePendingReview),
+		NewScore:     NewScoreDto(update.submission.Score, scoreType),
+	}
+	if update.currentHighScore == nil {
+		return result, nil
+	}
+	previousScore := NewScoreDto(update.currentHighScore.Score, scoreType)
+	result.PreviousScore = &previousScore
+
+	// 現在のランクを取得
+	userRank, err := userHighScoreUseCase.userRankingQueryService.FetchUserRank(ctx, update.ranking.ID, update.user.ID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to fetch user rank: %v", err)
+		return nil, err
+	}
+	result.Rank = userRank.Rank
+	return result, nil
+}
+
+// ユーザーのハイスコアを削除する (不正なスコアの取り消しに使う。送信履歴は残す)
+func (userHighScoreUseCase *UserHighScoreUseCase) DeleteUserHighScore(ctx context.Context, rankingID int, userID int) error {
+	// トランザクション内でリポジトリを使ってハイスコアを削除する
+	err := userHighScoreUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		return userHighScoreUseCase.userHighScoreRepository.Delete(ctx, rankingID, userID)
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserHighScoreUseCase.DeleteUserHighScore] Failed to delete user high score: %v", err)
 		return err
 	}
 
-	// 当該ランキングが存在しない場合は更新できない
+	// ランク付けのインデックスからもハイスコアを取り除く
+	if userHighScoreUseCase.userRankIndex != nil {
+		userHighScoreUseCase.userRankIndex.Remove(rankingID, userID)
+	}
+	return nil
+}
+
+// トランザクション内でのハイスコアの更新結果
+type userHighScoreUpdate struct {
+	ranking    *domain.Ranking
+	user       *domain.User
+	submission *domain.ScoreSubmission
+
+	// 送信前のハイスコア (未登録の場合はnil)
+	currentHighScore *domain.UserHighScore
+
+	// ハイスコアの保存結果 (審査待ちにした場合はnil)
+	upsertResult *domain.UserHighScoreUpsertResult
+
+	// 送信時に終了したシーズンをアーカイブして次のシーズンに進めたかどうか
+	seasonStarted bool
+}
+
+// トランザクション内でユーザーのハイスコアを更新する
+func (userHighScoreUseCase *UserHighScoreUseCase) updateUserHighScore(ctx context.Context, command UpdateUserHighScoreCommand) (*userHighScoreUpdate, error) {
+	rankingID := command.RankingID
+	userID := command.UserID
+
+	// ランキングの存在チェック (並び順もハイスコアの比較に使う)
+	// シーズンを進める処理が読み取ったシーズンをコミットまでに進めないよう、ランキングを行ロックして読み取る
+	ranking, err := userHighScoreUseCase.rankingRepository.FindByIDForUpdate(ctx, rankingID)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to fetch ranking: %v", err)
+		return nil, err
+	}
+
+	// シーズンが終了していれば次のシーズンに進める
+	// 定期実行より先に届いた送信が終了したシーズンのハイスコアとしてアーカイブされないように、送信と同一トランザクション内で進める
+	now := time.Now()
+	seasonStarted := false
+	if ranking.SeasonIsOver(now) {
+		ranking, err = userHighScoreUseCase.rotateSeason(ctx, ranking, now)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to rotate season: %v", err)
+			return nil, err
+		}
+		seasonStarted = true
+	}
+
+	// 送信の署名を検証する (改ざん、時計のずれ、再送を拒否する)
+	if err := userHighScoreUseCase.signatureVerifier.Verify(ctx, ranking, command); err != nil {
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] invalid signature: %v", err)
+		return nil, err
+	}
+
+	// ランキングのスコアの型に応じてスコアを変換し、スコアの制約を検証する
+	score, err := ranking.ParseScore(command.Score)
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] invalid score: %v", err)
+		return nil, err
+	}
 
 	// ユーザーの存在チェック
-	_, err = userHighScoreUseCase.userRepository.FindByID(ctx, userID)
+	user, err := userHighScoreUseCase.userRepository.FindByID(ctx, userID)
 
 	// エラーハンドリング
 	if err != nil {
 		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to fetch user: %v", err)
-		return err
+		return nil, err
 	}
 
-	// 当該ユーザーが存在しない場合は更新できない
+	// 現在のハイスコア (スコアの妥当性のルールの評価に使う)
+	currentHighScore, err := userHighScoreUseCase.userHighScoreRepository.Find(ctx, rankingID, userID)
 
-	// ユーザーのハイスコアを取得
-	// userHighScore, err := userHighScoreUseCase.userHighScoreRepository.Find(ctx, rankingID, userID)
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to fetch user high score: %v", err)
+		return nil, err
+	}
+
+	// 送信されたスコアを送信履歴に追加する
+	// 疑わしいスコアは審査待ちにし、シャドウバンしたユーザーの送信はルールを評価せずに反映しない
+	submission := domain.NewScoreSubmission(rankingID, userID, score, command.Client)
+	if user.IsShadowBanned() {
+		submission.ShadowBan()
+	} else {
+		// スコアの妥当性のルールを評価する (前回の送信と比べるため、送信履歴に追加する前に評価する)
+		flaggedRules, err := userHighScoreUseCase.evaluatePlausibility(ctx, ScoreCandidate{
+			Ranking:          ranking,
+			UserID:           userID,
+			Score:            score,
+			CurrentHighScore: currentHighScore,
+			SubmittedAt:      now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(flaggedRules) > 0 {
+			submission.HoldForReview(flaggedRules)
+		}
+	}
+	submission, err = userHighScoreUseCase.scoreSubmissionRepository.Create(ctx, submission)
 
 	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to store score submission: %v", err)
+		return nil, err
+	}
 
-	// スコアがない場合、またはハイスコアを更新した場合は永続化する
+	// 審査待ちにした場合とシャドウバンしたユーザーの場合はハイスコアを保存しない
+	update := &userHighScoreUpdate{ranking: ranking, user: user, submission: submission, currentHighScore: currentHighScore, seasonStarted: seasonStarted}
+	if submission.IsWithheld() {
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Withheld score: ranking_id=%d, user_id=%d, status=%s, rules=%v", rankingID, userID, submission.Status, submission.FlaggedRules)
+		return update, nil
+	}
+
+	// ランキングの並び順で既存のハイスコアを上回る場合のみ保存する
+	upsertResult, err := userHighScoreUseCase.userHighScoreRepository.Upsert(ctx, rankingID, userID, score, ranking.SortOrder)
 
 	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to store user high score: %v", err)
+		return nil, err
+	}
 
-	return nil
+	// ランキング、ユーザーと保存結果を返す
+	update.upsertResult = upsertResult
+	return update, nil
+}
+
+// 終了したシーズンをアーカイブして次のシーズンに進め、最新のランキングを返す
+// 他の処理が先に進めていた場合はアーカイブせずに最新のランキングを返す
+func (userHighScoreUseCase *UserHighScoreUseCase) rotateSeason(ctx context.Context, ranking *domain.Ranking, now time.Time) (*domain.Ranking, error) {
+	_, err := userHighScoreUseCase.seasonRepository.Archive(ctx, ranking, ranking.NextSeason(now))
+	if err != nil && !errors.Is(err, domain.ErrConflict) {
+		return nil, err
+	}
+	return userHighScoreUseCase.rankingRepository.FindByID(ctx, ranking.ID)
+}
+
+// スコアの妥当性のルールを評価し、疑わしいと判定したルールの名前を返す
+func (userHighScoreUseCase *UserHighScoreUseCase) evaluatePlausibility(ctx context.Context, candidate ScoreCandidate) ([]string, error) {
+	var flaggedRules []string
+	for _, rule := range userHighScoreUseCase.plausibilityRules {
+		suspicious, err := rule.IsSuspicious(ctx, candidate)
+
+		// エラーハンドリング
+		if err != nil {
+			log.Printf("[UserHighScoreUseCase.UpdateUserHighScore] Failed to evaluate plausibility rule %s: %v", rule.Name(), err)
+			return nil, err
+		}
+		if suspicious {
+			flaggedRules = append(flaggedRules, rule.Name())
+		}
+	}
+	return flaggedRules, nil
 }
@@ -0,0 +1,330 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ハイスコアは上回った場合のみ更新され、登録結果とランクが返る
+func TestUpdateUserHighScore(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+
+	// 初回は新規登録
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "100"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScoreCreated), result.Outcome)
+	assert.Nil(t, result.PreviousScore)
+	assert.Equal(t, "100", result.NewScore.String())
+	assert.Equal(t, 1, result.Rank)
+
+	// 同点は先に登録したユーザーが上位
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "100"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Rank)
+
+	// 既存のハイスコア以下では更新しない
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "50"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScoreUnchanged), result.Outcome)
+	assert.Equal(t, "100", result.PreviousScore.String())
+	assert.Equal(t, "100", result.NewScore.String())
+
+	// 上回った場合は更新して順位が上がる
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "150"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScoreImproved), result.Outcome)
+	assert.Equal(t, "100", result.PreviousScore.String())
+	assert.Equal(t, "150", result.NewScore.String())
+	assert.Equal(t, 1, result.Rank)
+}
+
+// 存在しないランキングやユーザーにはハイスコアを登録できない
+func TestUpdateUserHighScoreNotFound(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	user, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID + 1, UserID: user.ID, Score: "100"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID + 1, Score: "100"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// スコアが小さいほど上位のランキングでは低いスコアでのみ更新される
+func TestUpdateUserHighScoreAscending(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "speedrun", SortOrder: "asc"})
+	require.NoError(t, err)
+	assert.Equal(t, "asc", ranking.SortOrder)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "90"})
+	require.NoError(t, err)
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "120"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Rank)
+
+	// 大きいスコアでは更新しない
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "150"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScoreUnchanged), result.Outcome)
+	assert.Equal(t, "120", result.NewScore.String())
+
+	// 小さいスコアで更新して順位が上がる
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "80"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScoreImproved), result.Outcome)
+	assert.Equal(t, 1, result.Rank)
+}
+
+// 固定小数点数のランキングでは桁数どおりに丸めずに比較・表示する
+func TestUpdateUserHighScoreDecimal(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "accuracy", ScoreType: "decimal", ScoreScale: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "decimal", ranking.ScoreType)
+	assert.Equal(t, 3, ranking.ScoreScale)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "0.3"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.300", result.NewScore.String())
+
+	// 桁数を超える値は丸めずにエラーにする
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "0.3001"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	// 最小単位の差でも比較できる
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "0.301"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScoreImproved), result.Outcome)
+	assert.Equal(t, "0.300", result.PreviousScore.String())
+	assert.Equal(t, "0.301", result.NewScore.String())
+}
+
+// 所要時間のランキングは整数のミリ秒のみ登録できる
+func TestUpdateUserHighScoreDuration(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "speedrun", SortOrder: "asc", ScoreType: "duration"})
+	require.NoError(t, err)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "83456"})
+	require.NoError(t, err)
+	assert.Equal(t, "83456", result.NewScore.String())
+
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "83.5"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "-1"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	// 固定小数点数以外に桁数は指定できない
+	_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "timed", SortOrder: "asc", ScoreType: "duration", ScoreScale: 3})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+// ランキングのスコアの制約を満たさないスコアは違反した制約のエラーコードで登録できない
+func TestUpdateUserHighScoreRules(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "accuracy", ScoreType: "decimal", ScoreScale: 2, MinScore: "0", MaxScore: "100", ScoreStep: "0.25"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.00", ranking.MinScore.String())
+	assert.Equal(t, "100.00", ranking.MaxScore.String())
+	assert.Equal(t, "0.25", ranking.ScoreStep.String())
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+
+	for score, code := range map[string]string{
+		"-0.25":  "score_below_min",
+		"100.25": "score_above_max",
+		"12.3":   "score_step_mismatch",
+	} {
+		_, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: score})
+		var domainError *domain.Error
+		require.ErrorAs(t, err, &domainError, score)
+		assert.Equal(t, code, domainError.Code, score)
+	}
+
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "99.75"})
+	require.NoError(t, err)
+	assert.Equal(t, "99.75", result.NewScore.String())
+
+	// 制約は作成時に検証し、未指定の場合は制約なし
+	_, err = u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "broken", MinScore: "10", MaxScore: "5"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	unbounded, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "unbounded"})
+	require.NoError(t, err)
+	assert.Nil(t, unbounded.MinScore)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: unbounded.ID, UserID: alice.ID, Score: "-100"})
+	require.NoError(t, err)
+}
+
+// ハイスコアを削除するとランキングから消え、送信履歴は残る
+func TestDeleteUserHighScore(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	user, err := u.user.CreateUser(ctx, "cheater")
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID, Score: "999999"})
+	require.NoError(t, err)
+
+	require.NoError(t, u.userHighScore.DeleteUserHighScore(ctx, ranking.ID, user.ID))
+	_, err = u.queryService.FetchUserStanding(ctx, ranking.ID, user.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	page, err := usecase.NewIDPageRequest(0, "", 0)
+	require.NoError(t, err)
+	submissions, err := u.submission.GetScoreSubmissions(ctx, ranking.ID, user.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	assert.Len(t, submissions.Items, 1)
+
+	// 削除後は低いスコアでも登録できる
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID, Score: "10"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Rank)
+
+	// 登録されていないハイスコアはNotFound
+	assert.ErrorIs(t, u.userHighScore.DeleteUserHighScore(ctx, ranking.ID, user.ID+1), domain.ErrNotFound)
+}
+
+// スコアの妥当性のルールで疑わしいと判定したスコアは審査待ちになり、ハイスコアと順位表は変わらない
+func TestUpdateUserHighScorePlausibility(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	ratio := 2.0
+	_, err = u.ranking.UpdatePlausibilityRules(ctx, ranking.ID, usecase.UpdatePlausibilityRulesCommand{MaxImprovementRatio: &ratio, ScoreCap: "1000"})
+	require.NoError(t, err)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "100"})
+	require.NoError(t, err)
+
+	// 現在のハイスコアの2倍を超える改善は審査待ち (現在のランクを返す)
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "201"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScorePendingReview), result.Outcome)
+	assert.Equal(t, "100", result.PreviousScore.String())
+	assert.Equal(t, "201", result.NewScore.String())
+	assert.Equal(t, 1, result.Rank)
+
+	// 2倍以内の改善は受け付ける
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "200"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScoreImproved), result.Outcome)
+
+	// 上限を超えるスコアは初回でも審査待ち (ハイスコアが未登録のためランクは返らない)
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "1001"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScorePendingReview), result.Outcome)
+	assert.Nil(t, result.PreviousScore)
+	assert.Zero(t, result.Rank)
+	_, err = u.queryService.FetchUserStanding(ctx, ranking.ID, bob.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// 送信履歴には審査待ちの状態と判定したルールが残る
+	page, err := usecase.NewIDPageRequest(0, "", 10)
+	require.NoError(t, err)
+	submissions, err := u.submission.GetScoreSubmissions(ctx, ranking.ID, alice.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	require.Len(t, submissions.Items, 3)
+	assert.Equal(t, string(domain.SubmissionPendingReview), submissions.Items[1].Status)
+	assert.Equal(t, []string{domain.PlausibilityRuleImprovementRatio}, submissions.Items[1].FlaggedRules)
+	assert.Equal(t, string(domain.SubmissionAccepted), submissions.Items[2].Status)
+	assert.Empty(t, submissions.Items[2].FlaggedRules)
+	submissions, err = u.submission.GetScoreSubmissions(ctx, ranking.ID, bob.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	require.Len(t, submissions.Items, 1)
+	assert.Equal(t, []string{domain.PlausibilityRuleScoreCap}, submissions.Items[0].FlaggedRules)
+}
+
+// 送信間隔と分布から外れたスコアのルールも審査待ちにする
+func TestUpdateUserHighScorePlausibilityIntervalAndZScore(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+
+	// 分布の評価に必要な件数のハイスコアを登録する
+	for i := 0; i < domain.MinScoreStatsCount; i++ {
+		user, err := u.user.CreateUser(ctx, fmt.Sprintf("user%d", i))
+		require.NoError(t, err)
+		_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: user.ID, Score: strconv.Itoa(100 + i)})
+		require.NoError(t, err)
+	}
+	interval := int64(time.Hour / time.Millisecond)
+	maxZScore := 3.0
+	_, err = u.ranking.UpdatePlausibilityRules(ctx, ranking.ID, usecase.UpdatePlausibilityRulesCommand{MinSubmissionIntervalMs: &interval, MaxZScore: &maxZScore})
+	require.NoError(t, err)
+
+	// 分布から大きく外れたスコア
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	result, err := u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "1000"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScorePendingReview), result.Outcome)
+
+	// 分布内のスコアでも、前回の送信から間隔が短い場合は審査待ち
+	result, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "105"})
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.HighScorePendingReview), result.Outcome)
+
+	page, err := usecase.NewIDPageRequest(0, "", 10)
+	require.NoError(t, err)
+	submissions, err := u.submission.GetScoreSubmissions(ctx, ranking.ID, alice.ID, domain.TimeRange{}, page)
+	require.NoError(t, err)
+	require.Len(t, submissions.Items, 2)
+	assert.Equal(t, []string{domain.PlausibilityRuleZScore}, submissions.Items[0].FlaggedRules)
+	assert.Equal(t, []string{domain.PlausibilityRuleSubmissionInterval}, submissions.Items[1].FlaggedRules)
+}
@@ -1,9 +1,12 @@
 package usecase
 
+import "time"
+
 // ユーザーランク
 type UserRankDto struct {
-	UserID   int    `json:"user_id"`
-	UserName string `json:"user_name"`
-	Rank     int    `json:"rank"`
-	Score    int    `json:"score"`
+	UserID     int       `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	Rank       int       `json:"rank"`
+	Score      ScoreDto  `json:"score"`
+	AchievedAt time.Time `json:"achieved_at"`
 }
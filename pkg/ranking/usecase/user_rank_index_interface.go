@@ -0,0 +1,26 @@
+package usecase
+
+import "practice-go-game-ranking/pkg/ranking/domain"
+
+// ランク付けのインデックス (インターフェース)
+// ハイスコアの保存をコミット後に反映し、インデックスを使うクエリサービスとデータベースの内容を揃える
+type UserRankIndexInterface interface {
+	// 保存したハイスコアをランキングの並び順で反映する
+	// seasonはハイスコアを保存した時点のランキングのシーズンの番号 (周期なしの場合は0)。進めた後のシーズンより前のハイスコアは反映しない
+	Apply(highScore domain.UserHighScore, season int, sortOrder domain.SortOrder, userName string)
+
+	// シーズンの終了でユーザーハイスコアを空にしたランキングを次のシーズンの空のランキングにする
+	StartSeason(rankingID int, season int, sortOrder domain.SortOrder)
+
+	// 削除したランキングを取り除く
+	Reset(rankingID int)
+
+	// 削除したユーザーハイスコアを取り除く
+	Remove(rankingID int, userID int)
+
+	// 論理削除したユーザーのハイスコアを全てのランキングから取り除く (復元するまで以降のApplyも反映しない)
+	RemoveUser(userID int)
+
+	// 復元したユーザーのハイスコアを再び反映する
+	RestoreUser(userID int)
+}
@@ -0,0 +1,12 @@
+package usecase
+
+// ユーザーの前後のユーザーランキング
+// ランキングの端では存在する分だけを返す (1位のユーザーには上位のユーザーランクは含まれない)
+type UserRankingAroundDto struct {
+	RankingID   int           `json:"ranking_id"`
+	RankingName string        `json:"ranking_name"`
+	UserID      int           `json:"user_id"`
+	Rank        int           `json:"rank"`
+	TotalUsers  int           `json:"total_users"`
+	UserRanks   []UserRankDto `json:"user_ranks"`
+}
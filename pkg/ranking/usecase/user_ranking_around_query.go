@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+const (
+	// 前後に取得する件数の既定値
+	DefaultUserRankingAroundCount = 5
+
+	// 前後に取得する件数の上限
+	MaxUserRankingAroundCount = 100
+)
+
+// ユーザーの前後のユーザーランキングのクエリ条件
+type UserRankingAroundQuery struct {
+	RankingID int
+	UserID    int
+
+	// 上位に取得する件数
+	Before int
+
+	// 下位に取得する件数
+	After int
+}
+
+// ユーザーの前後のユーザーランキングのクエリ条件を生成する (未指定の場合は既定値)
+func NewUserRankingAroundQuery(rankingID int, userID int, before *int, after *int) (UserRankingAroundQuery, error) {
+	query := UserRankingAroundQuery{
+		RankingID: rankingID,
+		UserID:    userID,
+		Before:    DefaultUserRankingAroundCount,
+		After:     DefaultUserRankingAroundCount,
+	}
+	if before != nil {
+		query.Before = *before
+	}
+	if after != nil {
+		query.After = *after
+	}
+
+	// 取得件数の範囲チェック
+	for _, count := range []int{query.Before, query.After} {
+		if count < 0 || count > MaxUserRankingAroundCount {
+			return UserRankingAroundQuery{}, domain.NewValidationError("invalid_around_count", fmt.Sprintf("前後に取得する件数は0から%dの範囲で指定してください。入力された件数: %d", MaxUserRankingAroundCount, count))
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 前後に取得する件数の既定値と範囲
+func TestNewUserRankingAroundQuery(t *testing.T) {
+	// 未指定の場合は既定値
+	query, err := NewUserRankingAroundQuery(1, 2, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultUserRankingAroundCount, query.Before)
+	assert.Equal(t, DefaultUserRankingAroundCount, query.After)
+
+	// 0件は指定できる
+	zero := 0
+	query, err = NewUserRankingAroundQuery(1, 2, &zero, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, query.Before)
+
+	// 上限を超える件数はNG
+	tooMany := MaxUserRankingAroundCount + 1
+	_, err = NewUserRankingAroundQuery(1, 2, nil, &tooMany)
+	assert.Error(t, err, "Expected error for too many entries")
+}
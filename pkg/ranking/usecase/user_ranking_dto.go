@@ -5,4 +5,6 @@ type UserRankingDto struct {
 	RankingID   int           `json:"ranking_id"`
 	RankingName string        `json:"ranking_name"`
 	UserRanks   []UserRankDto `json:"user_ranks"`
+	HasNext     bool          `json:"-"`
+	HasPrev     bool          `json:"-"`
 }
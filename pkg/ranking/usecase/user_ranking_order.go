@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"fmt"
+	"practice-go-game-ranking/pkg/ranking/domain"
+)
+
+// ユーザーランキングの並び順
+type UserRankingOrder string
+
+const (
+	// 上位から順に並べる
+	UserRankingOrderRankAsc UserRankingOrder = "rank_asc"
+
+	// 下位から順に並べる
+	UserRankingOrderRankDesc UserRankingOrder = "rank_desc"
+)
+
+// 並び順を生成する (未指定の場合は上位から順に並べる)
+func NewUserRankingOrder(orderBy string) (UserRankingOrder, error) {
+	switch UserRankingOrder(orderBy) {
+	case "", UserRankingOrderRankAsc:
+		return UserRankingOrderRankAsc, nil
+	case UserRankingOrderRankDesc:
+		return UserRankingOrderRankDesc, nil
+	default:
+		return "", domain.NewValidationError("invalid_order_by", fmt.Sprintf("並び順は %s か %s を指定してください。入力された並び順: %q", UserRankingOrderRankAsc, UserRankingOrderRankDesc, orderBy))
+	}
+}
@@ -1,7 +1,54 @@
 package usecase
 
+import (
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"time"
+)
+
 // ユーザーランキングのクエリ条件
 type UserRankingQuery struct {
 	RankingID int
-	OrderBy   string
+	OrderBy   UserRankingOrder
+	Limit     int
+
+	// 基準となるハイスコアの位置 (nilの場合は先頭から取得する)
+	Cursor *UserRankingCursor
+}
+
+// ユーザーランキングのカーソル
+// ランク付けと同じ (スコア, 登録日時, ユーザーID) の組で位置を表すため、ページ間でスコアが更新されても重複や欠落が起きない
+type UserRankingCursor struct {
+	Score     domain.Score
+	Timestamp time.Time
+	UserID    int
+	Direction domain.PageDirection
+}
+
+// ユーザーランキングのクエリ条件を生成する
+func NewUserRankingQuery(rankingID int, orderBy string, limit int, cursor *UserRankingCursor) (UserRankingQuery, error) {
+	// 並び順
+	order, err := NewUserRankingOrder(orderBy)
+	if err != nil {
+		return UserRankingQuery{}, err
+	}
+
+	// 取得件数
+	limit, err = newPageLimit(limit)
+	if err != nil {
+		return UserRankingQuery{}, err
+	}
+
+	return UserRankingQuery{
+		RankingID: rankingID,
+		OrderBy:   order,
+		Limit:     limit,
+		Cursor:    cursor,
+	}, nil
+}
+
+// 上位から下位に向かって取得するかを判定する
+// 上位から並べて後ろのページを取得する場合と、下位から並べて前のページを取得する場合が該当する
+func (query UserRankingQuery) FetchesDownward() bool {
+	backward := query.Cursor != nil && query.Cursor.Direction == domain.PagePrev
+	return (query.OrderBy == UserRankingOrderRankAsc) != backward
 }
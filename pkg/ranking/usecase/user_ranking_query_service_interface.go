@@ -6,4 +6,13 @@ import "context"
 type UserRankingQueryServiceInterface interface {
 	// ユーザーランキングを取得する
 	FetchUserRanking(ctx context.Context, query UserRankingQuery) (*UserRankingDto, error)
+
+	// ユーザーの前後のユーザーランキングを取得する (ハイスコアが未登録の場合はErrNotFound)
+	FetchUserRankingAround(ctx context.Context, query UserRankingAroundQuery) (*UserRankingAroundDto, error)
+
+	// ランキングにおけるユーザーの現在のランクを取得する (ハイスコアが未登録の場合はErrNotFound)
+	FetchUserRank(ctx context.Context, rankingID int, userID int) (*UserRankDto, error)
+
+	// ランキングにおけるユーザーのランクと参加人数、パーセンタイルを取得する (ハイスコアが未登録の場合はErrNotFound)
+	FetchUserStanding(ctx context.Context, rankingID int, userID int) (*UserStandingDto, error)
 }
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 並び順と取得件数の既定値・ホワイトリスト
+func TestNewUserRankingQuery(t *testing.T) {
+	// 未指定の場合は上位から既定件数
+	query, err := NewUserRankingQuery(1, "", 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, UserRankingOrderRankAsc, query.OrderBy)
+	assert.Equal(t, DefaultPageLimit, query.Limit)
+
+	// 下位から
+	query, err = NewUserRankingQuery(1, "rank_desc", 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, UserRankingOrderRankDesc, query.OrderBy)
+	assert.Equal(t, 10, query.Limit)
+
+	// ホワイトリスト外の並び順はNG
+	_, err = NewUserRankingQuery(1, "score; DROP TABLE users", 10, nil)
+	assert.Error(t, err, "Expected error for unknown order_by")
+
+	// 上限を超える件数はNG
+	_, err = NewUserRankingQuery(1, "", MaxPageLimit+1, nil)
+	assert.Error(t, err, "Expected error for too large limit")
+}
+
+// 並び順とページの方向から取得する向きを判定する
+func TestUserRankingQueryFetchesDownward(t *testing.T) {
+	next := &UserRankingCursor{Direction: domain.PageNext}
+	prev := &UserRankingCursor{Direction: domain.PagePrev}
+
+	assert.True(t, UserRankingQuery{OrderBy: UserRankingOrderRankAsc}.FetchesDownward())
+	assert.True(t, UserRankingQuery{OrderBy: UserRankingOrderRankAsc, Cursor: next}.FetchesDownward())
+	assert.False(t, UserRankingQuery{OrderBy: UserRankingOrderRankAsc, Cursor: prev}.FetchesDownward())
+	assert.False(t, UserRankingQuery{OrderBy: UserRankingOrderRankDesc}.FetchesDownward())
+	assert.False(t, UserRankingQuery{OrderBy: UserRankingOrderRankDesc, Cursor: next}.FetchesDownward())
+	assert.True(t, UserRankingQuery{OrderBy: UserRankingOrderRankDesc, Cursor: prev}.FetchesDownward())
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"math"
+	"time"
+)
+
+// ランキングにおけるユーザーの順位情報
+type UserStandingDto struct {
+	RankingID  int       `json:"ranking_id"`
+	UserID     int       `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	Rank       int       `json:"rank"`
+	Score      ScoreDto  `json:"score"`
+	AchievedAt time.Time `json:"achieved_at"`
+
+	// ハイスコアを登録しているユーザー数
+	TotalUsers int `json:"total_users"`
+
+	// 自分より下位のユーザーの割合 (1位は100、最下位は0、1人だけの場合は100)
+	Percentile float64 `json:"percentile"`
+}
+
+// ランクと参加人数からパーセンタイルを計算する (小数第2位まで)
+func CalculatePercentile(rank int, totalUsers int) float64 {
+	if totalUsers <= 1 {
+		return 100
+	}
+	percentile := float64(totalUsers-rank) / float64(totalUsers-1) * 100
+	return math.Round(percentile*100) / 100
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// パーセンタイルの境界値
+func TestCalculatePercentile(t *testing.T) {
+	// 1人だけの場合は100
+	assert.Equal(t, 100.0, CalculatePercentile(1, 1))
+
+	// 1位は100、最下位は0
+	assert.Equal(t, 100.0, CalculatePercentile(1, 5))
+	assert.Equal(t, 0.0, CalculatePercentile(5, 5))
+
+	// 小数第2位までに丸める
+	assert.Equal(t, 33.33, CalculatePercentile(3, 4))
+}
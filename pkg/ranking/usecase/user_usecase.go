@@ -2,45 +2,58 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"log"
 	"practice-go-game-ranking/pkg/ranking/domain"
+	"sync"
 )
 
 // ユーザーユースケース
 type UserUseCase struct {
-	userRepository domain.UserRepositoryInterface
+	userRepository          domain.UserRepositoryInterface
+	rankingRepository       domain.RankingRepositoryInterface
+	userHighScoreRepository domain.UserHighScoreRepositoryInterface
+	userRankIndex           UserRankIndexInterface
+	transactionManager      TransactionManagerInterface
+
+	// ユーザーの論理削除と復元のランク付けのインデックスへの反映のロック
+	rankIndexMu sync.Mutex
 }
 
-// ユースケースを生成する
-func NewUserUseCase(r domain.UserRepositoryInterface) *UserUseCase {
+// ユースケースを生成する (ランク付けのインデックスを使わない場合はuserRankIndexにnilを渡す)
+func NewUserUseCase(userRepo domain.UserRepositoryInterface, rankingRepo domain.RankingRepositoryInterface, userHighScoreRepo domain.UserHighScoreRepositoryInterface, userRankIndex UserRankIndexInterface, tm TransactionManagerInterface) *UserUseCase {
 	return &UserUseCase{
-		userRepository: r,
+		userRepository:          userRepo,
+		rankingRepository:       rankingRepo,
+		userHighScoreRepository: userHighScoreRepo,
+		userRankIndex:           userRankIndex,
+		transactionManager:      tm,
 	}
 }
 
 // ユーザー一覧を取得する
-func (userUseCase *UserUseCase) GetUsers(ctx context.Context) ([]UserDto, error) {
+func (userUseCase *UserUseCase) GetUsers(ctx context.Context, page domain.IDPageRequest) (*PageDto[UserDto], error) {
 	// ユーザー一覧をリポジトリから取得する
-	users, err := userUseCase.userRepository.FindAll(ctx)
+	users, err := userUseCase.userRepository.FindAll(ctx, page)
 	if err != nil {
 		log.Printf("[UserUseCase.GetUsers] Failed to fetch users: %v", err)
 		return nil, err
 	}
 
 	// ユースケース層の構造体にマッピング (スライスの容量を事前に確保)
-	userDtos := make([]UserDto, 0, len(users))
-	for _, u := range users {
+	userDtos := make([]UserDto, 0, len(users.Items))
+	for i := range users.Items {
 		// ユーザーDTOにマッピング
-		userDtos = append(userDtos, UserDto{
-			ID:        u.ID,
-			Name:      u.Name.Value,
-			CreatedAt: u.CreatedAt,
-			UpdatedAt: u.UpdatedAt,
-		})
+		userDtos = append(userDtos, *toUserDto(&users.Items[i]))
 	}
 
 	// ユースケースのユーザーを返す
-	return userDtos, nil
+	hasNext, hasPrev := PageLinks(users.HasMore, page.CursorID > 0, page.Direction)
+	return &PageDto[UserDto]{
+		Items:   userDtos,
+		HasNext: hasNext,
+		HasPrev: hasPrev,
+	}, nil
 }
 
 // ユーザーを新規登録する
@@ -54,8 +67,12 @@ func (userUseCase *UserUseCase) CreateUser(ctx context.Context, name string) (*U
 		return nil, err
 	}
 
-	// リポジトリを使ってユーザーを登録する
-	user, err := userUseCase.userRepository.Create(ctx, userName)
+	// トランザクション内でリポジトリを使ってユーザーを登録する
+	var user *domain.User
+	err = userUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		user, err = userUseCase.userRepository.Create(ctx, userName)
+		return err
+	})
 
 	// エラーハンドリング
 	if err != nil {
@@ -64,10 +81,103 @@ func (userUseCase *UserUseCase) CreateUser(ctx context.Context, name string) (*U
 	}
 
 	// ユースケースのユーザーを返す
+	return toUserDto(user), nil
+}
+
+// ユーザーを論理削除する (ハイスコアは残したまま全てのランキングから除外する)
+func (userUseCase *UserUseCase) DeleteUser(ctx context.Context, id int) error {
+	// トランザクション内でリポジトリを使ってユーザーを論理削除する
+	err := userUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		return userUseCase.userRepository.Delete(ctx, id)
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserUseCase.DeleteUser] Failed to delete user: %v", err)
+		return err
+	}
+
+	// ランク付けのインデックスからもユーザーのハイスコアを取り除く
+	if userUseCase.userRankIndex != nil {
+		if err := userUseCase.syncUserRankIndex(ctx, id); err != nil {
+			log.Printf("[UserUseCase.DeleteUser] Failed to sync rank index: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// 論理削除したユーザーを復元する (削除されていないユーザーの場合はそのまま返す)
+func (userUseCase *UserUseCase) RestoreUser(ctx context.Context, id int) (*UserDto, error) {
+	// トランザクション内でリポジトリを使ってユーザーを復元する
+	var user *domain.User
+	err := userUseCase.transactionManager.RunInTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = userUseCase.userRepository.Restore(ctx, id)
+		return err
+	})
+
+	// エラーハンドリング
+	if err != nil {
+		log.Printf("[UserUseCase.RestoreUser] Failed to restore user: %v", err)
+		return nil, err
+	}
+
+	// 残していたハイスコアをランク付けのインデックスに戻す
+	if userUseCase.userRankIndex != nil {
+		if err := userUseCase.syncUserRankIndex(ctx, id); err != nil {
+			log.Printf("[UserUseCase.RestoreUser] Failed to sync rank index: %v", err)
+			return nil, err
+		}
+	}
+
+	// ユースケースのユーザーを返す
+	return toUserDto(user), nil
+}
+
+// ユーザーの論理削除と復元をランク付けのインデックスに反映する
+// 削除と復元のコミット後の反映が前後しても最後にコミットした状態に揃うよう、反映を直列にして反映時点のユーザーの状態を読み直す
+func (userUseCase *UserUseCase) syncUserRankIndex(ctx context.Context, id int) error {
+	userUseCase.rankIndexMu.Lock()
+	defer userUseCase.rankIndexMu.Unlock()
+
+	// 削除済みの場合は全てのランキングから取り除く
+	user, err := userUseCase.userRepository.FindByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		userUseCase.userRankIndex.RemoveUser(id)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// 削除されていない場合は残していたハイスコアを反映し直す
+	userUseCase.userRankIndex.RestoreUser(id)
+	return userUseCase.applyUserHighScores(ctx, user)
+}
+
+// ユーザーのハイスコアをランキングの並び順でランク付けのインデックスに反映する
+func (userUseCase *UserUseCase) applyUserHighScores(ctx context.Context, user *domain.User) error {
+	highScores, err := userUseCase.userHighScoreRepository.FindAllByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, highScore := range highScores {
+		ranking, err := userUseCase.rankingRepository.FindByID(ctx, highScore.RankingID)
+		if err != nil {
+			return err
+		}
+		userUseCase.userRankIndex.Apply(highScore, ranking.SeasonNumber(), ranking.SortOrder, user.Name.Value)
+	}
+	return nil
+}
+
+// ユーザーDTOにマッピングする
+func toUserDto(user *domain.User) *UserDto {
 	return &UserDto{
 		ID:        user.ID,
 		Name:      user.Name.Value,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
-	}, nil
+	}
 }
@@ -0,0 +1,70 @@
+package usecase_test
+
+import (
+	"context"
+	"practice-go-game-ranking/pkg/ranking/domain"
+	"practice-go-game-ranking/pkg/ranking/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 論理削除したユーザーはランキングから消え、復元すると元のハイスコアで戻る
+func TestDeleteAndRestoreUser(t *testing.T) {
+	ctx := context.Background()
+	u := newTestUseCases()
+	game, err := u.game.CreateGame(ctx, "game")
+	require.NoError(t, err)
+	ranking, err := u.ranking.CreateRanking(ctx, usecase.CreateRankingCommand{GameID: game.ID, Name: "ranking"})
+	require.NoError(t, err)
+	alice, err := u.user.CreateUser(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := u.user.CreateUser(ctx, "bob")
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: alice.ID, Score: "100"})
+	require.NoError(t, err)
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "200"})
+	require.NoError(t, err)
+
+	// 論理削除するとユーザー一覧とランキングから消える
+	require.NoError(t, u.user.DeleteUser(ctx, bob.ID))
+	page, err := usecase.NewIDPageRequest(0, "", 0)
+	require.NoError(t, err)
+	users, err := u.user.GetUsers(ctx, page)
+	require.NoError(t, err)
+	require.Len(t, users.Items, 1)
+	assert.Equal(t, alice.ID, users.Items[0].ID)
+	query, err := usecase.NewUserRankingQuery(ranking.ID, "", 0, nil)
+	require.NoError(t, err)
+	userRanking, err := u.queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, userRanking.UserRanks, 1)
+	assert.Equal(t, alice.ID, userRanking.UserRanks[0].UserID)
+	assert.Equal(t, 1, userRanking.UserRanks[0].Rank)
+	standing, err := u.queryService.FetchUserStanding(ctx, ranking.ID, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, standing.TotalUsers)
+	_, err = u.queryService.FetchUserStanding(ctx, ranking.ID, bob.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// 削除済みのユーザーはスコアを登録できず、再度の削除はNotFound
+	_, err = u.userHighScore.UpdateUserHighScore(ctx, usecase.UpdateUserHighScoreCommand{RankingID: ranking.ID, UserID: bob.ID, Score: "300"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, u.user.DeleteUser(ctx, bob.ID), domain.ErrNotFound)
+
+	// 復元すると元のハイスコアでランキングに戻る (復元済みのユーザーの復元はそのまま返す)
+	restored, err := u.user.RestoreUser(ctx, bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", restored.Name)
+	_, err = u.user.RestoreUser(ctx, bob.ID)
+	require.NoError(t, err)
+	userRanking, err = u.queryService.FetchUserRanking(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, userRanking.UserRanks, 2)
+	assert.Equal(t, bob.ID, userRanking.UserRanks[0].UserID)
+
+	// 存在しないユーザーはNotFound
+	_, err = u.user.RestoreUser(ctx, bob.ID+1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}